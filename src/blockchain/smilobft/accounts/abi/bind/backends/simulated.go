@@ -123,6 +123,7 @@ func (b *SimulatedBackend) rollback() {
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	b.pendingState.SetSmiloPayCurves(b.config.SmiloPay)
 }
 
 // CodeAt returns the code associated with a certain account in the blockchain.
@@ -352,6 +353,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	b.pendingState.SetSmiloPayCurves(b.config.SmiloPay)
 	return nil
 }
 
@@ -437,6 +439,7 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	b.pendingState.SetSmiloPayCurves(b.config.SmiloPay)
 
 	return nil
 }
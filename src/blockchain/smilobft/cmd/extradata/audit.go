@@ -0,0 +1,268 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/smilobftcore"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+var (
+	// errUnauthorizedProposer is returned if the seal of a header is not signed by
+	// one of its fullnodes, errUnauthorized in the engine.
+	errUnauthorizedProposer = errors.New("proposer is not a fullnode")
+
+	// errNoCommittedSeals is returned if a header carries no committed seal,
+	// errEmptyCommittedSeals in the engine.
+	errNoCommittedSeals = errors.New("no committed seals")
+
+	// errForeignSeal is returned if a committed seal is not signed by one of the
+	// fullnodes of the header, errInvalidCommittedSeals in the engine.
+	errForeignSeal = errors.New("committed seal not signed by a fullnode")
+
+	// errDuplicateSeal is returned if a fullnode signed more than one committed
+	// seal, errInvalidCommittedSeals in the engine.
+	errDuplicateSeal = errors.New("duplicate committed seal")
+
+	// errNoQuorum is returned if too few fullnodes committed to a header,
+	// errInvalidCommittedSeals in the engine.
+	errNoQuorum = errors.New("committed seals below quorum")
+
+	// errUnprovenFullnodes is returned if the fullnodes recorded by a header are
+	// not the ones of its parent, nor follow from a vote, evidence or fullnode
+	// transition carried by the parent, errInvalidFullnodes in the engine.
+	errUnprovenFullnodes = errors.New("recorded fullnodes not proven by the parent")
+)
+
+// sealReport is the outcome of recovering the signer of a committed seal.
+type sealReport struct {
+	Signer common.Address `json:"signer"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// headerReport is the outcome of auditing the seals of a Sport header against
+// its fullnodes.
+type headerReport struct {
+	Number    uint64           `json:"number"`
+	Hash      common.Hash      `json:"hash"`
+	Fullnodes []common.Address `json:"fullnodes"` // Fullnodes allowed to seal the header, as proven by its parent
+	Proposer  common.Address   `json:"proposer"`
+	Seals     []sealReport     `json:"committedSeals"`
+	Valid     int              `json:"validSeals"`
+	Quorum    int              `json:"quorum"`
+	Warnings  []string         `json:"warnings,omitempty"`
+	Errors    []string         `json:"errors,omitempty"`
+}
+
+// failed returns whether the header would be rejected by the Sport engine.
+func (r *headerReport) failed() bool {
+	return len(r.Errors) > 0
+}
+
+func (r *headerReport) fail(err error) {
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// parentFullnodes returns the fullnode sets the parent proves for its child to
+// record, the way the Sport engine applies the parent to its snapshot: the
+// fullnodes taking over if the parent is an epoch checkpoint, otherwise the ones
+// it records without the offenders of the evidence it carries, and these with
+// the vote it casts applied, as votes are tallied over the whole epoch. Once
+// votes wait for epoch checkpoints, votes and evidence of other blocks never
+// change the fullnodes. The config may be nil if unknown.
+func parentFullnodes(parent *types.Header, config *params.ChainConfig) ([][]common.Address, error) {
+	extra, err := types.ExtractSportExtra(parent)
+	if err != nil {
+		return nil, err
+	}
+	if extra.NextFullnodes != nil {
+		return [][]common.Address{extra.NextFullnodes}, nil
+	}
+	if config != nil && config.Sport.IsEpochTransitions(parent.Number) {
+		return [][]common.Address{extra.Fullnodes}, nil
+	}
+	evicted := make(map[common.Address]bool)
+	for _, blob := range extra.Misbehaviours {
+		evidence := new(sport.Misbehaviour)
+		if err := rlp.DecodeBytes(blob, evidence); err != nil {
+			return nil, fmt.Errorf("invalid misbehaviour evidence: %v", err)
+		}
+		evicted[evidence.Offender] = true
+	}
+	var remaining []common.Address
+	for _, fullnode := range extra.Fullnodes {
+		if !evicted[fullnode] {
+			remaining = append(remaining, fullnode)
+		}
+	}
+	candidates := [][]common.Address{remaining}
+	if vote := parent.Coinbase; vote != (common.Address{}) {
+		var voted []common.Address
+		for _, fullnode := range remaining {
+			if fullnode != vote {
+				voted = append(voted, fullnode)
+			}
+		}
+		if parent.Nonce != (types.BlockNonce{}) && !evicted[vote] {
+			voted = append(voted, vote) // Authorization vote, drop votes are all zero
+		}
+		candidates = append(candidates, voted)
+	}
+	return candidates, nil
+}
+
+// proposer recovers the fullnode that proposed a header from its seal.
+func proposer(header *types.Header, seal []byte) (common.Address, error) {
+	filtered := types.SportFilteredHeader(header, false)
+	if filtered == nil {
+		return common.Address{}, types.ErrInvalidSportHeaderExtra
+	}
+	blob, err := rlp.EncodeToBytes(filtered)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return sport.GetSignatureAddress(crypto.Keccak256(blob), seal)
+}
+
+// quorum returns the number of committed seals a header needs. Up to the
+// 66% switch block, one seal less than two thirds of the fullnodes is enough.
+func quorum(fullnodes int, number *big.Int, sixtySixPercentBlock *big.Int) int {
+	approvers := int(math.Ceil(float64(2*fullnodes) / 3.0))
+	if sixtySixPercentBlock != nil && number.Cmp(sixtySixPercentBlock) <= 0 {
+		return approvers - 1
+	}
+	return approvers
+}
+
+// auditHeader checks the proposer and the committed seals of a header against
+// the fullnodes proven by its parent, the way the Sport engine verifies them
+// against the snapshot of its parent. If a registry contract governs the
+// fullnodes, those are the fullnodes the parent records. Otherwise the header
+// records them, and they must be the parent ones, or follow from the vote,
+// evidence or fullnode transition of the parent. The config may be nil if
+// unknown, in which case the fullnodes are assumed to be voted.
+func auditHeader(parent, header *types.Header, config *params.ChainConfig, sixtySixPercentBlock *big.Int) *headerReport {
+	report := &headerReport{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+	}
+	if parent.Hash() != header.ParentHash || parent.Number.Uint64()+1 != report.Number {
+		report.fail(fmt.Errorf("parent %d %x does not match", parent.Number, parent.Hash()))
+		return report
+	}
+	extra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		report.fail(fmt.Errorf("invalid extra-data: %v", err))
+		return report
+	}
+	fullnodes := extra.Fullnodes
+	if config != nil && config.Sport.FullnodeRegistry() != (common.Address{}) {
+		parentExtra, err := types.ExtractSportExtra(parent)
+		if err != nil {
+			report.fail(fmt.Errorf("invalid parent extra-data: %v", err))
+			return report
+		}
+		fullnodes = parentExtra.Fullnodes
+	} else {
+		candidates, err := parentFullnodes(parent, config)
+		if err != nil {
+			report.fail(fmt.Errorf("invalid parent extra-data: %v", err))
+			return report
+		}
+		switch {
+		case sameFullnodes(fullnodes, candidates[0]):
+		case len(candidates) > 1 && sameFullnodes(fullnodes, candidates[1]):
+			report.Warnings = append(report.Warnings, fmt.Sprintf("recorded fullnodes differ from the parent ones, the vote on %s passed", parent.Coinbase.Hex()))
+		default:
+			report.fail(errUnprovenFullnodes)
+		}
+	}
+	report.Fullnodes = fullnodes
+	report.Quorum = quorum(len(fullnodes), header.Number, sixtySixPercentBlock)
+
+	allowed := make(map[common.Address]bool)
+	for _, fullnode := range fullnodes {
+		allowed[fullnode] = true
+	}
+	// Check the proposer
+	if report.Proposer, err = proposer(header, extra.Seal); err != nil {
+		report.fail(fmt.Errorf("invalid seal: %v", err))
+	} else if !allowed[report.Proposer] {
+		report.fail(errUnauthorizedProposer)
+	}
+	// Check the committed seals, every fullnode may commit once
+	if len(extra.CommittedSeal) == 0 {
+		report.fail(errNoCommittedSeals)
+	}
+	committed := smilobftcore.PrepareCommittedSeal(report.Hash)
+	for _, seal := range extra.CommittedSeal {
+		signer, err := sport.GetSignatureAddress(committed, seal)
+		switch {
+		case err != nil:
+			report.Seals = append(report.Seals, sealReport{Error: err.Error()})
+			report.fail(fmt.Errorf("invalid committed seal: %v", err))
+		case !allowed[signer]:
+			err := errForeignSeal
+			if contains(fullnodes, signer) {
+				err = errDuplicateSeal
+			}
+			report.Seals = append(report.Seals, sealReport{Signer: signer, Error: err.Error()})
+			report.fail(fmt.Errorf("%v: %s", err, signer.Hex()))
+		default:
+			delete(allowed, signer)
+			report.Seals = append(report.Seals, sealReport{Signer: signer})
+			report.Valid++
+		}
+	}
+	if report.Valid < report.Quorum {
+		report.fail(fmt.Errorf("%v: %d of %d fullnodes, need %d", errNoQuorum, report.Valid, len(fullnodes), report.Quorum))
+	}
+	return report
+}
+
+// sameFullnodes returns whether two lists hold the same fullnodes.
+func sameFullnodes(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, addr := range a {
+		if !contains(b, addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []common.Address, addr common.Address) bool {
+	for _, item := range list {
+		if item == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport/smilobftcore"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+// testFullnodes creates n fullnode keys, sorted by address.
+func testFullnodes(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return crypto.PubkeyToAddress(keys[i].PublicKey).Hex() < crypto.PubkeyToAddress(keys[j].PublicKey).Hex()
+	})
+	addrs := make([]common.Address, n)
+	for i, key := range keys {
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return keys, addrs
+}
+
+// testHeader creates a Sport header recording the given fullnodes.
+func testHeader(t *testing.T, parent *types.Header, fullnodes []common.Address) *types.Header {
+	header := &types.Header{
+		Number:     big.NewInt(0),
+		Difficulty: big.NewInt(1),
+		MixDigest:  types.SportDigest,
+	}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+		header.Number = new(big.Int).Add(parent.Number, common.Big1)
+	}
+	setExtra(t, header, &types.SportExtra{Fullnodes: fullnodes, Seal: []byte{}, CommittedSeal: [][]byte{}})
+	return header
+}
+
+func setExtra(t *testing.T, header *types.Header, extra *types.SportExtra) {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatalf("failed to encode extra-data: %v", err)
+	}
+	header.Extra = append(make([]byte, types.SportExtraVanity), payload...)
+}
+
+// sealHeader signs the header as proposer, then commits to it with the given
+// fullnodes, like the Sport engine.
+func sealHeader(t *testing.T, header *types.Header, proposer *ecdsa.PrivateKey, committers ...*ecdsa.PrivateKey) {
+	blob, _ := rlp.EncodeToBytes(types.SportFilteredHeader(header, false))
+	seal, err := crypto.Sign(crypto.Keccak256(crypto.Keccak256(blob)), proposer)
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	extra, _ := types.ExtractSportExtra(header)
+	extra.Seal = seal
+	setExtra(t, header, extra)
+
+	committed := crypto.Keccak256(smilobftcore.PrepareCommittedSeal(header.Hash()))
+	for _, key := range committers {
+		seal, err := crypto.Sign(committed, key)
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+		extra.CommittedSeal = append(extra.CommittedSeal, seal)
+	}
+	setExtra(t, header, extra)
+}
+
+func TestAuditHeader(t *testing.T) {
+	keys, fullnodes := testFullnodes(4)
+	outsider, _ := crypto.GenerateKey()
+	genesis := testHeader(t, nil, fullnodes)
+
+	tests := []struct {
+		proposer   *ecdsa.PrivateKey
+		committers []*ecdsa.PrivateKey
+		legacy     *big.Int
+		err        error
+	}{
+		{keys[0], keys[:3], nil, nil},                                                    // Quorum of 3 out of 4
+		{keys[1], keys, nil, nil},                                                        // Every fullnode committed
+		{keys[0], keys[:2], nil, errNoQuorum},                                            // 2 out of 4 is not enough
+		{keys[0], keys[:2], big.NewInt(1), nil},                                          // Unless before the 66% switch
+		{keys[0], keys[:2], big.NewInt(0), errNoQuorum},                                  // But not after it
+		{outsider, keys[:3], nil, errUnauthorizedProposer},                               // Proposer not a fullnode
+		{keys[0], []*ecdsa.PrivateKey{keys[0], keys[1], outsider}, nil, errForeignSeal},  // Seal by a stranger
+		{keys[0], []*ecdsa.PrivateKey{keys[0], keys[1], keys[1]}, nil, errDuplicateSeal}, // Seal twice by a fullnode
+		{keys[0], nil, nil, errNoCommittedSeals},                                         // No seal at all
+	}
+	for i, tt := range tests {
+		header := testHeader(t, genesis, fullnodes)
+		sealHeader(t, header, tt.proposer, tt.committers...)
+
+		report := auditHeader(genesis, header, nil, tt.legacy)
+		if report.Proposer != crypto.PubkeyToAddress(tt.proposer.PublicKey) {
+			t.Errorf("test %d: proposer mismatch: have %x, want %x", i, report.Proposer, crypto.PubkeyToAddress(tt.proposer.PublicKey))
+		}
+		if len(report.Seals) != len(tt.committers) {
+			t.Errorf("test %d: committed seal count mismatch: have %d, want %d", i, len(report.Seals), len(tt.committers))
+		}
+		for j, seal := range report.Seals {
+			if want := crypto.PubkeyToAddress(tt.committers[j].PublicKey); seal.Signer != want {
+				t.Errorf("test %d: committed seal %d signer mismatch: have %x, want %x", i, j, seal.Signer, want)
+			}
+		}
+		if tt.err == nil && report.failed() {
+			t.Errorf("test %d: valid header failed: %v", i, report.Errors)
+		}
+		if tt.err != nil && !hasError(report, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, report.Errors, tt.err)
+		}
+	}
+}
+
+// Tests that the child of an epoch checkpoint is checked against the fullnodes
+// taking over.
+func TestAuditHeaderCheckpoint(t *testing.T) {
+	keys, fullnodes := testFullnodes(4)
+
+	checkpoint := testHeader(t, nil, fullnodes)
+	extra, _ := types.ExtractSportExtra(checkpoint)
+	extra.NextFullnodes = fullnodes[:3]
+	setExtra(t, checkpoint, extra)
+
+	header := testHeader(t, checkpoint, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[1])
+	if report := auditHeader(checkpoint, header, nil, nil); report.failed() || report.Quorum != 2 {
+		t.Errorf("header sealed by the new fullnodes failed: quorum %d, %v", report.Quorum, report.Errors)
+	}
+	header = testHeader(t, checkpoint, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[3])
+	if report := auditHeader(checkpoint, header, nil, nil); !hasError(report, errForeignSeal) {
+		t.Errorf("seal of a dropped fullnode accepted: %v", report.Errors)
+	}
+}
+
+// Tests that the child of a block in which a vote passed may record the
+// fullnodes with the vote applied, while other changes are rejected.
+func TestAuditHeaderVote(t *testing.T) {
+	keys, fullnodes := testFullnodes(4)
+	parent := testHeader(t, nil, fullnodes)
+
+	// Without a vote in the parent, the fullnodes can't change
+	header := testHeader(t, parent, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[1])
+	if report := auditHeader(parent, header, nil, nil); !hasError(report, errUnprovenFullnodes) {
+		t.Errorf("fullnodes not proven by the parent accepted: %v", report.Errors)
+	}
+
+	// The fourth fullnode was voted out in the parent
+	parent.Coinbase = fullnodes[3]
+	header = testHeader(t, parent, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[1])
+	report := auditHeader(parent, header, nil, nil)
+	if report.failed() || report.Quorum != 2 {
+		t.Errorf("header sealed by the remaining fullnodes failed: quorum %d, %v", report.Quorum, report.Errors)
+	}
+	if len(report.Warnings) == 0 {
+		t.Errorf("no warning on fullnodes differing from the parent")
+	}
+	header = testHeader(t, parent, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[1], keys[3])
+	if report := auditHeader(parent, header, nil, nil); !hasError(report, errForeignSeal) {
+		t.Errorf("seal of a voted out fullnode accepted: %v", report.Errors)
+	}
+	header = testHeader(t, parent, fullnodes[:3])
+	sealHeader(t, header, keys[3], keys[0], keys[1])
+	if report := auditHeader(parent, header, nil, nil); !hasError(report, errUnauthorizedProposer) {
+		t.Errorf("proposal of a voted out fullnode accepted: %v", report.Errors)
+	}
+	// A forged header listing other fullnodes than the vote proves is rejected
+	outsiders, others := testFullnodes(2)
+	header = testHeader(t, parent, others)
+	sealHeader(t, header, outsiders[0], outsiders...)
+	if report := auditHeader(parent, header, nil, nil); !hasError(report, errUnprovenFullnodes) {
+		t.Errorf("forged fullnodes accepted: %v", report.Errors)
+	}
+	// Once votes wait for epoch checkpoints, the vote changes nothing yet
+	config := &params.ChainConfig{Sport: &params.SportConfig{EpochTransitionsBlock: big.NewInt(0)}}
+	header = testHeader(t, parent, fullnodes[:3])
+	sealHeader(t, header, keys[0], keys[0], keys[1])
+	if report := auditHeader(parent, header, config, nil); !hasError(report, errUnprovenFullnodes) {
+		t.Errorf("vote applied before the epoch checkpoint: %v", report.Errors)
+	}
+}
+
+// Tests that headers of a chain governed by a registry contract are sealed by
+// the fullnodes recorded in their parent.
+func TestAuditHeaderContract(t *testing.T) {
+	keys, fullnodes := testFullnodes(4)
+	registry := common.Address{0xf}
+	config := &params.ChainConfig{Sport: &params.SportConfig{FullnodeContract: &registry}}
+	parent := testHeader(t, nil, fullnodes[:3])
+
+	// The fourth fullnode registered in the header
+	header := testHeader(t, parent, fullnodes)
+	sealHeader(t, header, keys[0], keys[0], keys[1])
+	if report := auditHeader(parent, header, config, nil); report.failed() || len(report.Fullnodes) != 3 {
+		t.Errorf("header sealed by the parent fullnodes failed: %d fullnodes, %v", len(report.Fullnodes), report.Errors)
+	}
+	header = testHeader(t, parent, fullnodes)
+	sealHeader(t, header, keys[3], keys[0], keys[1])
+	if report := auditHeader(parent, header, config, nil); !hasError(report, errUnauthorizedProposer) {
+		t.Errorf("proposal of a fullnode registered in the header accepted: %v", report.Errors)
+	}
+}
+
+func hasError(report *headerReport, err error) bool {
+	for _, e := range report.Errors {
+		if strings.HasPrefix(e, err.Error()) {
+			return true
+		}
+	}
+	return false
+}
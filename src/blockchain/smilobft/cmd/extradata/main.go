@@ -18,9 +18,12 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -57,6 +60,47 @@ var (
 		Value: "Smilo, The hybrid blockchain platform with a conscience",
 	}
 
+	rpcFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "Node to fetch headers from, eg: --rpc=http://localhost:22000",
+	}
+
+	chaindataFlag = cli.StringFlag{
+		Name:  "chaindata",
+		Usage: "Chaindata directory of a stopped node to read headers from",
+	}
+
+	blockFlag = cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Number of the header to verify",
+	}
+
+	fromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First header of the range to verify",
+	}
+
+	toFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last header of the range to verify (default = --from)",
+	}
+
+	sixtySixPercentBlockFlag = cli.Uint64Flag{
+		Name:  "sixtysixpercentblock",
+		Usage: "66% switch block of the chain (default = from the chaindata, none over RPC)",
+	}
+
+	timeoutFlag = cli.IntFlag{
+		Name:  "timeout",
+		Usage: "timeout in secs of RPC calls",
+		Value: 30,
+	}
+
+	jsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the reports as JSON",
+	}
+
 	ExtraCommand = cli.Command{
 		Name:  "extra",
 		Usage: "Sport extraData",
@@ -92,6 +136,28 @@ var (
 				},
 				Description: `Generate sport mixhash`,
 			},
+			{
+				Action:    Verify,
+				Name:      "verify",
+				Usage:     "Verify the seals of Sport headers",
+				ArgsUsage: "--rpc <url> | --chaindata <dir> --block <number> | --from <number> --to <number>",
+				Flags: []cli.Flag{
+					rpcFlag,
+					chaindataFlag,
+					blockFlag,
+					fromFlag,
+					toFlag,
+					sixtySixPercentBlockFlag,
+					timeoutFlag,
+					jsonFlag,
+				},
+				Description: `
+Recovers the proposer and the committed seal signers of headers, fetched over
+RPC or read from a chaindata directory, and checks them against the fullnodes
+recorded in the header like the Sport engine does, warning when those differ
+from the ones its parent expects. Exits with an error if any header fails, eg:
+with errInvalidCommittedSeals.`,
+			},
 		},
 	}
 )
@@ -161,6 +227,107 @@ func Decode(ctx *cli.Context) error {
 	return nil
 }
 
+func Verify(ctx *cli.Context) error {
+	var (
+		source headerSource
+		err    error
+	)
+	switch {
+	case ctx.String(rpcFlag.Name) != "":
+		source, err = newRPCSource(ctx.String(rpcFlag.Name), time.Duration(ctx.Int(timeoutFlag.Name))*time.Second)
+	case ctx.String(chaindataFlag.Name) != "":
+		source, err = newDBSource(ctx.String(chaindataFlag.Name))
+	default:
+		return cli.NewExitError("--rpc or --chaindata is required", 1)
+	}
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+	defer source.Close()
+
+	var from, to uint64
+	switch {
+	case ctx.IsSet(blockFlag.Name):
+		from = ctx.Uint64(blockFlag.Name)
+		to = from
+	case ctx.IsSet(fromFlag.Name):
+		from, to = ctx.Uint64(fromFlag.Name), ctx.Uint64(fromFlag.Name)
+		if ctx.IsSet(toFlag.Name) {
+			to = ctx.Uint64(toFlag.Name)
+		}
+	default:
+		return cli.NewExitError("--block or --from is required", 1)
+	}
+	if from == 0 {
+		from = 1 // The genesis block carries no seal
+	}
+	if to < from {
+		return cli.NewExitError("empty range", 1)
+	}
+	config := source.Config()
+	var sixtySixPercentBlock *big.Int
+	if ctx.IsSet(sixtySixPercentBlockFlag.Name) {
+		sixtySixPercentBlock = new(big.Int).SetUint64(ctx.Uint64(sixtySixPercentBlockFlag.Name))
+	} else if config != nil {
+		sixtySixPercentBlock = config.SixtySixPercentBlock
+	}
+	// Audit the headers, each against its parent
+	parent, err := source.Header(from - 1)
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+	var reports []*headerReport
+	failed := 0
+	for number := from; number <= to; number++ {
+		header, err := source.Header(number)
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		report := auditHeader(parent, header, config, sixtySixPercentBlock)
+		if report.failed() {
+			failed++
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			reports = append(reports, report)
+		} else {
+			printReport(report)
+		}
+		parent = header
+	}
+	if ctx.Bool(jsonFlag.Name) {
+		out, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("Verified %d headers, %d failed\n", to-from+1, failed)
+	}
+	if failed > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d invalid headers", failed), 1)
+	}
+	return nil
+}
+
+func printReport(report *headerReport) {
+	status := "OK"
+	if report.failed() {
+		status = "FAILED"
+	}
+	fmt.Printf("block %d %s: %s, proposer %s, %d/%d fullnodes committed (quorum %d)\n",
+		report.Number, report.Hash.Hex(), status, report.Proposer.Hex(), report.Valid, len(report.Fullnodes), report.Quorum)
+	for _, seal := range report.Seals {
+		if seal.Error != "" {
+			fmt.Println("  committed seal:", seal.Signer.Hex(), "-", seal.Error)
+		} else {
+			fmt.Println("  committed seal:", seal.Signer.Hex())
+		}
+	}
+	for _, warning := range report.Warnings {
+		fmt.Println("  warning:", warning)
+	}
+	for _, err := range report.Errors {
+		fmt.Println("  error:", err)
+	}
+}
+
 func GenerateExtraFromFullnodes(vanity string, fullnodesStr string) (string, error) {
 	result := strings.Split(fullnodesStr, ",")
 	for i, r := range result {
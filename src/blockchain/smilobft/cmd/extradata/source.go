@@ -0,0 +1,130 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/ethclient"
+	"go-didux/src/blockchain/smilobft/ethdb"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+// headerSource retrieves canonical headers to audit.
+type headerSource interface {
+	// Header returns the canonical header with the given number.
+	Header(number uint64) (*types.Header, error)
+
+	// Config returns the chain configuration, if the source knows it.
+	Config() *params.ChainConfig
+
+	Close()
+}
+
+// rpcSource retrieves headers from a node over RPC.
+type rpcSource struct {
+	client  *ethclient.Client
+	timeout time.Duration
+}
+
+func newRPCSource(url string, timeout time.Duration) (*rpcSource, error) {
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial to node: %v", err)
+	}
+	return &rpcSource{client: client, timeout: timeout}, nil
+}
+
+func (s *rpcSource) Header(number uint64) (*types.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve header %d: %v", number, err)
+	}
+	return header, nil
+}
+
+func (s *rpcSource) Config() *params.ChainConfig {
+	return nil
+}
+
+func (s *rpcSource) Close() {
+	s.client.Close()
+}
+
+// dbSource reads headers from the chaindata directory of a stopped node.
+type dbSource struct {
+	db     ethdb.Database
+	config *params.ChainConfig
+}
+
+func newDBSource(dir string) (*dbSource, error) {
+	var (
+		db  ethdb.Database
+		err error
+	)
+	// Only open the freezer if the node created one, to leave the directory as is
+	if ancient := filepath.Join(dir, "ancient"); isDir(ancient) {
+		db, err = rawdb.NewLevelDBDatabaseWithFreezer(dir, 16, 16, ancient, "")
+	} else {
+		db, err = rawdb.NewLevelDBDatabase(dir, 16, 16, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chaindata: %v", err)
+	}
+	source := &dbSource{db: db}
+	if genesis := rawdb.ReadCanonicalHash(db, 0); genesis != (common.Hash{}) {
+		source.config = rawdb.ReadChainConfig(db, genesis)
+	}
+	return source, nil
+}
+
+func (s *dbSource) Header(number uint64) (*types.Header, error) {
+	hash := rawdb.ReadCanonicalHash(s.db, number)
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("header %d not found", number)
+	}
+	header := rawdb.ReadHeader(s.db, hash, number)
+	if header == nil {
+		return nil, fmt.Errorf("header %d %x not found", number, hash)
+	}
+	return header, nil
+}
+
+func (s *dbSource) Config() *params.ChainConfig {
+	return s.config
+}
+
+func (s *dbSource) Close() {
+	s.db.Close()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
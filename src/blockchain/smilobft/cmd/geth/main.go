@@ -161,9 +161,13 @@ var (
 		utils.SportEnableNodePermissionFlag,
 		utils.SportRequestTimeoutFlag,
 		utils.SportBlockPeriodFlag,
+		utils.SportEvictMisbehavingFlag,
+		utils.SportNoWALFlag,
 		utils.SolcPathFlag,
 		utils.SmiloCodeAnalysisPathFlag,
 		utils.MinBlocksEmptyMiningFlag,
+		utils.VaultDriverFlag,
+		utils.VaultIPCFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -224,6 +228,8 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		inspectCommand,
+		// See vaultcmd.go:
+		vaultCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
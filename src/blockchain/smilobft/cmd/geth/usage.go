@@ -280,9 +280,18 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.SportRequestTimeoutFlag,
 			utils.SportBlockPeriodFlag,
+			utils.SportEvictMisbehavingFlag,
+			utils.SportNoWALFlag,
 			utils.MinBlocksEmptyMiningFlag,
 		},
 	},
+	{
+		Name: "VAULT",
+		Flags: []cli.Flag{
+			utils.VaultDriverFlag,
+			utils.VaultIPCFlag,
+		},
+	},
 	{
 		Name: "CODE-QUALITY",
 		Flags: []cli.Flag{
@@ -0,0 +1,158 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of go-smilo.
+//
+// go-smilo is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-smilo is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-smilo. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-didux/src/blockchain/smilobft/cmd/utils"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+)
+
+var (
+	vaultForceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Import a vault state exported at a block other than the local head",
+	}
+
+	vaultCommand = cli.Command{
+		Name:     "vault",
+		Usage:    "Migrate vault contract state between nodes",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The vault state of a node can't be rebuilt from the chain by any node that isn't
+party to the vault transactions. These commands move it to another node, e.g. to
+rebuild a node whose vault database was lost, or to add a node to the privacy
+groups of the exporting node.
+
+Vault state roots are not part of the chain: the import checks the state is
+complete and matches the root it was exported with, but the state itself is taken
+on trust from the exporting node. The export holds the contracts of every privacy
+group of the exporting node, only hand it to nodes allowed to see all of them.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the vault state at a block into a file",
+				ArgsUsage: "<filename> [<blockNum>]",
+				Action:    utils.MigrateFlags(exportVaultState),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+				},
+				Description: `
+Writes the vault state at the given block, the current head by default, together
+with the vault state root it is recorded under to the file as JSON.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import a vault state exported from another node",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(importVaultState),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+					vaultForceFlag,
+				},
+				Description: `
+Installs the vault state in the file after checking it hashes to its vault state
+root and the local chain has the block it was exported at. The node must be
+stopped and its head must be the exported block: later blocks would have no vault
+state, so importing at an older block is refused unless --force is given. Only
+import files exported by a node trusted by the participant.`,
+			},
+		},
+	}
+)
+
+// exportVaultState writes the vault state at a block to a file.
+func exportVaultState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, _ := utils.MakeChain(ctx, stack)
+	start := time.Now()
+
+	header := chain.CurrentHeader()
+	if len(ctx.Args()) > 1 {
+		number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+		}
+		if header = chain.GetHeaderByNumber(number); header == nil {
+			utils.Fatalf("Export error: block #%d not found\n", number)
+		}
+	}
+	export, err := chain.ExportVaultState(header)
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	if err := ioutil.WriteFile(ctx.Args().First(), data, 0600); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Exported vault state of block #%d (%d nodes, root %x) in %v\n", export.Number, len(export.Nodes), export.VaultRoot, time.Since(start))
+	return nil
+}
+
+// importVaultState installs a vault state exported by exportVaultState.
+func importVaultState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	data, err := ioutil.ReadFile(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	export := new(core.VaultStateExport)
+	if err := json.Unmarshal(data, export); err != nil {
+		utils.Fatalf("Import error: invalid vault state file: %v\n", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	start := time.Now()
+	if head := rawdb.ReadHeadBlockHash(db); head != export.BlockHash {
+		if !ctx.Bool(vaultForceFlag.Name) {
+			utils.Fatalf("Import error: vault state exported at block #%d, not the local head (use --%s to import anyway)\n", export.Number, vaultForceFlag.Name)
+		}
+		log.Warn("Vault state not exported at the local head block, later blocks have no vault state", "exported", export.Number)
+	}
+	if err := core.ImportVaultState(db, export); err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Imported vault state of block #%d (%d nodes, root %x) in %v\n", export.Number, len(export.Nodes), export.VaultRoot, time.Since(start))
+	return nil
+}
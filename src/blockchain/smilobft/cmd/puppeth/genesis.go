@@ -17,18 +17,22 @@
 package main
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	math2 "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"go-didux/src/blockchain/smilobft/consensus/ethash"
 	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/params"
 )
 
@@ -488,3 +492,34 @@ func newPyEthereumGenesisSpec(network string, genesis *core.Genesis) (*pyEthereu
 
 	return spec, nil
 }
+
+// sportExtraData returns the genesis extra-data of a Sport chain starting with
+// the given fullnodes.
+func sportExtraData(fullnodes []common.Address) ([]byte, error) {
+	sorted := make([]common.Address, len(fullnodes))
+	copy(sorted, fullnodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	extra, err := rlp.EncodeToBytes(&types.SportExtra{
+		Fullnodes:     sorted,
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]byte, types.SportExtraVanity), extra...), nil
+}
+
+// sportFullnodes returns the fullnodes of a Sport genesis block.
+func sportFullnodes(genesis *core.Genesis) ([]common.Address, error) {
+	if genesis.Config == nil || genesis.Config.Sport == nil {
+		return nil, errors.New("not a Sport genesis")
+	}
+	extra, err := types.ExtractSportExtra(&types.Header{Extra: genesis.ExtraData})
+	if err != nil {
+		return nil, err
+	}
+	return extra.Fullnodes, nil
+}
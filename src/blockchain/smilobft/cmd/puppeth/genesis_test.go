@@ -25,8 +25,10 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/go-ethereum/common"
 
 	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 // Tests the go-ethereum to Aleth chainspec conversion for the Stureby testnet.
@@ -112,3 +114,32 @@ func TestParitySturebyConverter(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the Sport genesis extra-data round trips the fullnodes, sorted.
+func TestSportExtraData(t *testing.T) {
+	fullnodes := []common.Address{
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+	extra, err := sportExtraData(fullnodes)
+	if err != nil {
+		t.Fatalf("failed to encode extra-data: %v", err)
+	}
+	genesis := &core.Genesis{
+		Config:    &params.ChainConfig{Sport: &params.SportConfig{Epoch: 30000}},
+		ExtraData: extra,
+	}
+	decoded, err := sportFullnodes(genesis)
+	if err != nil {
+		t.Fatalf("failed to decode extra-data: %v", err)
+	}
+	want := []common.Address{fullnodes[1], fullnodes[2], fullnodes[0]}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("fullnodes mismatch: have %x, want %x", decoded, want)
+	}
+	genesis.Config.Sport = nil
+	if _, err := sportFullnodes(genesis); err == nil {
+		t.Errorf("non Sport genesis accepted")
+	}
+}
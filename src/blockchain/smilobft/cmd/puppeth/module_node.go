@@ -27,22 +27,28 @@ import (
 	"text/template"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 // nodeDockerfile is the Dockerfile required to run an Ethereum node.
 var nodeDockerfile = `
-FROM ethereum/client-go:latest
+FROM {{.Image}}
 
 ADD genesis.json /genesis.json
 {{if .Unlock}}
 	ADD signer.json /signer.json
 	ADD signer.pass /signer.pass
+{{end}}{{if .NodeKey}}
+	ADD nodekey /nodekey
+{{end}}{{if .Permissioned}}
+	ADD permissioned-nodes.json /permissioned-nodes.json
 {{end}}
 RUN \
   echo 'geth --cache 512 init /genesis.json' > geth.sh && \{{if .Unlock}}
-	echo 'mkdir -p /root/.ethereum/keystore/ && cp /signer.json /root/.ethereum/keystore/' >> geth.sh && \{{end}}
-	echo $'exec geth --networkid {{.NetworkID}} --cache 512 --port {{.Port}} --nat extip:{{.IP}} --maxpeers {{.Peers}} {{.LightFlag}} --ethstats \'{{.Ethstats}}\' {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} {{if .Etherbase}}--miner.etherbase {{.Etherbase}} --mine --miner.threads 1{{end}} {{if .Unlock}}--unlock 0 --password /signer.pass --mine{{end}} --miner.gastarget {{.GasTarget}} --miner.gaslimit {{.GasLimit}} --miner.gasprice {{.GasPrice}}' >> geth.sh
+	echo 'mkdir -p /root/.ethereum/keystore/ && cp /signer.json /root/.ethereum/keystore/' >> geth.sh && \{{end}}{{if .Permissioned}}
+	echo 'cp /permissioned-nodes.json /root/.ethereum/' >> geth.sh && \{{end}}
+	echo $'exec geth --networkid {{.NetworkID}} --cache 512 --port {{.Port}} --nat extip:{{.IP}} --maxpeers {{.Peers}} {{.LightFlag}} --ethstats \'{{.Ethstats}}\' {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} {{if .Etherbase}}--miner.etherbase {{.Etherbase}} --mine --miner.threads 1{{end}} {{if .Unlock}}--unlock 0 --password /signer.pass --mine{{end}} {{if .NodeKey}}--nodekey /nodekey --mine --smilobft.blockperiod {{.BlockPeriod}}{{end}} {{if .Permissioned}}--smilobft.permissioned{{end}} {{if .Vaultdir}}--vault.driver blackbox --vault.ipc /vault/blackbox.ipc{{end}} --miner.gastarget {{.GasTarget}} --miner.gaslimit {{.GasLimit}} --miner.gasprice {{.GasPrice}}' >> geth.sh
 
 ENTRYPOINT ["/bin/sh", "geth.sh"]
 `
@@ -61,8 +67,10 @@ services:
       - "{{.Port}}:{{.Port}}/udp"
     volumes:
       - {{.Datadir}}:/root/.ethereum{{if .Ethashdir}}
-      - {{.Ethashdir}}:/root/.ethash{{end}}
+      - {{.Ethashdir}}:/root/.ethash{{end}}{{if .Vaultdir}}
+      - {{.Vaultdir}}:/vault{{end}}
     environment:
+      - BASE_IMAGE={{.Image}}
       - PORT={{.Port}}/tcp
       - TOTAL_PEERS={{.TotalPeers}}
       - LIGHT_PEERS={{.LightPeers}}
@@ -70,7 +78,8 @@ services:
       - MINER_NAME={{.Etherbase}}
       - GAS_TARGET={{.GasTarget}}
       - GAS_LIMIT={{.GasLimit}}
-      - GAS_PRICE={{.GasPrice}}
+      - GAS_PRICE={{.GasPrice}}{{if .BlockPeriod}}
+      - BLOCK_PERIOD={{.BlockPeriod}}{{end}}
     logging:
       driver: "json-file"
       options:
@@ -84,7 +93,7 @@ services:
 // already exists there, it will be overwritten!
 func deployNode(client *sshClient, network string, bootnodes []string, config *nodeInfos, nocache bool) ([]byte, error) {
 	kind := "sealnode"
-	if config.keyJSON == "" && config.etherbase == "" {
+	if config.keyJSON == "" && config.etherbase == "" && config.nodeKey == "" {
 		kind = "bootnode"
 		bootnodes = make([]string, 0)
 	}
@@ -96,38 +105,54 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 	if config.peersLight > 0 {
 		lightFlag = fmt.Sprintf("--lightpeers=%d --lightserv=50", config.peersLight)
 	}
+	image := config.image
+	if image == "" {
+		image = "ethereum/client-go:latest"
+	}
+	blockPeriod := 0
+	if config.nodeKey != "" {
+		blockPeriod = config.blockPeriod
+	}
 	dockerfile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeDockerfile)).Execute(dockerfile, map[string]interface{}{
-		"NetworkID": config.network,
-		"Port":      config.port,
-		"IP":        client.address,
-		"Peers":     config.peersTotal,
-		"LightFlag": lightFlag,
-		"Bootnodes": strings.Join(bootnodes, ","),
-		"Ethstats":  config.ethstats,
-		"Etherbase": config.etherbase,
-		"GasTarget": uint64(1000000 * config.gasTarget),
-		"GasLimit":  uint64(1000000 * config.gasLimit),
-		"GasPrice":  uint64(1000000000 * config.gasPrice),
-		"Unlock":    config.keyJSON != "",
+		"Image":        image,
+		"NetworkID":    config.network,
+		"Port":         config.port,
+		"IP":           client.address,
+		"Peers":        config.peersTotal,
+		"LightFlag":    lightFlag,
+		"Bootnodes":    strings.Join(bootnodes, ","),
+		"Ethstats":     config.ethstats,
+		"Etherbase":    config.etherbase,
+		"GasTarget":    uint64(1000000 * config.gasTarget),
+		"GasLimit":     uint64(1000000 * config.gasLimit),
+		"GasPrice":     uint64(1000000000 * config.gasPrice),
+		"Unlock":       config.keyJSON != "",
+		"NodeKey":      config.nodeKey != "",
+		"BlockPeriod":  blockPeriod,
+		"Permissioned": len(config.permissioned) > 0,
+		"Vaultdir":     config.vaultdir,
 	})
 	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
 
 	composefile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeComposefile)).Execute(composefile, map[string]interface{}{
-		"Type":       kind,
-		"Datadir":    config.datadir,
-		"Ethashdir":  config.ethashdir,
-		"Network":    network,
-		"Port":       config.port,
-		"TotalPeers": config.peersTotal,
-		"Light":      config.peersLight > 0,
-		"LightPeers": config.peersLight,
-		"Ethstats":   config.ethstats[:strings.Index(config.ethstats, ":")],
-		"Etherbase":  config.etherbase,
-		"GasTarget":  config.gasTarget,
-		"GasLimit":   config.gasLimit,
-		"GasPrice":   config.gasPrice,
+		"Type":        kind,
+		"Image":       image,
+		"Datadir":     config.datadir,
+		"Ethashdir":   config.ethashdir,
+		"Network":     network,
+		"Port":        config.port,
+		"TotalPeers":  config.peersTotal,
+		"Light":       config.peersLight > 0,
+		"LightPeers":  config.peersLight,
+		"Ethstats":    config.ethstats[:strings.Index(config.ethstats, ":")],
+		"Etherbase":   config.etherbase,
+		"GasTarget":   config.gasTarget,
+		"GasLimit":    config.gasLimit,
+		"GasPrice":    config.gasPrice,
+		"Vaultdir":    config.vaultdir,
+		"BlockPeriod": blockPeriod,
 	})
 	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
 
@@ -136,6 +161,13 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 		files[filepath.Join(workdir, "signer.json")] = []byte(config.keyJSON)
 		files[filepath.Join(workdir, "signer.pass")] = []byte(config.keyPass)
 	}
+	if config.nodeKey != "" {
+		files[filepath.Join(workdir, "nodekey")] = []byte(config.nodeKey)
+	}
+	if len(config.permissioned) > 0 {
+		permissioned, _ := json.MarshalIndent(config.permissioned, "", "  ")
+		files[filepath.Join(workdir, "permissioned-nodes.json")] = permissioned
+	}
 	// Upload the deployment files to the remote server (and clean up afterwards)
 	if out, err := client.Upload(files); err != nil {
 		return out, err
@@ -167,6 +199,12 @@ type nodeInfos struct {
 	gasTarget  float64
 	gasLimit   float64
 	gasPrice   float64
+
+	image        string   // Docker image to run, defaults to the upstream geth one
+	nodeKey      string   // Hex encoded node key of a Sport fullnode
+	blockPeriod  int      // Minimum seconds between Sport blocks
+	permissioned []string // Enode URLs allowed to connect, if restricted
+	vaultdir     string   // Directory of the blackbox vault socket, if private
 }
 
 // Report converts the typed struct into a plain string->string map, containing
@@ -201,6 +239,21 @@ func (info *nodeInfos) Report() map[string]string {
 				log.Error("Failed to retrieve signer address", "err", err)
 			}
 		}
+		if info.nodeKey != "" {
+			// Sport fullnode, sealing with its node key
+			if key, err := crypto.HexToECDSA(info.nodeKey); err == nil {
+				report["Fullnode account"] = crypto.PubkeyToAddress(key.PublicKey).Hex()
+			} else {
+				log.Error("Failed to retrieve fullnode address", "err", err)
+			}
+			report["Block period (minimum)"] = fmt.Sprintf("%d s", info.blockPeriod)
+		}
+	}
+	if len(info.permissioned) > 0 {
+		report["Permissioned nodes"] = strconv.Itoa(len(info.permissioned))
+	}
+	if info.vaultdir != "" {
+		report["Vault directory"] = info.vaultdir
 	}
 	return report
 }
@@ -226,6 +279,7 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 	gasTarget, _ := strconv.ParseFloat(infos.envvars["GAS_TARGET"], 64)
 	gasLimit, _ := strconv.ParseFloat(infos.envvars["GAS_LIMIT"], 64)
 	gasPrice, _ := strconv.ParseFloat(infos.envvars["GAS_PRICE"], 64)
+	blockPeriod, _ := strconv.Atoi(infos.envvars["BLOCK_PERIOD"])
 
 	// Container available, retrieve its node ID and its genesis json
 	var out []byte
@@ -246,6 +300,16 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 	if out, err = client.Run(fmt.Sprintf("docker exec %s_%s_1 cat /signer.pass", network, kind)); err == nil {
 		keyPass = string(bytes.TrimSpace(out))
 	}
+	nodeKey := ""
+	if out, err = client.Run(fmt.Sprintf("docker exec %s_%s_1 cat /nodekey", network, kind)); err == nil {
+		nodeKey = string(bytes.TrimSpace(out))
+	}
+	var permissioned []string
+	if out, err = client.Run(fmt.Sprintf("docker exec %s_%s_1 cat /permissioned-nodes.json", network, kind)); err == nil {
+		if err := json.Unmarshal(out, &permissioned); err != nil {
+			log.Warn("Failed to parse permissioned nodes", "err", err)
+		}
+	}
 	// Run a sanity check to see if the devp2p is reachable
 	port := infos.portmap[infos.envvars["PORT"]]
 	if err = checkPort(client.server, port); err != nil {
@@ -266,6 +330,12 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 		gasTarget:  gasTarget,
 		gasLimit:   gasLimit,
 		gasPrice:   gasPrice,
+
+		image:        infos.envvars["BASE_IMAGE"],
+		nodeKey:      nodeKey,
+		blockPeriod:  blockPeriod,
+		permissioned: permissioned,
+		vaultdir:     infos.volumes["/vault"],
 	}
 	stats.enode = string(enode)
 
@@ -0,0 +1,248 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// vaultDockerfile is the Dockerfile required to run a blackbox vault.
+var vaultDockerfile = `
+FROM {{.Image}}
+
+ADD blackbox.conf /blackbox.conf
+ADD blackbox.pub /blackbox.pub
+ADD blackbox.key /blackbox.key
+
+ENTRYPOINT ["blackbox", "--configfile", "/blackbox.conf"]
+`
+
+// vaultComposefile is the docker-compose.yml file required to deploy and
+// maintain a blackbox vault. The vault socket is shared with the fullnode of the
+// same machine through the data directory.
+var vaultComposefile = `
+version: '2'
+services:
+  vault:
+    build: .
+    image: {{.Network}}/vault
+    container_name: {{.Network}}_vault_1
+    ports:
+      - "{{.Port}}:{{.Port}}"
+    volumes:
+      - {{.Datadir}}:/vault
+    environment:
+      - BASE_IMAGE={{.Image}}
+      - PORT={{.Port}}/tcp
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// vaultConfig is the blackbox configuration file of a vault, pointing it to the
+// keys added next to it and keeping its socket and payloads in the data directory.
+type vaultConfig struct {
+	Server struct {
+		Port int `json:"port"`
+	} `json:"server"`
+	HostName string          `json:"hostName"`
+	Socket   string          `json:"socket"`
+	DBFile   string          `json:"dbfile"`
+	Peers    []vaultPeer     `json:"peer"`
+	Keys     vaultConfigKeys `json:"keys"`
+}
+
+type vaultPeer struct {
+	URL string `json:"url"`
+}
+
+type vaultConfigKeys struct {
+	Passwords []string       `json:"passwords"`
+	KeyData   []vaultKeyPath `json:"keyData"`
+}
+
+type vaultKeyPath struct {
+	PrivateKeyPath string `json:"privateKeyPath"`
+	PublicKeyPath  string `json:"publicKeyPath"`
+}
+
+// vaultPrivateKey is the blackbox file format of an unlocked private key.
+type vaultPrivateKey struct {
+	Data struct {
+		Bytes string `json:"bytes"`
+	} `json:"data"`
+	Type string `json:"type"`
+}
+
+// newVaultKey generates a blackbox key pair, returning the base64 encoded public
+// and private keys.
+func newVaultKey() (string, string, error) {
+	pub, priv, err := box.GenerateKey(crand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub[:]), base64.StdEncoding.EncodeToString(priv[:]), nil
+}
+
+// deployVault deploys a new blackbox vault container to a remote machine via
+// SSH, docker and docker-compose. If an instance with the specified network name
+// already exists there, it will be overwritten!
+func deployVault(client *sshClient, network string, config *vaultInfos, nocache bool) ([]byte, error) {
+	// Generate the content to upload to the server
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	dockerfile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(vaultDockerfile)).Execute(dockerfile, map[string]interface{}{
+		"Image": config.image,
+	})
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(vaultComposefile)).Execute(composefile, map[string]interface{}{
+		"Image":   config.image,
+		"Datadir": config.datadir,
+		"Network": network,
+		"Port":    config.port,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	conf := vaultConfig{
+		HostName: "http://" + client.address,
+		Socket:   "/vault/blackbox.ipc",
+		DBFile:   "/vault/blackbox.db",
+		Peers:    make([]vaultPeer, 0, len(config.peers)),
+		Keys: vaultConfigKeys{
+			Passwords: []string{},
+			KeyData:   []vaultKeyPath{{PrivateKeyPath: "/blackbox.key", PublicKeyPath: "/blackbox.pub"}},
+		},
+	}
+	conf.Server.Port = config.port
+	for _, peer := range config.peers {
+		conf.Peers = append(conf.Peers, vaultPeer{URL: peer})
+	}
+	confJSON, _ := json.MarshalIndent(conf, "", "  ")
+	files[filepath.Join(workdir, "blackbox.conf")] = confJSON
+
+	key := vaultPrivateKey{Type: "unlocked"}
+	key.Data.Bytes = config.privateKey
+	keyJSON, _ := json.MarshalIndent(key, "", "  ")
+	files[filepath.Join(workdir, "blackbox.key")] = keyJSON
+	files[filepath.Join(workdir, "blackbox.pub")] = []byte(config.publicKey)
+
+	// Upload the deployment files to the remote server (and clean up afterwards)
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	// Build and deploy the vault service
+	if nocache {
+		return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s build --pull --no-cache && docker-compose -p %s up -d --force-recreate --timeout 60", workdir, network, network))
+	}
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate --timeout 60", workdir, network))
+}
+
+// vaultInfos is returned from a blackbox vault status check to allow reporting
+// various configuration parameters.
+type vaultInfos struct {
+	image      string
+	datadir    string
+	port       int
+	peers      []string
+	publicKey  string // Base64 encoded public key other participants address the vault by
+	privateKey string // Base64 encoded private key of the vault
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *vaultInfos) Report() map[string]string {
+	report := map[string]string{
+		"Docker image":      info.image,
+		"Data directory":    info.datadir,
+		"IPC socket":        filepath.Join(info.datadir, "blackbox.ipc"),
+		"Listener port":     strconv.Itoa(info.port),
+		"Known vault peers": strings.Join(info.peers, ", "),
+		"Vault public key":  info.publicKey,
+	}
+	return report
+}
+
+// checkVault does a health-check against a blackbox vault server to verify
+// whether it's running, and if yes, whether it's responsive.
+func checkVault(client *sshClient, network string) (*vaultInfos, error) {
+	// Inspect a possible vault container on the host
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_vault_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	// Run a sanity check to see if the peer port is reachable
+	port := infos.portmap[infos.envvars["PORT"]]
+	if err = checkPort(client.server, port); err != nil {
+		log.Warn("Vault port seems unreachable", "server", client.server, "port", port, "err", err)
+	}
+	// Container available, retrieve its peers and keys
+	var peers []string
+	if out, err := client.Run(fmt.Sprintf("docker exec %s_vault_1 cat /blackbox.conf", network)); err == nil {
+		var conf vaultConfig
+		if err := json.Unmarshal(out, &conf); err != nil {
+			log.Warn("Failed to parse vault config", "err", err)
+		}
+		for _, peer := range conf.Peers {
+			peers = append(peers, peer.URL)
+		}
+	}
+	publicKey, privateKey := "", ""
+	if out, err := client.Run(fmt.Sprintf("docker exec %s_vault_1 cat /blackbox.pub", network)); err == nil {
+		publicKey = string(bytes.TrimSpace(out))
+	}
+	if out, err := client.Run(fmt.Sprintf("docker exec %s_vault_1 cat /blackbox.key", network)); err == nil {
+		var key vaultPrivateKey
+		if err := json.Unmarshal(out, &key); err != nil {
+			log.Warn("Failed to parse vault key", "err", err)
+		}
+		privateKey = key.Data.Bytes
+	}
+	// Assemble and return the useful infos
+	stats := &vaultInfos{
+		image:      infos.envvars["BASE_IMAGE"],
+		datadir:    infos.volumes["/vault"],
+		port:       port,
+		peers:      peers,
+		publicKey:  publicKey,
+		privateKey: privateKey,
+	}
+	return stats, nil
+}
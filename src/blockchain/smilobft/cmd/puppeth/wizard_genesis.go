@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/params"
 )
 
@@ -59,6 +60,7 @@ func (w *wizard) makeGenesis() {
 	fmt.Println("Which consensus engine to use? (default = clique)")
 	fmt.Println(" 1. Ethash - proof-of-work")
 	fmt.Println(" 2. Clique - proof-of-authority")
+	fmt.Println(" 3. Sport  - byzantine fault tolerant proof-of-authority")
 
 	choice := w.read()
 	switch {
@@ -105,6 +107,72 @@ func (w *wizard) makeGenesis() {
 			copy(genesis.ExtraData[32+i*common.AddressLength:], signer[:])
 		}
 
+	case choice == "3":
+		// In the case of Sport, configure the consensus and the economics
+		genesis.Difficulty = big.NewInt(1)
+		genesis.Mixhash = types.SportDigest
+		genesis.Config.Sport = &params.SportConfig{
+			Epoch:    30000,
+			MinFunds: 1,
+		}
+		fmt.Println()
+		fmt.Println("How many blocks should votes be kept for before a checkpoint? (default = 30000)")
+		genesis.Config.Sport.Epoch = uint64(w.readDefaultInt(30000))
+
+		// We also need the initial list of fullnodes
+		fmt.Println()
+		fmt.Println("Which accounts are fullnodes? (mandatory at least one)")
+
+		var fullnodes []common.Address
+		for {
+			if address := w.readAddress(); address != nil {
+				fullnodes = append(fullnodes, *address)
+				continue
+			}
+			if len(fullnodes) > 0 {
+				break
+			}
+		}
+		extra, err := sportExtraData(fullnodes)
+		if err != nil {
+			log.Error("Failed to encode fullnodes", "err", err)
+			return
+		}
+		genesis.ExtraData = extra
+
+		fmt.Println()
+		fmt.Println("From which block should two thirds of the fullnodes seal blocks? (default = 0)")
+		genesis.Config.SixtySixPercentBlock = w.readDefaultBigInt(big.NewInt(0))
+
+		fmt.Println()
+		fmt.Println("Should blocks pay the Sport block rewards? (default = yes)")
+		if w.readDefaultYesNo(true) {
+			fmt.Println()
+			fmt.Println("Which account should receive the community share of the rewards? (default = none)")
+			community := w.readDefaultAddress(common.Address{})
+			genesis.Config.Sport.Rewards = []*params.SportRewards{params.DefaultSportRewards(big.NewInt(0), community)}
+		}
+		fmt.Println()
+		fmt.Println("How many seconds may the chain stay without a block while idle? (default = 0 = no empty blocks limit)")
+		if period := w.readDefaultInt(0); period > 0 {
+			genesis.Config.Sport.EmptyBlocks = &params.SportEmptyBlocks{Block: big.NewInt(0), Period: uint64(period)}
+		}
+		// Sport chains pay for transactions with SmiloPay rather than plain gas
+		fmt.Println()
+		fmt.Println("Should transactions be paid with SmiloPay? (default = yes)")
+		genesis.Config.IsSmilo = w.readDefaultYesNo(true)
+		if genesis.Config.IsSmilo {
+			genesis.Config.SmiloPay = params.SmiloPayCurves{params.DefaultSmiloPayCurve(big.NewInt(0))}
+		}
+		fmt.Println()
+		fmt.Println("Should transactions use gas? (default = yes)")
+		genesis.Config.IsGas = w.readDefaultYesNo(true)
+		if genesis.Config.IsGas {
+			fmt.Println()
+			fmt.Println("Should unused gas be refunded? (default = yes)")
+			genesis.Config.IsGasRefunded = w.readDefaultYesNo(true)
+		}
+
 	default:
 		log.Crit("Invalid consensus engine choice", "choice", choice)
 	}
@@ -231,6 +299,12 @@ func (w *wizard) manageGenesis() {
 		fmt.Printf("Which block should Petersburg come into effect? (default = %v)\n", w.conf.Genesis.Config.PetersburgBlock)
 		w.conf.Genesis.Config.PetersburgBlock = w.readDefaultBigInt(w.conf.Genesis.Config.PetersburgBlock)
 
+		if w.conf.Genesis.Config.Sport != nil {
+			fmt.Println()
+			fmt.Printf("From which block should two thirds of the fullnodes seal blocks? (default = %v)\n", w.conf.Genesis.Config.SixtySixPercentBlock)
+			w.conf.Genesis.Config.SixtySixPercentBlock = w.readDefaultBigInt(w.conf.Genesis.Config.SixtySixPercentBlock)
+		}
+
 		out, _ := json.MarshalIndent(w.conf.Genesis.Config, "", "  ")
 		fmt.Printf("Chain configuration updated:\n\n%s\n", out)
 
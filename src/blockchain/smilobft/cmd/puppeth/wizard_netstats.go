@@ -158,6 +158,14 @@ func (w *wizard) gatherStats(server string, pubkey []byte, client *sshClient) *s
 	} else {
 		stat.services["faucet"] = infos.Report()
 	}
+	logger.Debug("Checking for vault availability")
+	if infos, err := checkVault(client, w.network); err != nil {
+		if err != ErrServiceUnknown {
+			stat.services["vault"] = map[string]string{"offline": err.Error()}
+		}
+	} else {
+		stat.services["vault"] = infos.Report()
+	}
 	logger.Debug("Checking for dashboard availability")
 	if infos, err := checkDashboard(client, w.network); err != nil {
 		if err != ErrServiceUnknown {
@@ -22,9 +22,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 
 	"go-didux/src/blockchain/smilobft/accounts/keystore"
+	"go-didux/src/blockchain/smilobft/p2p/enode"
 )
 
 // deployNode creates a new node configuration based on some user input.
@@ -59,6 +61,18 @@ func (w *wizard) deployNode(boot bool) {
 	infos.genesis, _ = json.MarshalIndent(w.conf.Genesis, "", "  ")
 	infos.network = w.conf.Genesis.Config.ChainID.Int64()
 
+	// Sport nodes run a Sport enabled client rather than upstream geth
+	if w.conf.Genesis.Config.Sport != nil {
+		fmt.Println()
+		if infos.image == "" {
+			fmt.Printf("Which docker image runs the Sport client?\n")
+			infos.image = w.readString()
+		} else {
+			fmt.Printf("Which docker image runs the Sport client? (default = %s)\n", infos.image)
+			infos.image = w.readDefaultString(infos.image)
+		}
+	}
+
 	// Figure out where the user wants to store the persistent data
 	fmt.Println()
 	if infos.datadir == "" {
@@ -147,6 +161,51 @@ func (w *wizard) deployNode(boot bool) {
 					return
 				}
 			}
+		} else if w.conf.Genesis.Config.Sport != nil {
+			// Sport fullnodes seal with their node key, offer to reuse a previous one
+			fullnodes, err := sportFullnodes(w.conf.Genesis)
+			if err != nil {
+				log.Error("Failed to retrieve genesis fullnodes", "err", err)
+				return
+			}
+			if infos.nodeKey != "" {
+				if key, err := crypto.HexToECDSA(infos.nodeKey); err != nil {
+					infos.nodeKey = ""
+				} else {
+					fmt.Println()
+					fmt.Printf("Reuse previous (%s) fullnode key (y/n)? (default = yes)\n", crypto.PubkeyToAddress(key.PublicKey).Hex())
+					if !w.readDefaultYesNo(true) {
+						infos.nodeKey = ""
+					}
+				}
+			}
+			if infos.nodeKey == "" {
+				fmt.Println()
+				fmt.Println("What's the hex encoded node key of the fullnode? (won't be echoed)")
+				infos.nodeKey = w.readPassword()
+
+				key, err := crypto.HexToECDSA(infos.nodeKey)
+				if err != nil {
+					log.Error("Invalid node key", "err", err)
+					return
+				}
+				address, known := crypto.PubkeyToAddress(key.PublicKey), false
+				for _, fullnode := range fullnodes {
+					if fullnode == address {
+						known = true
+						break
+					}
+				}
+				if !known {
+					log.Warn("Node key is not a genesis fullnode, it has to be voted in", "address", address)
+				}
+			}
+			if infos.blockPeriod == 0 {
+				infos.blockPeriod = 1
+			}
+			fmt.Println()
+			fmt.Printf("What's the minimum number of seconds between blocks? (default = %d)\n", infos.blockPeriod)
+			infos.blockPeriod = w.readDefaultInt(infos.blockPeriod)
 		}
 		// Establish the gas dynamics to be enforced by the signer
 		fmt.Println()
@@ -161,6 +220,53 @@ func (w *wizard) deployNode(boot bool) {
 		fmt.Printf("What gas price should the signer require (GWei)? (default = %0.3f)\n", infos.gasPrice)
 		infos.gasPrice = w.readDefaultFloat(infos.gasPrice)
 	}
+	if w.conf.Genesis.Config.Sport != nil {
+		// Sport networks may restrict the nodes allowed to connect
+		fmt.Println()
+		if len(infos.permissioned) > 0 {
+			fmt.Printf("Should only permissioned nodes be allowed to connect (y/n)? (default = yes)\n")
+		} else {
+			fmt.Printf("Should only permissioned nodes be allowed to connect (y/n)? (default = no)\n")
+		}
+		if w.readDefaultYesNo(len(infos.permissioned) > 0) {
+			fmt.Println()
+			if len(infos.permissioned) > 0 {
+				fmt.Printf("Which enode URLs are permissioned? (default = keep the %d previous ones)\n", len(infos.permissioned))
+			} else {
+				fmt.Println("Which enode URLs are permissioned? (mandatory at least one)")
+			}
+			var permissioned []string
+			for {
+				url := w.readDefaultString("")
+				if url == "" {
+					if len(permissioned) > 0 {
+						break
+					}
+					if len(infos.permissioned) > 0 {
+						permissioned = infos.permissioned
+						break
+					}
+					continue
+				}
+				if _, err := enode.ParseV4(url); err != nil {
+					log.Error("Invalid enode URL", "err", err)
+					continue
+				}
+				permissioned = append(permissioned, url)
+			}
+			infos.permissioned = permissioned
+		} else {
+			infos.permissioned = nil
+		}
+		// Private transactions need a blackbox vault running on the same machine
+		fmt.Println()
+		if infos.vaultdir == "" {
+			fmt.Printf("Where is the blackbox vault data stored on the remote machine? (default = none, no private transactions)\n")
+		} else {
+			fmt.Printf("Where is the blackbox vault data stored on the remote machine? (default = %s)\n", infos.vaultdir)
+		}
+		infos.vaultdir = w.readDefaultString(infos.vaultdir)
+	}
 	// Try to deploy the full node on the host
 	nocache := false
 	if existed {
@@ -0,0 +1,131 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// deployVault creates a new blackbox vault based on some user input. Vaults
+// carry the private transactions of the Sport fullnode on the same machine.
+func (w *wizard) deployVault() {
+	// Do some sanity check before the user wastes time on input
+	if w.conf.Genesis == nil {
+		log.Error("No genesis block configured")
+		return
+	}
+	if w.conf.Genesis.Config.Sport == nil {
+		log.Error("Vaults are only supported on Sport networks")
+		return
+	}
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	// Retrieve any active vault configurations from the server
+	infos, err := checkVault(client, w.network)
+	if err != nil {
+		infos = &vaultInfos{port: 9000}
+	}
+	existed := err == nil
+
+	// Figure out which image runs the vault
+	fmt.Println()
+	if infos.image == "" {
+		fmt.Printf("Which docker image runs the blackbox vault?\n")
+		infos.image = w.readString()
+	} else {
+		fmt.Printf("Which docker image runs the blackbox vault? (default = %s)\n", infos.image)
+		infos.image = w.readDefaultString(infos.image)
+	}
+	// Figure out where the user wants to store the persistent data
+	fmt.Println()
+	if infos.datadir == "" {
+		fmt.Printf("Where should data be stored on the remote machine?\n")
+		infos.datadir = w.readString()
+	} else {
+		fmt.Printf("Where should data be stored on the remote machine? (default = %s)\n", infos.datadir)
+		infos.datadir = w.readDefaultString(infos.datadir)
+	}
+	// Figure out which port to listen on
+	fmt.Println()
+	fmt.Printf("Which TCP port should the vault listen on for other vaults? (default = %d)\n", infos.port)
+	infos.port = w.readDefaultInt(infos.port)
+
+	// Figure out which other vaults to connect to
+	fmt.Println()
+	if len(infos.peers) == 0 {
+		fmt.Println("Which other vault URLs should be connected to? (empty line to finish)")
+	} else {
+		fmt.Printf("Which other vault URLs should be connected to? (default = keep the %d previous ones)\n", len(infos.peers))
+	}
+	var peers []string
+	for {
+		if url := w.readDefaultString(""); url != "" {
+			peers = append(peers, url)
+			continue
+		}
+		break
+	}
+	if len(peers) > 0 || !existed {
+		infos.peers = peers
+	}
+	// Vaults are addressed by their key, offer to reuse a previous one
+	if infos.publicKey != "" && infos.privateKey != "" {
+		fmt.Println()
+		fmt.Printf("Reuse previous (%s) vault key (y/n)? (default = yes)\n", infos.publicKey)
+		if !w.readDefaultYesNo(true) {
+			infos.publicKey, infos.privateKey = "", ""
+		}
+	}
+	if infos.publicKey == "" || infos.privateKey == "" {
+		if infos.publicKey, infos.privateKey, err = newVaultKey(); err != nil {
+			log.Error("Failed to generate vault key", "err", err)
+			return
+		}
+		log.Info("Generated new vault key", "public", infos.publicKey)
+	}
+	// Try to deploy the vault on the host
+	nocache := false
+	if existed {
+		fmt.Println()
+		fmt.Printf("Should the vault be built from scratch (y/n)? (default = no)\n")
+		nocache = w.readDefaultYesNo(false)
+	}
+	if out, err := deployVault(client, w.network, infos, nocache); err != nil {
+		log.Error("Failed to deploy vault container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	// All ok, run a network scan to pick any changes up
+	log.Info("Waiting for vault to finish booting")
+	time.Sleep(3 * time.Second)
+
+	fmt.Println()
+	fmt.Printf("Sealers on this machine should use %s as their vault data directory\n", infos.datadir)
+	fmt.Printf("Private transactions are sent to this vault with its public key %s\n", infos.publicKey)
+
+	w.networkStats()
+}
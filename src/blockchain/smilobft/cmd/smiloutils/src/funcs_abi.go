@@ -0,0 +1,144 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package src
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi"
+)
+
+// packCall encodes a contract method call, or the constructor arguments if the
+// method is empty, reading the ABI from a file and the arguments from strings.
+func packCall(abiFile string, method string, args []string) ([]byte, error) {
+	f, err := os.Open(abiFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ABI: %v", err)
+	}
+	defer f.Close()
+
+	parsed, err := abi.JSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %v", err)
+	}
+	inputs := parsed.Constructor.Inputs
+	if method != "" {
+		m, ok := parsed.Methods[method]
+		if !ok {
+			return nil, fmt.Errorf("method %q not found in ABI", method)
+		}
+		inputs = m.Inputs
+	}
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("argument count mismatch: have %d, want %d", len(args), len(inputs))
+	}
+	values := make([]interface{}, len(args))
+	for i, input := range inputs {
+		if values[i], err = convertArg(input.Type, args[i]); err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %v", i, input.Type, err)
+		}
+	}
+	return parsed.Pack(method, values...)
+}
+
+// convertArg converts a command line argument into the Go value the ABI packer
+// expects for the type. Arrays and slices are given as [a,b,c].
+func convertArg(t abi.Type, arg string) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(arg) {
+			return nil, fmt.Errorf("invalid address %q", arg)
+		}
+		return common.HexToAddress(arg), nil
+
+	case abi.BoolTy:
+		return strconv.ParseBool(arg)
+
+	case abi.StringTy:
+		return arg, nil
+
+	case abi.BytesTy:
+		return hexutil.Decode(arg)
+
+	case abi.FixedBytesTy, abi.HashTy:
+		blob, err := hexutil.Decode(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(blob) != t.Type.Len() {
+			return nil, fmt.Errorf("invalid length: have %d bytes, want %d", len(blob), t.Type.Len())
+		}
+		value := reflect.New(t.Type).Elem()
+		reflect.Copy(value, reflect.ValueOf(blob))
+		return value.Interface(), nil
+
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(arg, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", arg)
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return nil, fmt.Errorf("%s out of range", arg)
+		}
+		if t.T == abi.IntTy && n.BitLen() >= t.Size {
+			return nil, fmt.Errorf("%s out of range", arg)
+		}
+		if t.Type == reflect.TypeOf(n) {
+			return n, nil
+		}
+		if t.T == abi.UintTy {
+			return reflect.ValueOf(n.Uint64()).Convert(t.Type).Interface(), nil
+		}
+		return reflect.ValueOf(n.Int64()).Convert(t.Type).Interface(), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		if t.Elem.T == abi.SliceTy || t.Elem.T == abi.ArrayTy {
+			return nil, fmt.Errorf("nested arrays are not supported")
+		}
+		if !strings.HasPrefix(arg, "[") || !strings.HasSuffix(arg, "]") {
+			return nil, fmt.Errorf("array %q not in [a,b,c] form", arg)
+		}
+		var items []string
+		if inner := strings.TrimSpace(arg[1 : len(arg)-1]); inner != "" {
+			items = strings.Split(inner, ",")
+		}
+		value := reflect.MakeSlice(reflect.SliceOf(t.Elem.Type), len(items), len(items))
+		if t.T == abi.ArrayTy {
+			if len(items) != t.Size {
+				return nil, fmt.Errorf("invalid length: have %d items, want %d", len(items), t.Size)
+			}
+			value = reflect.New(t.Type).Elem()
+		}
+		for i, item := range items {
+			elem, err := convertArg(*t.Elem, strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			value.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return value.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
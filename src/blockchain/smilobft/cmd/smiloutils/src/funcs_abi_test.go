@@ -0,0 +1,99 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package src
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi"
+)
+
+const testABI = `[
+	{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}]},
+	{"type": "function", "name": "set", "inputs": [
+		{"name": "amount", "type": "uint256"},
+		{"name": "small", "type": "uint8"},
+		{"name": "delta", "type": "int64"},
+		{"name": "flag", "type": "bool"},
+		{"name": "name", "type": "string"},
+		{"name": "id", "type": "bytes4"},
+		{"name": "blob", "type": "bytes"},
+		{"name": "list", "type": "address[]"},
+		{"name": "pair", "type": "uint16[2]"}
+	]}
+]`
+
+func TestPackCall(t *testing.T) {
+	f, err := ioutil.TempFile("", "smiloutils-abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(testABI)
+	f.Close()
+
+	parsed, err := abi.JSON(bytes.NewReader([]byte(testABI)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// Constructor arguments
+	have, err := packCall(f.Name(), "", []string{owner.Hex()})
+	if err != nil {
+		t.Fatalf("failed to pack constructor: %v", err)
+	}
+	want, _ := parsed.Pack("", owner)
+	if !bytes.Equal(have, want) {
+		t.Errorf("constructor mismatch: have %x, want %x", have, want)
+	}
+	// Method call with every supported type
+	have, err = packCall(f.Name(), "set", []string{
+		"0x10", "255", "-3", "true", "hello", "0x01020304", "0xff",
+		"[" + owner.Hex() + ", " + owner.Hex() + "]", "[1,2]",
+	})
+	if err != nil {
+		t.Fatalf("failed to pack call: %v", err)
+	}
+	want, _ = parsed.Pack("set", big.NewInt(16), uint8(255), int64(-3), true, "hello",
+		[4]byte{1, 2, 3, 4}, []byte{0xff}, []common.Address{owner, owner}, [2]uint16{1, 2})
+	if !bytes.Equal(have, want) {
+		t.Errorf("call mismatch: have %x, want %x", have, want)
+	}
+	// Invalid arguments
+	invalid := [][]string{
+		{"0x10"}, // Argument count
+		{"0x10", "256", "-3", "true", "hello", "0x01020304", "0xff", "[]", "[1,2]"},   // uint8 overflow
+		{"0x10", "255", "-3", "maybe", "hello", "0x01020304", "0xff", "[]", "[1,2]"},  // bool
+		{"0x10", "255", "-3", "true", "hello", "0x010203", "0xff", "[]", "[1,2]"},     // bytes4 length
+		{"0x10", "255", "-3", "true", "hello", "0x01020304", "0xff", "[0x12]", "[1]"}, // address and array length
+	}
+	for i, args := range invalid {
+		if _, err := packCall(f.Name(), "set", args); err == nil {
+			t.Errorf("test %d: invalid arguments accepted", i)
+		}
+	}
+	if _, err := packCall(f.Name(), "unknown", nil); err == nil {
+		t.Errorf("unknown method accepted")
+	}
+}
@@ -17,7 +17,7 @@
 package src
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -26,15 +26,38 @@ import (
 	"go-didux/src/blockchain/smilobft"
 )
 
-// EstimateGas will estimate the gas cost
-func EstimateGas(fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte) (gas uint64, err error) {
+// estimateGas asks the node for the gas needed by a transaction.
+func (s *session) estimateGas(fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte) (uint64, error) {
+	if s.client == nil {
+		return 0, errors.New("gas unknown offline, set --gas")
+	}
 	msg := smilobft.CallMsg{From: fromAddress, To: toAddress, Value: amount, Data: data}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := s.context()
+	defer cancel()
+
+	gas, err := s.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	return gas, nil
+}
+
+// suggestGasPrice returns the gas price of the session, suggested by the node unless
+// set on the command line.
+func (s *session) suggestGasPrice() (*big.Int, error) {
+	if s.gasPrice != nil {
+		return s.gasPrice, nil
+	}
+	if s.client == nil {
+		return nil, errors.New("gas price unknown offline, set --gasprice")
+	}
+	ctx, cancel := s.context()
 	defer cancel()
-	gas, err = client.EstimateGas(ctx, msg)
+
+	price, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
-		fmt.Println("estimateGas, client.EstimateGas, ", err)
-		return gas, err
+		return nil, fmt.Errorf("failed to suggest gas price: %v", err)
 	}
-	return gas, err
+	s.gasPrice = price
+	return price, nil
 }
@@ -17,40 +17,33 @@
 package src
 
 import (
-	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
-//GetNextNonce Will get the next nonce for the account
-func GetNextNonce(address common.Address) (nextNonce uint64, err error) {
-	if nonce == -1 {
-		_, err = PendingNonceAt(address)
-		if err != nil {
-			return nextNonce, err
-		}
-	}
-	nonce++
-	nextNonce = uint64(nonce)
-	return nextNonce, err
+// setNonce makes nonce the next one used for the address.
+func (s *session) setNonce(address common.Address, nonce uint64) {
+	s.nonces[address] = nonce
 }
 
-//PendingNonceAt will get the next pending nonce
-func PendingNonceAt(address common.Address) (currentNonce uint64, err error) {
-	if nonce == -1 {
-		var tmpNonce uint64
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// nextNonce returns the nonce of the next transaction of the address and
+// reserves it, asking the node for the pending nonce the first time around.
+func (s *session) nextNonce(address common.Address) (uint64, error) {
+	nonce, ok := s.nonces[address]
+	if !ok {
+		if s.client == nil {
+			return 0, errors.New("nonce unknown offline, set --nonce")
+		}
+		ctx, cancel := s.context()
 		defer cancel()
-		tmpNonce, err = client.PendingNonceAt(ctx, address)
-		if err != nil {
-			err = fmt.Errorf("failed to obtain nonce for %s: %v", address.Hex(), err)
-			return currentNonce, err
+
+		var err error
+		if nonce, err = s.client.PendingNonceAt(ctx, address); err != nil {
+			return 0, fmt.Errorf("failed to obtain nonce for %s: %v", address.Hex(), err)
 		}
-		nonce = int64(tmpNonce)
-		currentNonce = uint64(nonce)
-	} else {
-		currentNonce = uint64(nonce)
 	}
-	return currentNonce, err
+	s.nonces[address] = nonce + 1
+	return nonce, nil
 }
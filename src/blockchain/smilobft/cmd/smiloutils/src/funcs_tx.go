@@ -17,75 +17,261 @@
 package src
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 
+	"go-didux/src/blockchain/smilobft"
 	"go-didux/src/blockchain/smilobft/core/types"
 )
 
-// NewTransactionSigned creates a new transaction and signs it
-func NewTransactionSigned(fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte) (signedTx *types.Transaction, err error) {
-	tx, err := NewTransaction(fromAddress, toAddress, amount, gasLimit, data)
-	if err != nil {
-		err = fmt.Errorf("failed to createSignedTransaction, createTransaction transaction: %v", err)
-		return tx, err
-	}
+// txRequest describes a transaction to build, from the command line flags or an
+// entry of a batch file. Zero fields are filled in from the session.
+type txRequest struct {
+	To         *common.Address `json:"to"`
+	Value      string          `json:"value"` // Wei, decimal or 0x prefixed hex
+	Data       hexutil.Bytes   `json:"data"`
+	Gas        uint64          `json:"gas"`
+	GasPrice   string          `json:"gasPrice"` // Wei, decimal or 0x prefixed hex
+	SharedWith []string        `json:"sharedWith"`
+}
+
+// txResult is the machine readable outcome of building, signing and possibly
+// sending a transaction.
+type txResult struct {
+	Hash        common.Hash     `json:"hash"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Contract    *common.Address `json:"contractAddress,omitempty"`
+	Nonce       uint64          `json:"nonce"`
+	Value       string          `json:"value"`
+	Gas         uint64          `json:"gas"`
+	GasPrice    string          `json:"gasPrice"`
+	Raw         hexutil.Bytes   `json:"raw"`
+	Private     bool            `json:"private,omitempty"`
+	VaultDigest string          `json:"vaultDigest,omitempty"`
+	Sent        bool            `json:"sent"`
+	Receipt     *receiptResult  `json:"receipt,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// receiptResult is the machine readable form of a transaction receipt.
+type receiptResult struct {
+	TxHash          common.Hash     `json:"transactionHash"`
+	Status          uint64          `json:"status"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	BlockNumber     uint64          `json:"blockNumber"`
+	GasUsed         uint64          `json:"gasUsed"`
+	ContractAddress *common.Address `json:"contractAddress,omitempty"`
+}
 
-	key, err := crypto.HexToECDSA(privatekey)
+// newTxResult describes a signed transaction sent by the given account.
+func newTxResult(tx *types.Transaction, from common.Address) (*txResult, error) {
+	raw, err := rlp.EncodeToBytes(tx)
 	if err != nil {
-		fmt.Println("crypto.HexToECDSA, Invalid private key", err)
 		return nil, err
 	}
+	result := &txResult{
+		Hash:     tx.Hash(),
+		From:     from,
+		To:       tx.To(),
+		Nonce:    tx.Nonce(),
+		Value:    tx.Value().String(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice().String(),
+		Raw:      raw,
+		Private:  tx.IsVault(),
+	}
+	if tx.To() == nil {
+		contract := crypto.CreateAddress(from, tx.Nonce())
+		result.Contract = &contract
+	}
+	return result, nil
+}
+
+// newReceiptResult describes a transaction receipt.
+func newReceiptResult(receipt *types.Receipt) *receiptResult {
+	result := &receiptResult{
+		TxHash:      receipt.TxHash,
+		Status:      receipt.Status,
+		BlockHash:   receipt.BlockHash,
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		GasUsed:     receipt.GasUsed,
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		result.ContractAddress = &receipt.ContractAddress
+	}
+	return result
+}
 
-	keyAddr := crypto.PubkeyToAddress(key.PublicKey)
-	if fromAddress != keyAddr {
-		fmt.Println("crypto.PubkeyToAddress, The address of this PK does not match with the desired fromAddress")
-		return nil, errors.New("not correct PK for the account")
+// parseWei parses an amount of wei, empty meaning zero.
+func parseWei(amount string) (*big.Int, error) {
+	if amount == "" {
+		return new(big.Int), nil
 	}
+	n, ok := new(big.Int).SetString(amount, 0)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return n, nil
+}
 
-	signer := types.NewEIP155Signer(chainID)
+// build creates the unsigned transaction of a request, reserving the next nonce
+// of the sender.
+func (s *session) build(from common.Address, req *txRequest) (*types.Transaction, error) {
+	value, err := parseWei(req.Value)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := parseWei(req.GasPrice)
+	if err != nil {
+		return nil, err
+	}
+	if gasPrice.Sign() == 0 {
+		if gasPrice, err = s.suggestGasPrice(); err != nil {
+			return nil, err
+		}
+	}
+	gas := req.Gas
+	if gas == 0 {
+		gas = s.gasLimit
+	}
+	if gas == 0 {
+		if gas, err = s.estimateGas(from, req.To, value, req.Data); err != nil {
+			return nil, err
+		}
+	}
+	nonce, err := s.nextNonce(from)
+	if err != nil {
+		return nil, err
+	}
+	if req.To == nil {
+		return types.NewContractCreation(nonce, value, gas, gasPrice, req.Data), nil
+	}
+	return types.NewTransaction(nonce, *req.To, value, gas, gasPrice, req.Data), nil
+}
 
-	signedTx, err = types.SignTx(tx, signer, key)
+// sign builds and signs the public transaction of a request.
+func (s *session) sign(signer txSigner, req *txRequest) (*txResult, *types.Transaction, error) {
+	tx, err := s.build(signer.Address(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	signed, err := signer.SignTx(tx, s.chainID)
 	if err != nil {
-		err = fmt.Errorf("failed to createSignedTransaction, signTransaction : %v", err)
-		return tx, err
+		return nil, nil, fmt.Errorf("failed to sign transaction: %v", err)
 	}
+	result, err := newTxResult(signed, signer.Address())
+	return result, signed, err
+}
 
-	GetNextNonce(fromAddress)
+// send broadcasts a signed public transaction through the node.
+func (s *session) send(tx *types.Transaction) error {
+	if err := s.online(); err != nil {
+		return err
+	}
+	ctx, cancel := s.context()
+	defer cancel()
 
-	return signedTx, err
+	if err := s.client.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to send transaction %s: %v", tx.Hash().Hex(), err)
+	}
+	return nil
 }
 
-// NewTransaction will create a unsigned transaction
-func NewTransaction(fromAddress common.Address, toAddress *common.Address, amount *big.Int, gasLimit uint64, data []byte) (tx *types.Transaction, err error) {
-	var txNonce uint64
-	txNonce, err = PendingNonceAt(fromAddress)
+// sendPrivate builds a private transaction: the payload is stored in the vault
+// of the node and shared with the given vault keys, and the transaction sent
+// on chain only carries the vault digest of it.
+func (s *session) sendPrivate(signer txSigner, req *txRequest) (*txResult, error) {
+	if err := s.online(); err != nil {
+		return nil, err
+	}
+	if len(req.Data) == 0 {
+		return nil, errors.New("private transactions need a payload")
+	}
+	if value, err := parseWei(req.Value); err != nil || value.Sign() != 0 {
+		return nil, errors.New("private transactions cannot transfer value")
+	}
+	_, payload, err := s.sign(signer, req)
 	if err != nil {
-		fmt.Println("Error: createTransaction, currentNonce, ", err)
-		return tx, err
+		return nil, err
 	}
+	args := map[string]interface{}{"sharedWith": req.SharedWith}
 
-	if gasLimit == 0 {
-		gasLimit, err = EstimateGas(fromAddress, toAddress, amount, data)
-		if err != nil {
-			fmt.Println("Error: createTransaction, estimateGas, ", err)
-			return tx, err
-		}
+	// Store the payload in the vault, getting back its digest
+	raw, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return nil, err
 	}
+	ctx, cancel := s.context()
+	defer cancel()
 
-	if toAddress == nil {
-		tx = types.NewContractCreation(txNonce, amount, gasLimit, gasPrice, data)
+	var digest string
+	if err := s.rpc.CallContext(ctx, &digest, "eth_shareRawTransactionVault", hexutil.Bytes(raw), args); err != nil {
+		return nil, fmt.Errorf("failed to share payload with the vault: %v", err)
+	}
+	data, err := hex.DecodeString(digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault digest %q: %v", digest, err)
+	}
+	// Replace the payload with its digest and send the private transaction
+	var tx *types.Transaction
+	if payload.To() == nil {
+		tx = types.NewContractCreation(payload.Nonce(), payload.Value(), payload.Gas(), payload.GasPrice(), data)
 	} else {
-		tx = types.NewTransaction(txNonce, *toAddress, amount, gasLimit, gasPrice, data)
+		tx = types.NewTransaction(payload.Nonce(), *payload.To(), payload.Value(), payload.Gas(), payload.GasPrice(), data)
+	}
+	signed, err := signer.SignVaultTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign private transaction: %v", err)
+	}
+	if raw, err = rlp.EncodeToBytes(signed); err != nil {
+		return nil, err
+	}
+	var hash common.Hash
+	if err := s.rpc.CallContext(ctx, &hash, "eth_sendRawTransactionVault", hexutil.Bytes(raw), args); err != nil {
+		return nil, fmt.Errorf("failed to send private transaction: %v", err)
 	}
+	result, err := newTxResult(signed, signer.Address())
+	if err != nil {
+		return nil, err
+	}
+	result.VaultDigest, result.Sent = digest, true
+	return result, nil
+}
 
-	return tx, err
+// waitReceipt polls the node for the receipt of a transaction until it is mined
+// or the session times out.
+func (s *session) waitReceipt(hash common.Hash) (*types.Receipt, error) {
+	if err := s.online(); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(s.timeout)
+	for {
+		ctx, cancel := s.context()
+		receipt, err := s.client.TransactionReceipt(ctx, hash)
+		cancel()
+
+		switch {
+		case err == nil:
+			return receipt, nil
+		case err != smilobft.NotFound:
+			return nil, fmt.Errorf("failed to obtain receipt of %s: %v", hash.Hex(), err)
+		case time.Now().After(deadline):
+			return nil, fmt.Errorf("transaction %s not mined after %v", hash.Hex(), s.timeout)
+		}
+		time.Sleep(time.Second)
+	}
 }
 
+// TxFrom returns the sender of a signed transaction.
 func TxFrom(tx *types.Transaction) (address common.Address, err error) {
 	V, _, _ := tx.RawSignatureValues()
 	signer := deriveSigner(V)
@@ -0,0 +1,95 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package src
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// Tests that transactions are built and signed offline from the session
+// defaults, with consecutive nonces.
+func TestOfflineSign(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := &keySigner{key: key}
+
+	s := &session{
+		timeout:  time.Second,
+		chainID:  big.NewInt(10),
+		gasPrice: big.NewInt(1),
+		gasLimit: 21000,
+		nonces:   make(map[common.Address]uint64),
+	}
+	s.setNonce(signer.Address(), 7)
+
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	for i, req := range []*txRequest{
+		{To: &to, Value: "1000"},
+		{To: &to, Value: "0x10", Gas: 50000, GasPrice: "5"},
+		{Data: []byte{0x60, 0x00}},
+	} {
+		result, tx, err := s.sign(signer, req)
+		if err != nil {
+			t.Fatalf("test %d: failed to sign: %v", i, err)
+		}
+		if tx.Nonce() != uint64(7+i) || result.Nonce != tx.Nonce() {
+			t.Errorf("test %d: nonce mismatch: have %d, want %d", i, tx.Nonce(), 7+i)
+		}
+		from, err := types.Sender(types.NewEIP155Signer(s.chainID), tx)
+		if err != nil || from != signer.Address() || result.From != from {
+			t.Errorf("test %d: sender mismatch: have %x, want %x (%v)", i, from, signer.Address(), err)
+		}
+		if result.Hash != tx.Hash() || len(result.Raw) == 0 || result.Sent {
+			t.Errorf("test %d: invalid result %+v", i, result)
+		}
+	}
+	if result, _, _ := s.sign(signer, &txRequest{Data: []byte{0x60}}); result == nil || result.Contract == nil || *result.Contract != crypto.CreateAddress(signer.Address(), 10) {
+		t.Errorf("contract address not reported")
+	}
+	// Missing defaults need a node
+	s.gasLimit = 0
+	if _, _, err := s.sign(signer, &txRequest{To: &to}); err == nil {
+		t.Errorf("gas estimated offline")
+	}
+	if err := s.send(nil); err != errOffline {
+		t.Errorf("send offline: have %v, want %v", err, errOffline)
+	}
+}
+
+// Tests that private transactions are signed with the vault signature values.
+func TestSignVaultTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := &keySigner{key: key}
+
+	tx := types.NewTransaction(0, common.Address{}, new(big.Int), 21000, big.NewInt(1), []byte{1})
+	signed, err := signer.SignVaultTx(tx)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if !signed.IsVault() {
+		t.Fatalf("signed transaction is not private")
+	}
+	if from, err := TxFrom(signed); err != nil || from != signer.Address() {
+		t.Errorf("sender mismatch: have %x, want %x (%v)", from, signer.Address(), err)
+	}
+}
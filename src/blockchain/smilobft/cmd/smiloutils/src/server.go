@@ -17,19 +17,13 @@
 package src
 
 import (
-	"context"
-	"math/big"
-	"time"
-
 	"gopkg.in/urfave/cli.v1"
-
-	"go-didux/src/blockchain/smilobft/ethclient"
 )
 
 var (
 	transactionFlag = cli.StringFlag{
 		Name:  "transaction",
-		Usage: "Hex string for Sport extraData",
+		Usage: "Hash of the transaction to replace",
 	}
 
 	connectionFlag = cli.StringFlag{
@@ -39,74 +33,256 @@ var (
 
 	timeoutFlag = cli.IntFlag{
 		Name:  "timeout",
-		Usage: "timeout in secs",
+		Usage: "timeout in secs of node calls and of waiting for receipts",
 		Value: 30,
 	}
 
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "Keystore file of the account signing transactions",
+	}
+
 	passphraseFlag = cli.StringFlag{
 		Name:  "passphrase",
-		Usage: "passphrase",
+		Usage: "Passphrase of the keystore file",
 		Value: "",
 	}
 
+	passwordFileFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "File holding the passphrase of the keystore file",
+	}
+
+	clefFlag = cli.StringFlag{
+		Name:  "clef",
+		Usage: "Clef endpoint signing transactions, eg: --clef=/path/to/clef.ipc",
+	}
+
+	fromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Account signing transactions through clef",
+	}
+
 	privatekeyFlag = cli.StringFlag{
 		Name:  "privatekey",
-		Usage: "privatekey",
+		Usage: "Hex private key signing transactions (deprecated, use --keystore)",
 		Value: "",
 	}
 
+	chainIDFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Chain id to sign for (default = asked to the node)",
+	}
+
+	nonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the (first) transaction (default = pending nonce of the node)",
+	}
+
+	gasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "Gas limit (default = estimated by the node)",
+	}
+
 	gaspriceFlag = cli.Uint64Flag{
 		Name:  "gasprice",
-		Usage: "gasprice",
+		Usage: "Gas price in wei (default = suggested by the node)",
 		Value: 0x00,
 	}
 
+	toFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Recipient address",
+	}
+
+	valueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Amount to transfer in wei",
+	}
+
+	dataFlag = cli.StringFlag{
+		Name:  "data",
+		Usage: "Hex payload, eg: contract bytecode or call data",
+	}
+
+	binFlag = cli.StringFlag{
+		Name:  "bin",
+		Usage: "File holding the hex contract bytecode",
+	}
+
+	abiFlag = cli.StringFlag{
+		Name:  "abi",
+		Usage: "File holding the contract ABI, to encode the arguments",
+	}
+
+	methodFlag = cli.StringFlag{
+		Name:  "method",
+		Usage: "Contract method to call",
+	}
+
+	sharedWithFlag = cli.StringSliceFlag{
+		Name:  "sharedwith",
+		Usage: "Vault public key to share a private transaction with (repeatable)",
+	}
+
+	fileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "JSON file holding the list of transactions",
+	}
+
+	sendFlag = cli.BoolFlag{
+		Name:  "send",
+		Usage: "Send the signed transactions through the node",
+	}
+
+	waitFlag = cli.BoolFlag{
+		Name:  "wait",
+		Usage: "Send the signed transactions and wait for their receipts",
+	}
+
+	nodeFlags = []cli.Flag{
+		connectionFlag,
+		timeoutFlag,
+	}
+
+	signerFlags = []cli.Flag{
+		keystoreFlag,
+		passphraseFlag,
+		passwordFileFlag,
+		clefFlag,
+		fromFlag,
+		privatekeyFlag,
+	}
+
+	buildFlags = []cli.Flag{
+		chainIDFlag,
+		nonceFlag,
+		gasFlag,
+		gaspriceFlag,
+		sendFlag,
+		waitFlag,
+	}
+
 	TransactionCommand = cli.Command{
 		Name:  "transaction",
 		Usage: "do things with a transaction",
+		Description: `
+Transactions are built and signed offline when the chain id, nonce, gas and gas
+price are all given, the node fills in the missing ones otherwise. Results are
+printed as JSON.`,
 		Subcommands: []cli.Command{
 			{
 				Action:    CancelTransaction,
 				Name:      "cancel",
 				Usage:     "cancel txid",
 				ArgsUsage: "<tx id>",
-				Flags: []cli.Flag{
-					connectionFlag,
+				Flags: flags(nodeFlags, signerFlags, []cli.Flag{
 					transactionFlag,
-					timeoutFlag,
-					passphraseFlag,
-					privatekeyFlag,
 					gaspriceFlag,
-				},
-				Description: `Cancel a transaction.`,
+					waitFlag,
+				}),
+				Description: `Cancel a pending transaction, replacing it with an empty transfer to self.`,
 			},
 			{
 				Action:    UpTransaction,
 				Name:      "up",
 				Usage:     "up gas for a txid",
 				ArgsUsage: "<tx id>",
-				Flags: []cli.Flag{
-					connectionFlag,
+				Flags: flags(nodeFlags, signerFlags, []cli.Flag{
 					transactionFlag,
-					timeoutFlag,
-					passphraseFlag,
-					privatekeyFlag,
 					gaspriceFlag,
-				},
-				Description: `Up a transaction.`,
+					waitFlag,
+				}),
+				Description: `Up the gas price of a pending transaction, replacing it with a copy.`,
+			},
+			{
+				Action: TransferTransaction,
+				Name:   "transfer",
+				Usage:  "build and sign a transfer",
+				Flags: flags(nodeFlags, signerFlags, buildFlags, []cli.Flag{
+					toFlag,
+					valueFlag,
+					dataFlag,
+				}),
+				Description: `Build and sign a value transfer.`,
+			},
+			{
+				Action:    DeployTransaction,
+				Name:      "deploy",
+				Usage:     "build and sign a contract deploy",
+				ArgsUsage: "[constructor args...]",
+				Flags: flags(nodeFlags, signerFlags, buildFlags, []cli.Flag{
+					valueFlag,
+					dataFlag,
+					binFlag,
+					abiFlag,
+					sharedWithFlag,
+				}),
+				Description: `
+Build and sign a contract deploy from its bytecode. Constructor arguments are
+encoded with the ABI, if given. Deploys shared with vault keys are private and
+always sent through the node.`,
+			},
+			{
+				Action:    CallTransaction,
+				Name:      "call",
+				Usage:     "build and sign a contract call",
+				ArgsUsage: "[method args...]",
+				Flags: flags(nodeFlags, signerFlags, buildFlags, []cli.Flag{
+					toFlag,
+					valueFlag,
+					dataFlag,
+					abiFlag,
+					methodFlag,
+					sharedWithFlag,
+				}),
+				Description: `
+Build and sign a contract call, from its call data or from the ABI, method and
+arguments. Calls shared with vault keys are private and always sent through the
+node.`,
+			},
+			{
+				Action:    SendTransactions,
+				Name:      "send",
+				Usage:     "send signed transactions",
+				ArgsUsage: "<raw tx hex...>",
+				Flags: flags(nodeFlags, []cli.Flag{
+					waitFlag,
+				}),
+				Description: `Send signed raw transactions through the node.`,
+			},
+			{
+				Action: BatchTransactions,
+				Name:   "batch",
+				Usage:  "build and sign a batch of transactions",
+				Flags: flags(nodeFlags, signerFlags, buildFlags, []cli.Flag{
+					fileFlag,
+				}),
+				Description: `
+Build and sign the transactions listed in a JSON file, with consecutive nonces:
+
+  [{"to": "0x...", "value": "1000", "data": "0x...", "gas": 21000, "gasPrice": "0", "sharedWith": []}]
+
+Sending stops at the first transaction rejected by the node, so that no nonce
+gap is left behind.`,
+			},
+			{
+				Action:      WaitTransactions,
+				Name:        "wait",
+				Usage:       "wait for transaction receipts",
+				ArgsUsage:   "<tx id...>",
+				Flags:       nodeFlags,
+				Description: `Wait until the transactions are mined and print their receipts.`,
 			},
 		},
 	}
-
-	client     *ethclient.Client
-	chainID    *big.Int
-	timeout    = 30 * time.Second
-	gasPrice   *big.Int
-	gasLimit   uint64
-	nonce      int64
-	privatekey string
 )
 
-func LocalContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), timeout)
+// flags concatenates lists of flags.
+func flags(lists ...[]cli.Flag) []cli.Flag {
+	var all []cli.Flag
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
 }
@@ -0,0 +1,110 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package src
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-didux/src/blockchain/smilobft/ethclient"
+	"go-didux/src/blockchain/smilobft/rpc"
+)
+
+// errOffline is returned when an operation needs a node but no connection was
+// given on the command line.
+var errOffline = errors.New("no node connection, set --connection")
+
+// session holds the node connection and the transaction defaults of a command,
+// replacing state that used to be kept in package globals. Its client is nil
+// when the command runs offline.
+type session struct {
+	rpc     *rpc.Client
+	client  *ethclient.Client
+	timeout time.Duration
+
+	chainID  *big.Int // Chain to sign for, from --chainid or the node
+	gasPrice *big.Int // Gas price to use, from --gasprice or suggested by the node
+	gasLimit uint64   // Gas limit to use, from --gas or estimated by the node
+
+	nonces map[common.Address]uint64 // Next nonce to use for each sender
+}
+
+// newSession creates a session from the command line flags, dialing the node if
+// one was given.
+func newSession(ctx *cli.Context) (*session, error) {
+	s := &session{
+		timeout: time.Duration(ctx.Int(timeoutFlag.Name)) * time.Second,
+		nonces:  make(map[common.Address]uint64),
+	}
+	if connection := ctx.String(connectionFlag.Name); connection != "" {
+		dialctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+
+		client, err := rpc.DialContext(dialctx, connection)
+		if err != nil {
+			return nil, fmt.Errorf("could not dial to Didux node: %v", err)
+		}
+		s.rpc, s.client = client, ethclient.NewClient(client)
+	}
+	if ctx.IsSet(chainIDFlag.Name) {
+		s.chainID = new(big.Int).SetUint64(ctx.Uint64(chainIDFlag.Name))
+	} else {
+		if s.client == nil {
+			return nil, errors.New("chain id unknown offline, set --chainid")
+		}
+		callctx, cancel := s.context()
+		defer cancel()
+
+		chainID, err := s.client.NetworkID(callctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get NetworkID of Didux node: %v", err)
+		}
+		s.chainID = chainID
+	}
+	if price := ctx.Uint64(gaspriceFlag.Name); price != 0 {
+		s.gasPrice = new(big.Int).SetUint64(price)
+	}
+	s.gasLimit = ctx.Uint64(gasFlag.Name)
+	return s, nil
+}
+
+// close releases the node connection, if any.
+func (s *session) close() {
+	if s.rpc != nil {
+		s.rpc.Close()
+	}
+}
+
+// context returns a context bounded by the session timeout, for a single call
+// to the node.
+func (s *session) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+// online returns errOffline if the session has no node connection.
+func (s *session) online() error {
+	if s.client == nil {
+		return errOffline
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package src
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-didux/src/blockchain/smilobft/accounts"
+	"go-didux/src/blockchain/smilobft/accounts/external"
+	"go-didux/src/blockchain/smilobft/accounts/keystore"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// errClefVault is returned when signing a private transaction with clef, which
+// only produces public transaction signatures.
+var errClefVault = errors.New("clef cannot sign private transactions, use --keystore")
+
+// txSigner signs the transactions built by the commands.
+type txSigner interface {
+	// Address returns the account signing the transactions.
+	Address() common.Address
+
+	// SignTx signs a public transaction for the given chain.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignVaultTx signs a private transaction, whose payload is a vault digest.
+	SignVaultTx(tx *types.Transaction) (*types.Transaction, error)
+}
+
+// keySigner signs with a private key held in memory.
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *keySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.key)
+}
+
+func (s *keySigner) SignVaultTx(tx *types.Transaction) (*types.Transaction, error) {
+	tx.SetVault()
+	return types.SignTx(tx, types.HomesteadSigner{}, s.key)
+}
+
+// clefSigner signs through an external clef instance.
+type clefSigner struct {
+	clef    *external.ExternalSigner
+	account accounts.Account
+}
+
+func (s *clefSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *clefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.clef.SignTx(s.account, tx, chainID)
+}
+
+func (s *clefSigner) SignVaultTx(tx *types.Transaction) (*types.Transaction, error) {
+	return nil, errClefVault
+}
+
+// openSigner creates the signer selected on the command line: a keystore file,
+// a clef endpoint or, for backwards compatibility, a raw private key.
+func openSigner(ctx *cli.Context) (txSigner, error) {
+	switch {
+	case ctx.String(keystoreFlag.Name) != "":
+		keyjson, err := ioutil.ReadFile(ctx.String(keystoreFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore file: %v", err)
+		}
+		passphrase, err := readPassphrase(ctx)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyjson, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore file: %v", err)
+		}
+		return &keySigner{key: key.PrivateKey}, nil
+
+	case ctx.String(clefFlag.Name) != "":
+		if !common.IsHexAddress(ctx.String(fromFlag.Name)) {
+			return nil, errors.New("clef needs the account to sign with, set --from")
+		}
+		clef, err := external.NewExternalSigner(ctx.String(clefFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to clef: %v", err)
+		}
+		account := accounts.Account{Address: common.HexToAddress(ctx.String(fromFlag.Name))}
+		if !clef.Contains(account) {
+			return nil, fmt.Errorf("clef does not manage account %s", account.Address.Hex())
+		}
+		return &clefSigner{clef: clef, account: account}, nil
+
+	case ctx.String(privatekeyFlag.Name) != "":
+		key, err := crypto.HexToECDSA(ctx.String(privatekeyFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		return &keySigner{key: key}, nil
+	}
+	return nil, errors.New("no signer, set --keystore or --clef")
+}
+
+// readPassphrase returns the keystore passphrase, read from --passwordfile or
+// given with --passphrase.
+func readPassphrase(ctx *cli.Context) (string, error) {
+	if file := ctx.String(passwordFileFlag.Name); file != "" {
+		blob, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file: %v", err)
+		}
+		return strings.TrimRight(string(blob), "\r\n"), nil
+	}
+	return ctx.String(passphraseFlag.Name), nil
+}
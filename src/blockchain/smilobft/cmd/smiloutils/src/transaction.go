@@ -18,153 +18,416 @@
 package src
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/orinocopay/go-etherutils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"gopkg.in/urfave/cli.v1"
 
 	"go-didux/src/blockchain/smilobft/core/types"
-	"go-didux/src/blockchain/smilobft/ethclient"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
-func GetValidTXGasPrice(ctx *cli.Context) (validTX *types.Transaction, gasprice int64, err error) {
-	transaction := ctx.String(transactionFlag.Name)
-	if len(transaction) == 0 {
-		return validTX, gasprice, cli.NewExitError("transaction is required", 1)
-	}
-
-	connection := ctx.String(connectionFlag.Name)
-	if len(connection) == 0 {
-		return validTX, gasprice, cli.NewExitError("connection is required", 1)
-	}
-	gasprice = ctx.Int64(gaspriceFlag.Name)
-
-	privatekey = ctx.String(privatekeyFlag.Name)
+// printJSON writes the result of a command to stdout.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
 
-	fmt.Println("Will cancel the transaction ", "transaction", transaction, "connection", connection)
+// exitError wraps an error into a failed exit of the command.
+func exitError(err error) error {
+	return cli.NewExitError(err.Error(), 1)
+}
 
-	client, err = ethclient.Dial(connection)
+// open creates the session and the signer of a command building transactions.
+func open(ctx *cli.Context) (*session, txSigner, error) {
+	s, err := newSession(ctx)
 	if err != nil {
-		return validTX, gasprice, cli.NewExitError("Could not dial to Didux node", 1)
+		return nil, nil, err
 	}
-
-	thisctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	chainID, err = client.NetworkID(thisctx)
+	signer, err := openSigner(ctx)
 	if err != nil {
-		return validTX, gasprice, cli.NewExitError("Could not get NetworkID of Didux node", 1)
+		s.close()
+		return nil, nil, err
 	}
+	if ctx.IsSet(nonceFlag.Name) {
+		s.setNonce(signer.Address(), ctx.Uint64(nonceFlag.Name))
+	}
+	return s, signer, nil
+}
 
-	fmt.Println("Connection to Didux node OK", "chainID", chainID, "transaction", transaction, "connection", connection)
-
-	tx := common.HexToHash(transaction)
-	thisctx, cancel = LocalContext()
-	defer cancel()
-
-	avalidTX, pending, err := client.TransactionByHash(thisctx, tx)
-
-	if avalidTX == nil || avalidTX.Hash() != tx || err != nil {
-		fmt.Println("failed to obtain transaction", tx.Hex(), err)
-		return validTX, gasprice, cli.NewExitError("Failed to obtain transaction", 1)
-	} else if !pending {
-		fmt.Printf("Transaction %s has already been mined \n", tx.Hex())
-		return validTX, gasprice, cli.NewExitError("Transaction %s has already been mined", 1)
+// readAddress returns the address given in a flag, nil if unset.
+func readAddress(ctx *cli.Context, flag cli.StringFlag) (*common.Address, error) {
+	hex := ctx.String(flag.Name)
+	if hex == "" {
+		return nil, nil
 	}
+	if !common.IsHexAddress(hex) {
+		return nil, fmt.Errorf("invalid --%s address %q", flag.Name, hex)
+	}
+	address := common.HexToAddress(hex)
+	return &address, nil
+}
 
-	//set it back
-	validTX = avalidTX
+// readData returns the hex payload given in a flag, or in a file.
+func readData(ctx *cli.Context, flag, fileFlag cli.StringFlag) ([]byte, error) {
+	hex := ctx.String(flag.Name)
+	if file := ctx.String(fileFlag.Name); file != "" {
+		blob, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --%s: %v", fileFlag.Name, err)
+		}
+		hex = strings.TrimSpace(string(blob))
+	}
+	if hex == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(hex, "0x") {
+		hex = "0x" + hex
+	}
+	data, err := hexutil.Decode(hex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %v", flag.Name, err)
+	}
+	return data, nil
+}
 
-	fmt.Println("validTX, ", validTX)
+// submit signs the transaction of a request, sends it and waits for its receipt
+// if asked to, and prints the outcome.
+func submit(ctx *cli.Context, req *txRequest) error {
+	s, signer, err := open(ctx)
+	if err != nil {
+		return exitError(err)
+	}
+	defer s.close()
 
-	return validTX, gasprice, nil
+	result, err := process(s, signer, req, ctx.Bool(sendFlag.Name) || ctx.Bool(waitFlag.Name))
+	if err != nil {
+		return exitError(err)
+	}
+	if ctx.Bool(waitFlag.Name) {
+		receipt, err := s.waitReceipt(result.Hash)
+		if err != nil {
+			return exitError(err)
+		}
+		result.Receipt = newReceiptResult(receipt)
+	}
+	return printJSON(result)
 }
 
-func ProcessValidTXAndGas(validTX *types.Transaction, gasprice int64, minGasPrice *big.Int, cmdStr string) error {
-	if gasprice == 0 {
-		// No gas price supplied; use the calculated minimum
-		gasPrice = minGasPrice
-	} else {
-		gasPrice = big.NewInt(gasprice)
-		// Gas price supplied; ensure it is at least 10% more than the current gas price
-		if gasPrice.Cmp(minGasPrice) >= 0 {
-			fmt.Printf("Gas price must be at least %s", etherutils.WeiToString(minGasPrice, true))
-			return cli.NewExitError(fmt.Sprintf("Gas price must be at least %s", etherutils.WeiToString(minGasPrice, true)), 1)
+// process signs the transaction of a request and sends it if asked to. Private
+// transactions are always sent, their payload only reaches the vault through
+// the node.
+func process(s *session, signer txSigner, req *txRequest, send bool) (*txResult, error) {
+	if len(req.SharedWith) > 0 {
+		return s.sendPrivate(signer, req)
+	}
+	result, tx, err := s.sign(signer, req)
+	if err != nil {
+		return nil, err
+	}
+	if send {
+		if err := s.send(tx); err != nil {
+			return nil, err
 		}
+		result.Sent = true
 	}
+	return result, nil
+}
 
-	// Create and sign the transaction
-	fromAddress, err := TxFrom(validTX)
+// TransferTransaction builds and signs a value transfer.
+func TransferTransaction(ctx *cli.Context) error {
+	to, err := readAddress(ctx, toFlag)
 	if err != nil {
-		fmt.Println("Failed to obtain from address")
-		return cli.NewExitError("Failed to obtain from address", 1)
-
+		return exitError(err)
 	}
+	if to == nil {
+		return exitError(errors.New("recipient is required, set --to"))
+	}
+	data, err := readData(ctx, dataFlag, cli.StringFlag{})
+	if err != nil {
+		return exitError(err)
+	}
+	return submit(ctx, &txRequest{To: to, Value: ctx.String(valueFlag.Name), Data: data})
+}
 
-	nonce = int64(validTX.Nonce())
-
-	signedTx, err := NewTransactionSigned(fromAddress, &fromAddress, nil, nil)
+// DeployTransaction builds and signs a contract deploy, public or private.
+func DeployTransaction(ctx *cli.Context) error {
+	code, err := readData(ctx, dataFlag, binFlag)
 	if err != nil {
-		fmt.Printf("Failed to createSignedTransaction %s", validTX.Hash().Hex())
-		return cli.NewExitError("Failed to createSignedTransaction", 1)
+		return exitError(err)
 	}
+	if len(code) == 0 {
+		return exitError(errors.New("contract bytecode is required, set --data or --bin"))
+	}
+	if file := ctx.String(abiFlag.Name); file != "" {
+		args, err := packCall(file, "", ctx.Args())
+		if err != nil {
+			return exitError(err)
+		}
+		code = append(code, args...)
+	} else if ctx.NArg() > 0 {
+		return exitError(errors.New("constructor arguments need the contract ABI, set --abi"))
+	}
+	return submit(ctx, &txRequest{
+		Value:      ctx.String(valueFlag.Name),
+		Data:       code,
+		SharedWith: ctx.StringSlice(sharedWithFlag.Name),
+	})
+}
 
-	thisctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+// CallTransaction builds and signs a contract call, public or private.
+func CallTransaction(ctx *cli.Context) error {
+	to, err := readAddress(ctx, toFlag)
+	if err != nil {
+		return exitError(err)
+	}
+	if to == nil {
+		return exitError(errors.New("contract is required, set --to"))
+	}
+	var data []byte
+	if file := ctx.String(abiFlag.Name); file != "" {
+		if ctx.String(methodFlag.Name) == "" {
+			return exitError(errors.New("method is required, set --method"))
+		}
+		data, err = packCall(file, ctx.String(methodFlag.Name), ctx.Args())
+	} else {
+		data, err = readData(ctx, dataFlag, cli.StringFlag{})
+	}
+	if err != nil {
+		return exitError(err)
+	}
+	return submit(ctx, &txRequest{
+		To:         to,
+		Value:      ctx.String(valueFlag.Name),
+		Data:       data,
+		SharedWith: ctx.StringSlice(sharedWithFlag.Name),
+	})
+}
 
-	fmt.Println("signedTx, ", signedTx)
-	err = client.SendTransaction(thisctx, signedTx)
+// SendTransactions sends signed raw transactions through the node.
+func SendTransactions(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return exitError(errors.New("no transaction to send"))
+	}
+	s, err := newSession(ctx)
 	if err != nil {
-		fmt.Println("Failed to send transaction, ", validTX.Hash().Hex(), err)
-		return cli.NewExitError("Signed transaction failed SendTransaction, ", 1)
+		return exitError(err)
 	}
+	defer s.close()
 
-	fmt.Println("cmd", "transaction",
-		"Command", cmdStr,
-		"Address", fromAddress.Hex(),
-		"NetworkID", chainID,
-		"Gas", signedTx.Gas(),
-		"Gas Price", signedTx.GasPrice().String(),
-		"Transaction Hash", signedTx.Hash().Hex(),
-	)
+	results := make([]*txResult, 0, ctx.NArg())
+	for _, arg := range ctx.Args() {
+		raw, err := hexutil.Decode(arg)
+		if err != nil {
+			return exitError(fmt.Errorf("invalid raw transaction %q: %v", arg, err))
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(raw, tx); err != nil {
+			return exitError(fmt.Errorf("invalid raw transaction %q: %v", arg, err))
+		}
+		if tx.IsVault() {
+			return exitError(fmt.Errorf("transaction %s is private, send it with deploy or call", tx.Hash().Hex()))
+		}
+		from, err := TxFrom(tx)
+		if err != nil {
+			return exitError(fmt.Errorf("invalid signature of %s: %v", tx.Hash().Hex(), err))
+		}
+		result, err := newTxResult(tx, from)
+		if err != nil {
+			return exitError(err)
+		}
+		if err := s.send(tx); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Sent = true
+		}
+		results = append(results, result)
+	}
+	if ctx.Bool(waitFlag.Name) {
+		waitResults(s, results)
+	}
+	return printJSON(results)
+}
 
+// BatchTransactions builds and signs the transactions of a batch file, with
+// consecutive nonces, and sends them if asked to.
+func BatchTransactions(ctx *cli.Context) error {
+	blob, err := ioutil.ReadFile(ctx.String(fileFlag.Name))
+	if err != nil {
+		return exitError(fmt.Errorf("failed to read batch file: %v", err))
+	}
+	var reqs []*txRequest
+	if err := json.Unmarshal(blob, &reqs); err != nil {
+		return exitError(fmt.Errorf("failed to parse batch file: %v", err))
+	}
+	s, signer, err := open(ctx)
+	if err != nil {
+		return exitError(err)
+	}
+	defer s.close()
+
+	send := ctx.Bool(sendFlag.Name) || ctx.Bool(waitFlag.Name)
+	results := make([]*txResult, 0, len(reqs))
+	for i, req := range reqs {
+		result, err := process(s, signer, req, send)
+		if err != nil {
+			// Later transactions would be stuck behind the missing nonce, stop here
+			results = append(results, &txResult{From: signer.Address(), To: req.To, Error: fmt.Sprintf("transaction %d: %v", i, err)})
+			break
+		}
+		results = append(results, result)
+	}
+	if ctx.Bool(waitFlag.Name) {
+		waitResults(s, results)
+	}
+	if err := printJSON(results); err != nil {
+		return err
+	}
+	if len(results) < len(reqs) || results[len(results)-1].Error != "" {
+		return cli.NewExitError("batch aborted", 1)
+	}
 	return nil
 }
 
-// CancelTransaction will cancel a transaction based on the tx and pk
-func CancelTransaction(ctx *cli.Context) error {
+// waitResults waits for the receipts of the sent transactions, recording them or
+// the failure to get them in the results.
+func waitResults(s *session, results []*txResult) {
+	for _, result := range results {
+		if !result.Sent {
+			continue
+		}
+		receipt, err := s.waitReceipt(result.Hash)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		result.Receipt = newReceiptResult(receipt)
+	}
+}
 
-	validTX, gasprice, err := GetValidTXGasPrice(ctx)
+// WaitTransactions waits for the receipts of transactions.
+func WaitTransactions(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return exitError(errors.New("no transaction to wait for"))
+	}
+	s, err := newSession(ctx)
 	if err != nil {
-		return cli.NewExitError("Could not get GetStuff with Didux node", 1)
+		return exitError(err)
 	}
+	defer s.close()
 
-	minGasPrice := big.NewInt(0).Add(big.NewInt(0).Add(validTX.GasPrice(), big.NewInt(0).Div(validTX.GasPrice(), big.NewInt(10))), big.NewInt(10))
+	receipts := make([]*receiptResult, 0, ctx.NArg())
+	for _, arg := range ctx.Args() {
+		hash, err := hexutil.Decode(arg)
+		if err != nil || len(hash) != common.HashLength {
+			return exitError(fmt.Errorf("invalid transaction hash %q", arg))
+		}
+		receipt, err := s.waitReceipt(common.BytesToHash(hash))
+		if err != nil {
+			return exitError(err)
+		}
+		receipts = append(receipts, newReceiptResult(receipt))
+	}
+	return printJSON(receipts)
+}
 
-	err = ProcessValidTXAndGas(validTX, gasprice, minGasPrice, "cancel")
+// replaceTransaction replaces a pending transaction of the signer with either an
+// empty transfer to self or a copy of it, paying a higher gas price.
+func replaceTransaction(ctx *cli.Context, cancel bool) error {
+	hex := ctx.String(transactionFlag.Name)
+	if hex == "" {
+		hex = ctx.Args().First()
+	}
+	hash, err := hexutil.Decode(hex)
+	if err != nil || len(hash) != common.HashLength {
+		return exitError(errors.New("transaction is required"))
+	}
+	s, signer, err := open(ctx)
 	if err != nil {
-		return cli.NewExitError("Could not get DoMoreSuff with Didux node", 1)
+		return exitError(err)
 	}
+	defer s.close()
 
-	return nil
-}
-
-// UpTransaction will up the gas for transaction based on the tx and pk
-func UpTransaction(ctx *cli.Context) error {
+	if err := s.online(); err != nil {
+		return exitError(err)
+	}
+	callctx, cancelCall := s.context()
+	defer cancelCall()
 
-	validTX, gasprice, err := GetValidTXGasPrice(ctx)
+	validTX, pending, err := s.client.TransactionByHash(callctx, common.BytesToHash(hash))
 	if err != nil {
-		return cli.NewExitError("Could not get GetValidTXGasPrice with Didux node", 1)
+		return exitError(fmt.Errorf("failed to obtain transaction %x: %v", hash, err))
 	}
-
+	if !pending {
+		return exitError(fmt.Errorf("transaction %x has already been mined", hash))
+	}
+	from, err := TxFrom(validTX)
+	if err != nil {
+		return exitError(fmt.Errorf("failed to obtain from address: %v", err))
+	}
+	if from != signer.Address() {
+		return exitError(fmt.Errorf("transaction sent by %s, not by the signer %s", from.Hex(), signer.Address().Hex()))
+	}
+	if validTX.IsVault() && !cancel {
+		return exitError(errors.New("the payload of private transactions cannot be resent, cancel it instead"))
+	}
+	// The pool only replaces a transaction paying at least 10% more
 	minGasPrice := big.NewInt(0).Add(big.NewInt(0).Add(validTX.GasPrice(), big.NewInt(0).Div(validTX.GasPrice(), big.NewInt(10))), big.NewInt(10))
 
-	err = ProcessValidTXAndGas(validTX, gasprice, minGasPrice, "up")
+	gasPrice := minGasPrice
+	if s.gasPrice != nil {
+		if s.gasPrice.Cmp(minGasPrice) < 0 {
+			return exitError(fmt.Errorf("gas price must be at least %s wei", minGasPrice))
+		}
+		gasPrice = s.gasPrice
+	}
+	var tx *types.Transaction
+	switch {
+	case cancel:
+		tx = types.NewTransaction(validTX.Nonce(), from, new(big.Int), params.TxGas, gasPrice, nil)
+	case validTX.To() == nil:
+		tx = types.NewContractCreation(validTX.Nonce(), validTX.Value(), validTX.Gas(), gasPrice, validTX.Data())
+	default:
+		tx = types.NewTransaction(validTX.Nonce(), *validTX.To(), validTX.Value(), validTX.Gas(), gasPrice, validTX.Data())
+	}
+	signed, err := signer.SignTx(tx, s.chainID)
 	if err != nil {
-		return cli.NewExitError("Could not ProcessValidTXAndGas with Didux node", 1)
+		return exitError(fmt.Errorf("failed to sign transaction: %v", err))
 	}
+	result, err := newTxResult(signed, from)
+	if err != nil {
+		return exitError(err)
+	}
+	if err := s.send(signed); err != nil {
+		return exitError(err)
+	}
+	result.Sent = true
 
-	return nil
+	if ctx.Bool(waitFlag.Name) {
+		receipt, err := s.waitReceipt(result.Hash)
+		if err != nil {
+			return exitError(err)
+		}
+		result.Receipt = newReceiptResult(receipt)
+	}
+	return printJSON(result)
+}
+
+// CancelTransaction will cancel a transaction based on the tx and pk
+func CancelTransaction(ctx *cli.Context) error {
+	return replaceTransaction(ctx, true)
+}
+
+// UpTransaction will up the gas for transaction based on the tx and pk
+func UpTransaction(ctx *cli.Context) error {
+	return replaceTransaction(ctx, false)
 }
@@ -67,6 +67,7 @@ import (
 	"go-didux/src/blockchain/smilobft/p2p/nat"
 	"go-didux/src/blockchain/smilobft/p2p/netutil"
 	"go-didux/src/blockchain/smilobft/params"
+	"go-didux/src/blockchain/smilobft/vault"
 	whisper "go-didux/src/blockchain/smilobft/whisper/whisperv6"
 )
 
@@ -772,6 +773,14 @@ var (
 		Usage: "Default minimum difference between two consecutive block's timestamps in seconds",
 		Value: eth.DefaultConfig.Sport.BlockPeriod,
 	}
+	SportEvictMisbehavingFlag = cli.BoolFlag{
+		Name:  "smilobft.evictmisbehaving",
+		Usage: "Include evidence of fullnodes signing conflicting consensus messages in proposed blocks, evicting them once the chain's misbehaviour eviction block is reached",
+	}
+	SportNoWALFlag = cli.BoolFlag{
+		Name:  "smilobft.nowal",
+		Usage: "Disable the consensus write-ahead log, a restarted fullnode then forgets the votes it sent for the height being decided",
+	}
 	SolcPathFlag = cli.StringFlag{
 		Name:  "solcpath",
 		Usage: "path to solc executable, if provided, enables eth.compile.solidity web3",
@@ -784,9 +793,19 @@ var (
 	}
 	MinBlocksEmptyMiningFlag = BigFlag{
 		Name:  "minblocksemptymining",
-		Usage: " Min Blocks to mine before Stop Mining Empty Blocks",
+		Usage: " Min Blocks to mine before Stop Mining Empty Blocks (ignored by chains pacing their empty blocks)",
 		Value: big.NewInt(20000000),
 	}
+
+	// Vault settings
+	VaultDriverFlag = cli.StringFlag{
+		Name:  "vault.driver",
+		Usage: "Private transaction manager driver (" + strings.Join(vault.Drivers(), ", ") + "), defaults to the VAULT_IPC environment variable",
+	}
+	VaultIPCFlag = cli.StringFlag{
+		Name:  "vault.ipc",
+		Usage: "Blackbox socket or configuration file used by the blackbox vault driver",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -1418,6 +1437,12 @@ func setSport(ctx *cli.Context, cfg *eth.Config) {
 	if ctx.GlobalIsSet(SportBlockPeriodFlag.Name) {
 		cfg.Sport.BlockPeriod = ctx.GlobalUint64(SportBlockPeriodFlag.Name)
 	}
+	if ctx.GlobalIsSet(SportEvictMisbehavingFlag.Name) {
+		cfg.Sport.EvictMisbehaving = ctx.GlobalBool(SportEvictMisbehavingFlag.Name)
+	}
+	if ctx.GlobalIsSet(SportNoWALFlag.Name) {
+		cfg.Sport.NoWAL = ctx.GlobalBool(SportNoWALFlag.Name)
+	}
 	if ctx.GlobalIsSet(SportEnableNodePermissionFlag.Name) {
 		cfg.SportEnableNodePermissionFlag = ctx.GlobalBool(SportEnableNodePermissionFlag.Name)
 	}
@@ -1429,6 +1454,15 @@ func setSport(ctx *cli.Context, cfg *eth.Config) {
 	}
 }
 
+func setVault(ctx *cli.Context, cfg *eth.Config) {
+	if ctx.GlobalIsSet(VaultDriverFlag.Name) {
+		cfg.Vault.Driver = ctx.GlobalString(VaultDriverFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultIPCFlag.Name) {
+		cfg.Vault.IPC = ctx.GlobalString(VaultIPCFlag.Name)
+	}
+}
+
 func setCodeQuality(ctx *cli.Context, cfg *eth.Config) {
 	if ctx.GlobalIsSet(SolcPathFlag.Name) {
 		cfg.SolcPath = ctx.GlobalString(SolcPathFlag.Name)
@@ -1511,6 +1545,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	setWhitelist(ctx, cfg)
 	setLes(ctx, cfg)
 	setSport(ctx, cfg)
+	setVault(ctx, cfg)
 	setCodeQuality(ctx, cfg)
 
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
@@ -112,8 +112,8 @@ type Engine interface {
 
 // Handler should be implemented is the consensus needs to handle and send peer's message
 type Handler interface {
-	// NewChainHead handles a new head block comes
-	NewChainHead() error
+	// NewChainHead handles a new head block of chain
+	NewChainHead(chain ChainReader) error
 
 	// HandleMsg handles a message from peer
 	HandleMsg(address common.Address, data p2p.Msg) (bool, error)
@@ -139,4 +139,8 @@ type SmiloBFT interface {
 
 	// Stop stops the engine
 	Stop() error
+
+	// ProposalPending reports whether a block proposal at the given height was
+	// handed to consensus, so the block sealed can no longer be replaced
+	ProposalPending(number *big.Int) bool
 }
@@ -26,6 +26,7 @@ import (
 	"math/big"
 
 	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/consensus/sport"
 	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/core/types"
 )
@@ -61,6 +62,87 @@ func (api *API) GetSnapshotAtHash(hash common.Hash) (*Snapshot, error) {
 	return api.smilo.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 }
 
+// Status returns the consensus state of the fullnode and the share of the recent
+// blocks sealed by every fullnode.
+func (api *API) Status() (*Status, error) {
+	api.smilo.coreMu.RLock()
+	started := api.smilo.coreStarted
+	api.smilo.coreMu.RUnlock()
+
+	status := &Status{}
+	if started {
+		status.Status = api.smilo.core.Status()
+	}
+	if status.Status == nil {
+		return nil, sport.ErrStoppedEngine
+	}
+	participation, blocks, err := api.smilo.sealParticipation(api.chain, api.chain.CurrentHeader(), participationWindow)
+	if err != nil {
+		return nil, err
+	}
+	status.Participation, status.ParticipationBlocks = participation, blocks
+	return status, nil
+}
+
+// GetMisbehaviour returns the evidence of fullnodes signing conflicting consensus
+// messages recorded by this node, only the evidence against offender if given.
+func (api *API) GetMisbehaviour(offender *common.Address) ([]*sport.Misbehaviour, error) {
+	return api.smilo.misbehaviours(offender)
+}
+
+// GetSignersFromBlock returns who proposed and sealed the given block, and the
+// round it was decided in.
+func (api *API) GetSignersFromBlock(number *rpc.BlockNumber) (*BlockSigners, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.smilo.blockSigners(api.chain, header)
+}
+
+// GetSignersFromBlockByHash returns who proposed and sealed the given block, and
+// the round it was decided in.
+func (api *API) GetSignersFromBlockByHash(hash common.Hash) (*BlockSigners, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.smilo.blockSigners(api.chain, header)
+}
+
+// GetFullnodeActivity returns how many blocks every fullnode proposed, sealed and
+// missed between the given blocks, both included.
+func (api *API) GetFullnodeActivity(from, to rpc.BlockNumber) (*Activity, error) {
+	head := api.chain.CurrentHeader().Number.Uint64()
+	resolve := func(number rpc.BlockNumber) uint64 {
+		if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+			return head
+		}
+		return uint64(number.Int64())
+	}
+	return api.smilo.fullnodeActivity(api.chain, resolve(from), resolve(to))
+}
+
+// GetTransitions returns the fullnode set changes scheduled for the epoch
+// checkpoint following the given block.
+func (api *API) GetTransitions(number *rpc.BlockNumber) (*Transitions, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.smilo.transitions(api.chain, header)
+}
+
 // Proposals (clique override) return a array of candidates that aim to become full-nodes (proposals)
 func (api *API) Proposals() map[common.Address]bool {
 	api.smilo.candidatesLock.RLock()
@@ -78,6 +160,11 @@ func (api *API) Propose(address common.Address, auth bool) {
 	api.smilo.candidatesLock.Lock()
 	defer api.smilo.candidatesLock.Unlock()
 
+	if registry := fullnodeRegistry(api.chain); registry != (common.Address{}) {
+		log.Error("Could not propose new candidate, fullnodes register in the fullnode contract", "contract", registry, "address", address, "auth", auth)
+		return
+	}
+
 	// BEGIN SMILO SPECIFICS
 	requireSmilos := new(big.Int).Mul(big.NewInt(api.smilo.config.MinFunds), big.NewInt(1e18))
 
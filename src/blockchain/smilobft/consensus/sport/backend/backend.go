@@ -44,6 +44,7 @@ func New(config *sport.Config, privateKey *ecdsa.PrivateKey, db ethdb.Database)
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	recentMessages, _ := lru.NewARC(inmemoryPeers)
 	knownMessages, _ := lru.NewARC(inmemoryMessages)
+	recentSealSigners, _ := lru.NewARC(inmemorySealSigners)
 	backend := &backend{
 		config:           config,
 		smilobftEventMux: new(event.TypeMux),
@@ -57,6 +58,8 @@ func New(config *sport.Config, privateKey *ecdsa.PrivateKey, db ethdb.Database)
 		coreStarted:      false,
 		recentMessages:   recentMessages,
 		knownMessages:    knownMessages,
+
+		recentSealSigners: recentSealSigners,
 	}
 	backend.core = smilobftcore.New(backend, backend.config)
 	return backend
@@ -74,6 +77,12 @@ func (sb *backend) Address() common.Address {
 	return sb.address
 }
 
+// Epoch returns the number of blocks between checkpoints, light clients sync
+// from the last checkpoint covered by their trusted checkpoint.
+func (sb *backend) Epoch() uint64 {
+	return sb.config.Epoch
+}
+
 func (sb *backend) Close() error {
 	return nil
 }
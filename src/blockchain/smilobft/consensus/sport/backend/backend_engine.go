@@ -20,6 +20,7 @@ package backend
 import (
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -34,13 +35,10 @@ import (
 
 	"go-didux/src/blockchain/smilobft/rpc"
 
-	"github.com/orinocopay/go-etherutils"
-
 	"golang.org/x/crypto/sha3"
 
 	"go-didux/src/blockchain/smilobft/consensus"
 	"go-didux/src/blockchain/smilobft/consensus/sport"
-	"go-didux/src/blockchain/smilobft/consensus/sport/fullnode"
 	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/core/state"
 	"go-didux/src/blockchain/smilobft/core/types"
@@ -76,6 +74,12 @@ var (
 	errInvalidUncleHash = errors.New("non empty uncle hash")
 	// errInvalidTimestamp is returned if the timestamp of a block is lower than the previous block's timestamp + the minimum block period.
 	errInvalidTimestamp = errors.New("invalid timestamp")
+	// errEarlyEmptyBlock is returned if a block without transactions is sealed before
+	// the empty block period since its parent is over.
+	errEarlyEmptyBlock = errors.New("empty block sealed before the empty block period")
+	// errPendingProposal is returned if a block is sealed at a height a proposal
+	// was already handed to the core for.
+	errPendingProposal = errors.New("proposal pending at block height")
 	// errInvalidVotingChain is returned if an authorization list is attempted to
 	// be modified via out-of-range or non-contiguous headers.
 	errInvalidVotingChain = errors.New("invalid voting chain")
@@ -86,6 +90,21 @@ var (
 	errInvalidCommittedSeals = errors.New("invalid committed seals")
 	// errEmptyCommittedSeals is returned if the field of committed seals is zero.
 	errEmptyCommittedSeals = errors.New("zero committed seals")
+	// errInvalidContractFullnodes is returned if the fullnodes recorded in a block
+	// differ from the ones registered in the fullnode contract.
+	errInvalidContractFullnodes = errors.New("fullnodes mismatch fullnode contract")
+	// errInvalidFullnodes is returned if the fullnodes recorded by a block differ
+	// from the voted fullnode set of its parent snapshot.
+	errInvalidFullnodes = errors.New("fullnodes mismatch snapshot")
+	// errInvalidNextFullnodes is returned if an epoch checkpoint does not record the
+	// fullnode set taking over after it, or another block records one.
+	errInvalidNextFullnodes = errors.New("invalid next fullnodes")
+	// errInvalidCheckpointVote is returned if an epoch checkpoint handing over the
+	// fullnode set casts a vote.
+	errInvalidCheckpointVote = errors.New("vote cast on fullnode set transition checkpoint")
+	// errInvalidMisbehaviour is returned if a block carries misbehaviour evidence
+	// that is invalid, stale, duplicated or against a non fullnode.
+	errInvalidMisbehaviour = errors.New("invalid misbehaviour evidence")
 	// errMismatchTxhashes is returned if the TxHash in header is mismatch.
 
 	errMismatchTxhashes = errors.New("mismatch transaction hashes")
@@ -106,30 +125,8 @@ var (
 
 	inmemoryAddresses  = 20 // Number of recent addresses from ecrecover
 	recentAddresses, _ = lru.NewARC(inmemoryAddresses)
-
-	// smiloTokenMetricsTable is the Block reward in wei to a fullnode when successfully mining a block
-	smiloTokenMetricsTable = map[*big.Int]*big.Int{
-		big.NewInt(20000000):   getSmiloValue("4000000000 gwei"), // 4 smilo
-		big.NewInt(40000000):   getSmiloValue("2000000000 gwei"), // 2 smilo
-		big.NewInt(60000000):   getSmiloValue("1750000000 gwei"), // 1.75 smilo
-		big.NewInt(80000000):   getSmiloValue("1500000000 gwei"), // 1.5 smilo
-		big.NewInt(100000000):  getSmiloValue("1250000000 gwei"), // 1.25 smilo
-		big.NewInt(120000000):  getSmiloValue("1000000000 gwei"), // 1 smilo
-		big.NewInt(140000000):  getSmiloValue("800000000 gwei"),  // 0.8 smilo
-		big.NewInt(160000000):  getSmiloValue("600000000 gwei"),  // 0.6 smilo
-		big.NewInt(180000000):  getSmiloValue("400000000 gwei"),  // 0.4 smilo
-		big.NewInt(200000000):  getSmiloValue("200000000 gwei"),  // 0.2 smilo
-		big.NewInt(400000000):  getSmiloValue("100000000 gwei"),  // 0.1 smilo
-		big.NewInt(800000000):  getSmiloValue("50000000 gwei"),   // 0.05 smilo
-		big.NewInt(1600000000): getSmiloValue("25000000 gwei"),   // 0.025 smilo
-	}
 )
 
-func getSmiloValue(value string) *big.Int {
-	v, _ := etherutils.StringToWei(value)
-	return v
-}
-
 // Author (clique override) retrieves the Ethereum address of the account that minted the given
 // block, which may be different from the header's coinbase if a consensus
 // engine is based on signatures.
@@ -206,14 +203,28 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	if parent.Time+sb.config.BlockPeriod > header.Time {
 		return errInvalidTimestamp
 	}
+	if header.TxHash == types.EmptyRootHash && header.Time < emptyBlockTime(chain, parent) {
+		return errEarlyEmptyBlock
+	}
 	// Verify fullnodes in extraData. Fullnodes in snapshot and extraData should be the same.
 	snap, err := sb.snapshot(chain, number-1, header.ParentHash, parents)
 	if err != nil {
 		return err
 	}
-	fullnodes := make([]byte, len(snap.fullnodes())*common.AddressLength)
-	for i, f := range snap.fullnodes() {
-		copy(fullnodes[i*common.AddressLength:], f[:])
+	if fullnodeRegistry(chain) == (common.Address{}) {
+		sportExtra, err := types.ExtractSportExtra(header)
+		if err != nil {
+			return err
+		}
+		if !sameFullnodes(sportExtra.Fullnodes, snap.fullnodes()) {
+			return errInvalidFullnodes
+		}
+	}
+	if err := verifyNextFullnodes(header, snap); err != nil {
+		return err
+	}
+	if err := verifyMisbehaviours(header, snap); err != nil {
+		return err
 	}
 	if err := sb.verifySigner(chain, header, parents); err != nil {
 		return err
@@ -296,7 +307,12 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 	var addresses []common.Address
 	var authorizes []bool
 
-	if sb.coreStarted {
+	checkpoint := number%sb.config.Epoch == 0 && snap.epochTransitions(number)
+	if fullnodeRegistry(chain) != (common.Address{}) {
+		log.Trace("Fullnodes are governed by the fullnode contract, not casting votes")
+	} else if checkpoint {
+		log.Trace("Fullnode set changes at the epoch checkpoint, not casting votes")
+	} else if sb.coreStarted {
 		statedb, _, err := sb.chain.State()
 		if err != nil {
 			log.Error("Could not Prepare candidates, got error with statedb", "error", err)
@@ -349,6 +365,24 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 	}
 	header.Extra = extra
 
+	// record the fullnode set taking over after the epoch checkpoint
+	if checkpoint {
+		if err := writeNextFullnodes(header, snap.nextFullnodes()); err != nil {
+			return err
+		}
+	}
+
+	// include the evidence against misbehaving fullnodes to evict them
+	misbehaviours, err := sb.pendingMisbehaviours(snap, number)
+	if err != nil {
+		return err
+	}
+	if len(misbehaviours) > 0 {
+		if err := writeMisbehaviours(header, misbehaviours); err != nil {
+			return err
+		}
+	}
+
 	// set header's timestamp
 	header.Time = parent.Time + sb.config.BlockPeriod
 	if int64(header.Time) < time.Now().Unix() {
@@ -371,7 +405,18 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 	//Will generate rewards for every block until block 40000000
 	//From this point on, ddd block rewards in Sport only if there is transactions in it
 	if header.Number.Cmp(big.NewInt(1)) > 0 && len(txs) > 0 || number < 40000000 {
-		AccumulateRewards(sb.config.CommunityAddress, state, header)
+		AccumulateRewards(sb.rewardsAt(chain.Config(), header.Number), state, header)
+	}
+
+	if registry := fullnodeRegistry(chain); registry != (common.Address{}) {
+		if chain.Config().Sport.IsMisbehaviourEviction(header.Number) {
+			if err := evictMisbehaving(header, state, registry); err != nil {
+				return nil, err
+			}
+		}
+		if err := commitFullnodes(header, state, registry); err != nil {
+			return nil, err
+		}
 	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
@@ -405,13 +450,28 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 		log.Error("Seal, Bail out ErrUnknownAncestor")
 		return nil, consensus.ErrUnknownAncestor
 	}
+	// Empty blocks either wait for the empty block period or for transactions
+	if len(block.Transactions()) == 0 {
+		if heartbeat := emptyBlockTime(chain, parent); heartbeat > 0 {
+			if header.Time < heartbeat {
+				log.Debug("Seal, holding back empty block until the empty block period is over", "number", number, "time", heartbeat)
+				header.Time = heartbeat
+				block = block.WithSeal(header)
+			}
+		} else if block.Number().Cmp(sb.config.MinBlocksEmptyMining) >= 0 {
+			log.Debug("Seal, Bail out errWaitTransactions", "MinBlocksEmptyMining", sb.config.MinBlocksEmptyMining)
+			return nil, errWaitTransactions
+		}
+	}
 	block, err = sb.updateBlock(parent, block)
 	if err != nil {
 		log.Error("Seal, Bail out updateBlock", "err", err)
 		return nil, err
 	}
 
-	// wait for the timestamp of header, use this to adjust the block period
+	// wait for the timestamp of header, use this to adjust the block period. Empty
+	// blocks are held back here until the empty block period is over, unless the
+	// worker replaces them by a block including newly arrived transactions.
 	delay := time.Unix(int64(block.Header().Time), 0).Sub(now())
 	select {
 	case <-time.After(delay):
@@ -420,12 +480,6 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 		return nil, nil
 	}
 
-	log.Trace("If we're mining, but nothing is being processed, wake on new transactions ? ", "MinBlocksEmptyMining", sb.config.MinBlocksEmptyMining, "BlockNum", block.Number(), "BlockNum Cmp MinBlocksMining", block.Number().Cmp(sb.config.MinBlocksEmptyMining))
-	if len(block.Transactions()) == 0 && block.Number().Cmp(sb.config.MinBlocksEmptyMining) >= 0 {
-		log.Debug("Seal, Bail out errWaitTransactions")
-		return nil, errWaitTransactions
-	}
-
 	// get the proposed block hash and clear it if the seal() is completed.
 	sb.sealMu.Lock()
 	sb.proposedBlockHash = block.Hash()
@@ -436,16 +490,23 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 	}
 	defer clear()
 
+	// A proposal handed to the core can't be replaced before it is committed,
+	// proposing another block at the same height would equivocate
+	select {
+	case <-stop:
+		return nil, nil
+	default:
+	}
+	if sb.ProposalPending(header.Number) {
+		log.Debug("Seal, Bail out errPendingProposal", "number", number)
+		return nil, errPendingProposal
+	}
+	atomic.StoreUint64(&sb.proposedNumber, number)
+
 	// post block into Sport engine
-	go func() {
-		requestEvent := sport.RequestEvent{
-			BlockProposal: block,
-		}
-		err := sb.EventMux().Post(requestEvent)
-		if err != nil {
-			log.Error("Seal, Could not send sport.RequestEvent message with block proposal, ", "RequestEvent", requestEvent)
-		}
-	}()
+	sb.postEvent(sport.RequestEvent{
+		BlockProposal: block,
+	})
 
 	for {
 		select {
@@ -466,18 +527,32 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 	}
 }
 
+// ProposalPending implements consensus.SmiloBFT.ProposalPending
+func (sb *backend) ProposalPending(number *big.Int) bool {
+	return atomic.LoadUint64(&sb.proposedNumber) == number.Uint64()
+}
+
 // APIs (clique override) returns the RPC APIs this consensus engine provides.
 func (sb *backend) APIs(chain consensus.ChainReader) []rpc.API {
+	api := &API{chain: chain, smilo: sb}
 	return []rpc.API{{
 		Namespace: "smilobft",
 		Version:   "1.0",
-		Service:   &API{chain: chain, smilo: sb},
+		Service:   api,
+		Public:    true,
+	}, {
+		Namespace: "sport",
+		Version:   "1.0",
+		Service:   api,
 		Public:    true,
 	}}
 }
 
 // snapshot (clique override) retrieves the authorization snapshot at a given point in time.
 func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
+	if registry := fullnodeRegistry(chain); registry != (common.Address{}) {
+		return sb.contractSnapshot(chain, registry, number, hash, parents)
+	}
 	// Search for a snapshot in memory or on disk for checkpoints
 	var (
 		headers []*types.Header
@@ -493,6 +568,9 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 		if number%checkpointInterval == 0 {
 			if s, err := loadSnapshot(sb.config.Epoch, sb.db, hash); err == nil {
 				log.Info("Loaded voting snapshot from disk", "number", number, "hash", hash, "fullnodes", s.fullnodes())
+				s.FullnodeSet = sb.newFullnodeSet(chain, number, s.fullnodes())
+				s.Transitions = epochTransitions(chain)
+				s.Evictions = misbehaviourEvictions(chain)
 				snap = s
 				break
 			}
@@ -507,7 +585,9 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 			if err != nil {
 				return nil, err
 			}
-			snap = newSnapshot(sb.config.Epoch, 0, genesis.Hash(), fullnode.NewFullnodeSet(sportExtra.Fullnodes, sb.config.SpeakerPolicy))
+			snap = newSnapshot(sb.config.Epoch, 0, genesis.Hash(), sb.newFullnodeSet(chain, 0, sportExtra.Fullnodes))
+			snap.Transitions = epochTransitions(chain)
+			snap.Evictions = misbehaviourEvictions(chain)
 			if err := snap.store(sb.db); err != nil {
 				log.Error("Could not store the genesis snapshot to disk!! ")
 				return nil, err
@@ -515,6 +595,22 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 			log.Info("Stored genesis voting snapshot to disk")
 			break
 		}
+		// If we're at an epoch checkpoint whose ancestors are unknown, as light
+		// clients syncing from a trusted checkpoint are, trust its fullnodes
+		if number%sb.config.Epoch == 0 && chain.GetHeaderByNumber(number-1) == nil {
+			if checkpoint := chain.GetHeader(hash, number); checkpoint != nil {
+				s, err := sb.checkpointSnapshot(chain, checkpoint)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.store(sb.db); err != nil {
+					return nil, err
+				}
+				log.Info("Stored checkpoint voting snapshot to disk", "number", number, "hash", hash, "fullnodes", s.fullnodes())
+				snap = s
+				break
+			}
+		}
 		// No snapshot for this header, gather the header and move backward
 		var header *types.Header
 		if len(parents) > 0 {
@@ -542,6 +638,9 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	if err != nil {
 		return nil, err
 	}
+	if len(headers) > 0 {
+		snap.FullnodeSet = sb.newFullnodeSet(chain, snap.Number, snap.fullnodes())
+	}
 	sb.recents.Add(snap.Hash, snap)
 
 	// If we've generated a new checkpoint snapshot, save to disk
@@ -20,7 +20,13 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 func TestBlockRewards(t *testing.T) {
@@ -188,7 +194,7 @@ func TestBlockRewards(t *testing.T) {
 	for _, test := range testCases {
 
 		t.Run(test.name, func(t *testing.T) {
-			reward := getSmiloBlockReward(test.blockNum)
+			reward := params.DefaultSportRewards(common.Big0, common.Address{}).BlockReward(test.blockNum)
 			//fmt.Println("reward", reward.Int64(), "blockNum", test.blockNum, "expectedReward", test.expectedReward)
 			require.Equal(t, test.expectedReward.Int64(), reward.Int64(), "Failed to get proper reward for block %d ", test.blockNum)
 
@@ -196,3 +202,39 @@ func TestBlockRewards(t *testing.T) {
 	}
 
 }
+
+func TestGenesisRewards(t *testing.T) {
+	community := common.HexToAddress("0x0000000000000000000000000000000000000c0c")
+	coinbase := common.HexToAddress("0x00000000000000000000000000000000000000cb")
+
+	_, engine := newBlockChain(1)
+	engine.config.CommunityAddress = "0x0000000000000000000000000000000000000bad"
+
+	config := &params.ChainConfig{Sport: &params.SportConfig{Rewards: []*params.SportRewards{{
+		Block:            big.NewInt(10),
+		Tiers:            []params.SportRewardTier{{Until: big.NewInt(100), Reward: big.NewInt(1000)}},
+		CommunityShare:   10,
+		CommunityAddress: community,
+	}}}}
+	testCases := []struct {
+		name      string
+		blockNum  *big.Int
+		coinbase  *big.Int
+		community common.Address
+		share     *big.Int
+	}{
+		{"legacy rewards before activation", big.NewInt(9), big.NewInt(4e18), common.HexToAddress(engine.config.CommunityAddress), big.NewInt(1e18)},
+		{"genesis rewards after activation", big.NewInt(10), big.NewInt(1000), community, big.NewInt(100)},
+		{"genesis rewards after last tier", big.NewInt(100), big.NewInt(0), community, big.NewInt(0)},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+			header := &types.Header{Number: test.blockNum, Coinbase: coinbase}
+
+			AccumulateRewards(engine.rewardsAt(config, header.Number), statedb, header)
+			require.Equal(t, test.coinbase, statedb.GetBalance(coinbase))
+			require.Equal(t, test.share, statedb.GetBalance(test.community))
+		})
+	}
+}
@@ -20,6 +20,7 @@ package backend
 import (
 	"bytes"
 	"math/big"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -27,9 +28,11 @@ import (
 
 	"go-didux/src/blockchain/smilobft/consensus"
 	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/fullnode"
 	"go-didux/src/blockchain/smilobft/consensus/sport/smilobftcore"
 	"go-didux/src/blockchain/smilobft/core/state"
 	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 // verifySigner checks whether the signer is in parent's fullnode set
@@ -118,37 +121,54 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 
 // AccumulateRewards (override from ethash) credits the coinbase of the given block with the mining reward.
 // The total reward consists of the static block reward and rewards for  the community.
-func AccumulateRewards(communityAddress string, state *state.StateDB, header *types.Header) {
+func AccumulateRewards(rewards *params.SportRewards, state *state.StateDB, header *types.Header) {
 	// add reward based on chain progression
-	blockReward := getSmiloBlockReward(header.Number)
+	blockReward := rewards.BlockReward(header.Number)
 
 	// Accumulate the rewards
-	reward := new(big.Int).Set(blockReward)
 	emptryAddress := common.Address{}
 	if header.Coinbase != emptryAddress {
 
-		log.Info("$$$$$$$$$$$$$$$$$$$$$ AccumulateRewards, block: ", "blockNum", header.Number.Int64(), "BlockReward", blockReward.Int64(), "Coinbase", header.Coinbase.Hex())
+		log.Info("$$$$$$$$$$$$$$$$$$$$$ AccumulateRewards, block: ", "blockNum", header.Number.Int64(), "BlockReward", blockReward, "Coinbase", header.Coinbase.Hex())
 
 		// Accumulate the rewards to community
-		if communityAddress != "" {
-			rewardForCommunity := new(big.Int).Div(blockReward, big.NewInt(4))
-			state.AddBalance(common.HexToAddress(communityAddress), rewardForCommunity, header.Number)
-			log.Info("$$$$$$$$$$$$$$$$$$$$$ AccumulateRewards, adding reward to community ", "rewardForCommunity", rewardForCommunity, "communityAddress", communityAddress)
+		if rewards.CommunityShare > 0 {
+			rewardForCommunity := rewards.CommunityReward(blockReward)
+			state.AddBalance(rewards.CommunityAddress, rewardForCommunity, header.Number)
+			log.Info("$$$$$$$$$$$$$$$$$$$$$ AccumulateRewards, adding reward to community ", "rewardForCommunity", rewardForCommunity, "communityAddress", rewards.CommunityAddress)
 		}
-		state.AddBalance(header.Coinbase, reward, header.Number)
+		state.AddBalance(header.Coinbase, blockReward, header.Number)
 	}
 }
 
-func getSmiloBlockReward(blockNum *big.Int) (blockReward *big.Int) {
-	blockReward = new(big.Int)
-	for maxBlockRange, reward := range smiloTokenMetricsTable {
-		if blockNum.Cmp(maxBlockRange) == -1 {
-			if reward.Cmp(blockReward) > 0 {
-				blockReward = reward
-			}
+// rewardsAt returns the block rewards in effect at block num. Until the genesis
+// reward schedule is activated, blocks keep the legacy rewards paying the
+// community share to the locally configured community address, so that existing
+// chains still sync. From the activation block on, the genesis pins the address.
+func (sb *backend) rewardsAt(config *params.ChainConfig, num *big.Int) *params.SportRewards {
+	if rewards := config.Sport.RewardsAt(num); rewards != nil {
+		return rewards
+	}
+	var community common.Address
+	if sb.config.CommunityAddress != "" {
+		community = common.HexToAddress(sb.config.CommunityAddress)
+	}
+	return params.DefaultSportRewards(common.Big0, community)
+}
+
+// newFullnodeSet creates the set of fullnodes authorizing the block after
+// number, selecting its speakers by the policy in effect at that block.
+func (sb *backend) newFullnodeSet(chain consensus.ChainReader, number uint64, fullnodes []common.Address) sport.FullnodeSet {
+	var (
+		policy  = sb.config.SpeakerPolicy
+		weights map[common.Address]uint64
+	)
+	if config := chain.Config(); config != nil {
+		if speakerPolicy := config.Sport.SpeakerPolicyAt(new(big.Int).SetUint64(number + 1)); speakerPolicy != nil {
+			policy, weights = sport.SpeakerPolicy(speakerPolicy.Policy), speakerPolicy.Weights
 		}
 	}
-	return blockReward
+	return fullnode.NewFullnodeSetAt(fullnodes, policy, weights, number+1)
 }
 
 // update timestamp and signature of the block based on its number of transactions
@@ -178,6 +198,7 @@ func (sb *backend) Start(chain consensus.ChainReader, currentBlock func() *types
 
 	// clear previous data
 	sb.proposedBlockHash = common.Hash{}
+	atomic.StoreUint64(&sb.proposedNumber, 0)
 	if sb.commitChBlock != nil {
 		close(sb.commitChBlock)
 	}
@@ -276,6 +297,129 @@ func writeSeal(h *types.Header, seal []byte) error {
 	return nil
 }
 
+// writeNextFullnodes writes the fullnode set taking over after an epoch checkpoint
+// into the extra-data field of its header.
+func writeNextFullnodes(h *types.Header, fullnodes []common.Address) error {
+	sportExtra, err := types.ExtractSportExtra(h)
+	if err != nil {
+		return err
+	}
+
+	sportExtra.NextFullnodes = make([]common.Address, len(fullnodes))
+	copy(sportExtra.NextFullnodes, fullnodes)
+
+	payload, err := rlp.EncodeToBytes(&sportExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.SportExtraVanity], payload...)
+	return nil
+}
+
+// writeMisbehaviours writes the encoded misbehaviour evidence into the extra-data
+// field of the given header.
+func writeMisbehaviours(h *types.Header, misbehaviours [][]byte) error {
+	sportExtra, err := types.ExtractSportExtra(h)
+	if err != nil {
+		return err
+	}
+
+	sportExtra.Misbehaviours = misbehaviours
+
+	payload, err := rlp.EncodeToBytes(&sportExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.SportExtraVanity], payload...)
+	return nil
+}
+
+// verifyNextFullnodes checks that an epoch checkpoint records the fullnode set of
+// the parent snapshot with the pending changes applied, and that other blocks
+// record none. Such checkpoints hand over the fullnode set and can't cast a vote.
+func verifyNextFullnodes(header *types.Header, snap *Snapshot) error {
+	sportExtra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return err
+	}
+	number := header.Number.Uint64()
+	if number%snap.Epoch == 0 && snap.epochTransitions(number) {
+		if sportExtra.NextFullnodes == nil || !sameFullnodes(sportExtra.NextFullnodes, snap.nextFullnodes()) {
+			return errInvalidNextFullnodes
+		}
+		if header.Coinbase != (common.Address{}) || header.Nonce != emptyNonce {
+			return errInvalidCheckpointVote
+		}
+		return nil
+	}
+	if sportExtra.NextFullnodes != nil {
+		return errInvalidNextFullnodes
+	}
+	return nil
+}
+
+// checkpointSnapshot returns the snapshot at an epoch checkpoint from the
+// fullnodes recorded in its extra-data, without looking at its ancestors. The
+// fullnodes taking over are recorded if changes wait for checkpoints, otherwise
+// the checkpoint is applied on top of the fullnodes that sealed it.
+func (sb *backend) checkpointSnapshot(chain consensus.ChainReader, checkpoint *types.Header) (*Snapshot, error) {
+	sportExtra, err := types.ExtractSportExtra(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	number := checkpoint.Number.Uint64()
+	if sportExtra.NextFullnodes != nil {
+		snap := newSnapshot(sb.config.Epoch, number, checkpoint.Hash(), sb.newFullnodeSet(chain, number, sportExtra.NextFullnodes))
+		snap.Transitions = epochTransitions(chain)
+		snap.Evictions = misbehaviourEvictions(chain)
+		return snap, nil
+	}
+	parent := newSnapshot(sb.config.Epoch, number-1, checkpoint.ParentHash, sb.newFullnodeSet(chain, number-1, sportExtra.Fullnodes))
+	parent.Transitions = epochTransitions(chain)
+	parent.Evictions = misbehaviourEvictions(chain)
+
+	snap, err := parent.apply([]*types.Header{checkpoint})
+	if err != nil {
+		return nil, err
+	}
+	snap.FullnodeSet = sb.newFullnodeSet(chain, number, snap.fullnodes())
+	return snap, nil
+}
+
+// epochTransitions returns the block from which fullnode set changes of the chain
+// wait for epoch checkpoints, nil if they never do.
+func epochTransitions(chain consensus.ChainReader) *big.Int {
+	if config := chain.Config(); config != nil {
+		return config.Sport.EpochTransitions()
+	}
+	return nil
+}
+
+// misbehaviourEvictions returns the block from which blocks of the chain may
+// evict misbehaving fullnodes, nil if they never do.
+func misbehaviourEvictions(chain consensus.ChainReader) *big.Int {
+	if config := chain.Config(); config != nil {
+		return config.Sport.MisbehaviourEviction()
+	}
+	return nil
+}
+
+// emptyBlockTime returns the earliest timestamp of an empty block following parent,
+// 0 if empty blocks are not paced.
+func emptyBlockTime(chain consensus.ChainReader, parent *types.Header) uint64 {
+	config := chain.Config()
+	if config == nil {
+		return 0
+	}
+	period := config.Sport.EmptyBlockPeriod(new(big.Int).Add(parent.Number, common.Big1))
+	if period == 0 {
+		return 0
+	}
+	return parent.Time + period
+}
+
 // writeCommittedSeals writes the extra-data field of a block header with given committed seals.
 func writeCommittedSeals(h *types.Header, committedSeals [][]byte) error {
 	if len(committedSeals) == 0 {
@@ -33,6 +33,7 @@ import (
 	"go-didux/src/blockchain/smilobft/consensus"
 	"go-didux/src/blockchain/smilobft/consensus/sport"
 	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 func TestPrepare(t *testing.T) {
@@ -497,3 +498,70 @@ func TestWriteCommittedSeals(t *testing.T) {
 		t.Errorf("error mismatch: have %v, want %v", err, errInvalidCommittedSeals)
 	}
 }
+
+func TestEmptyBlockPeriod(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	chain.Config().Sport.EmptyBlocks = &params.SportEmptyBlocks{Block: big.NewInt(1), Period: 30}
+	genesis := chain.Genesis()
+
+	// Empty blocks sealed before the period are rejected, others are not
+	header := makeBlockWithoutSeal(chain, engine, genesis).Header()
+	header.Time = genesis.Time() + 1
+	block, _ := engine.updateBlock(genesis.Header(), types.NewBlockWithHeader(header))
+	header = block.Header()
+	if err := engine.VerifyHeader(chain, header, false); err != errEarlyEmptyBlock {
+		t.Errorf("error mismatch: have %v, want %v", err, errEarlyEmptyBlock)
+	}
+	header.TxHash = cmn.StringToHash("123456789")
+	if err := engine.VerifyHeader(chain, header, false); err == errEarlyEmptyBlock {
+		t.Errorf("block with transactions rejected as early empty block")
+	}
+	header = block.Header()
+	header.Time = genesis.Time() + 30
+	if err := engine.VerifyHeader(chain, header, false); err == errEarlyEmptyBlock {
+		t.Errorf("empty block after the period rejected")
+	}
+
+	// Seal holds back empty blocks until the period is over
+	sealed, err := engine.Seal(chain, block, nil)
+	if err != nil {
+		t.Fatalf("failed to seal empty block: %v", err)
+	}
+	if sealed.Time() != genesis.Time()+30 {
+		t.Errorf("timestamp mismatch: have %d, want %d", sealed.Time(), genesis.Time()+30)
+	}
+
+	// The speaker is given the period to wait for transactions
+	if delay := engine.ProposalDelay(genesis); delay != 0 {
+		t.Errorf("proposal delay after old block: have %v, want 0", delay)
+	}
+	recent := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: uint64(now().Unix())})
+	if delay := engine.ProposalDelay(recent); delay <= 20*time.Second || delay > 30*time.Second {
+		t.Errorf("proposal delay after recent block: have %v, want about 30s", delay)
+	}
+}
+
+func TestSealPendingProposal(t *testing.T) {
+	chain, engine := newBlockChain(4)
+	block := makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	stop := make(chan struct{}, 1)
+	eventSub := engine.EventMux().Subscribe(sport.RequestEvent{})
+	go func() {
+		<-eventSub.Chan()
+		stop <- struct{}{}
+		eventSub.Unsubscribe()
+	}()
+	if _, err := engine.Seal(chain, block, stop); err != nil {
+		t.Fatalf("failed to propose block: %v", err)
+	}
+	if !engine.ProposalPending(block.Number()) {
+		t.Fatal("proposal handed to the core not pending")
+	}
+
+	// Another block at the same height must not be proposed while the first is pending
+	header := block.Header()
+	header.GasUsed = 1
+	if _, err := engine.Seal(chain, types.NewBlockWithHeader(header), nil); err != errPendingProposal {
+		t.Errorf("error mismatch: have %v, want %v", err, errPendingProposal)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/core/vm"
 	"go-didux/src/blockchain/smilobft/params"
+	"go-didux/src/blockchain/smilobft/rpc"
 )
 
 // in this test, we can set n to 1, and it means we can process Sport and commit a
@@ -140,3 +141,14 @@ func makeBlockWithoutSeal(chain *core.BlockChain, engine *backend, parent *types
 	block, _ := engine.Finalize(chain, header, state, nil, nil, nil)
 	return block
 }
+
+// dialAPIs serves the APIs of the engine in process, as the node does.
+func dialAPIs(chain *core.BlockChain, b *backend) *rpc.Client {
+	server := rpc.NewServer()
+	for _, api := range b.APIs(chain) {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			panic(err)
+		}
+	}
+	return rpc.DialInProc(server)
+}
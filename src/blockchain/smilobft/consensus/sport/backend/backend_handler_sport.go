@@ -69,16 +69,9 @@ func (sb *backend) HandleMsg(addr common.Address, msg p2p.Msg) (bool, error) {
 		}
 		sb.knownMessages.Add(hash, true)
 
-		go func() {
-			err := sb.smilobftEventMux.Post(sport.MessageEvent{
-				Payload: data,
-			})
-			if err != nil {
-				log.Error("Could not send sb.smilobftEventMux.Post, sport.MessageEvent", "err", err)
-			} else {
-				log.Error("Sent sb.smilobftEventMux.Post, sport.MessageEvent")
-			}
-		}()
+		sb.postEvent(sport.MessageEvent{
+			Payload: data,
+		})
 
 		return true, nil
 	}
@@ -115,18 +108,21 @@ func (sb *backend) SetBroadcaster(broadcaster consensus.Broadcaster) {
 	sb.broadcaster = broadcaster
 }
 
-func (sb *backend) NewChainHead() error {
+// NewChainHead implements consensus.Handler.NewChainHead, the seal signers of the
+// new head are indexed on every node, fullnode or not.
+func (sb *backend) NewChainHead(chain consensus.ChainReader) error {
+	go sb.chainHeadUpdated(chain)
+
 	sb.coreMu.RLock()
 	defer sb.coreMu.RUnlock()
 	if !sb.coreStarted {
 		return sport.ErrStoppedEngine
 	}
-	go func() {
-		err := sb.smilobftEventMux.Post(sport.FinalCommittedEvent{})
-		if err != nil {
-			log.Error("NewChainHead, Could not send FinalCommittedEvent, ", "err", err)
-		}
-
-	}()
+	sb.postEvent(sport.FinalCommittedEvent{})
 	return nil
 }
+
+// postEvent posts the event to the core without waiting for it to be taken.
+func (sb *backend) postEvent(ev interface{}) {
+	sb.config.Events.Post(sb.smilobftEventMux, ev)
+}
@@ -47,7 +47,7 @@ func (sb *backend) Broadcast(fullnodeSet sport.FullnodeSet, payload []byte) erro
 	msg := sport.MessageEvent{
 		Payload: payload,
 	}
-	go sb.smilobftEventMux.Post(msg)
+	sb.postEvent(msg)
 	return nil
 }
 
@@ -253,6 +253,22 @@ func (sb *backend) LastBlockProposal() (sport.BlockProposal, common.Address) {
 	return block, speaker
 }
 
+// ProposalDelay implements sport.Backend.ProposalDelay
+func (sb *backend) ProposalDelay(proposal sport.BlockProposal) time.Duration {
+	block, ok := proposal.(*types.Block)
+	if !ok || sb.chain == nil {
+		return 0
+	}
+	heartbeat := emptyBlockTime(sb.chain, block.Header())
+	if heartbeat == 0 {
+		return 0
+	}
+	if delay := time.Unix(int64(heartbeat), 0).Sub(now()); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
 // HasBadBlockProposal check if the hash has a bad block associated to it
 func (sb *backend) HasBadBlockProposal(hash common.Hash) bool {
 	if sb.hasBadBlock == nil {
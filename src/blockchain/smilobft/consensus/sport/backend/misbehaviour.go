@@ -0,0 +1,192 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/smilobftcore"
+	"go-didux/src/blockchain/smilobft/contracts/fullnodes"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// Misbehaviour evidence is carried in the extra-data of blocks, so that every
+// node verifies it and evicts the offenders at the same block: from the vote
+// snapshot, or from the fullnode registry when it governs the fullnode set.
+
+const (
+	dbKeyMisbehaviourPrefix = "smilobft-misbehaviour"
+)
+
+// ReportMisbehaviour implements sport.Backend.ReportMisbehaviour, it stores the
+// evidence and, if configured, includes it in the blocks proposed until the
+// offender is evicted.
+func (sb *backend) ReportMisbehaviour(evidence *sport.Misbehaviour) {
+	if err := smilobftcore.VerifyMisbehaviour(evidence); err != nil {
+		sb.logger.Error("Discarding invalid misbehaviour evidence", "evidence", evidence, "err", err)
+		return
+	}
+	blob, err := json.Marshal(evidence)
+	if err != nil {
+		sb.logger.Error("Failed to encode misbehaviour evidence", "evidence", evidence, "err", err)
+		return
+	}
+	hash := evidence.Hash()
+	if err := sb.db.Put(append([]byte(dbKeyMisbehaviourPrefix), hash[:]...), blob); err != nil {
+		sb.logger.Error("Failed to store misbehaviour evidence", "evidence", evidence, "err", err)
+		return
+	}
+	sb.logger.Warn("Recorded fullnode misbehaviour", "evidence", evidence, "hash", hash)
+}
+
+// misbehaviours returns the evidence stored in the database, only the evidence
+// against offender if it is not nil.
+func (sb *backend) misbehaviours(offender *common.Address) ([]*sport.Misbehaviour, error) {
+	it := sb.db.NewIteratorWithPrefix([]byte(dbKeyMisbehaviourPrefix))
+	defer it.Release()
+
+	evidences := []*sport.Misbehaviour{}
+	for it.Next() {
+		evidence := new(sport.Misbehaviour)
+		if err := json.Unmarshal(it.Value(), evidence); err != nil {
+			return nil, err
+		}
+		if offender == nil || evidence.Offender == *offender {
+			evidences = append(evidences, evidence)
+		}
+	}
+	return evidences, it.Error()
+}
+
+// pendingMisbehaviours returns the encoded evidence against the fullnodes of the
+// snapshot to include in the block of the given number, one per offender. None
+// is included before the chain's misbehaviour eviction block.
+func (sb *backend) pendingMisbehaviours(snap *Snapshot, number uint64) ([][]byte, error) {
+	if !sb.config.EvictMisbehaving || !snap.evictions(number) || (number%snap.Epoch == 0 && snap.epochTransitions(number)) {
+		return nil, nil
+	}
+	evidences, err := sb.misbehaviours(nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(evidences, func(i, j int) bool {
+		return bytes.Compare(evidences[i].Offender[:], evidences[j].Offender[:]) < 0
+	})
+	var (
+		included = make(map[common.Address]bool)
+		encoded  [][]byte
+	)
+	for _, evidence := range evidences {
+		// Leave at least one fullnode to seal blocks
+		if len(encoded)+1 >= snap.FullnodeSet.Size() {
+			break
+		}
+		if included[evidence.Offender] || !snap.checkVote(evidence.Offender, false) || checkMisbehaviour(evidence, snap, number) != nil {
+			continue
+		}
+		blob, err := rlp.EncodeToBytes(evidence)
+		if err != nil {
+			return nil, err
+		}
+		included[evidence.Offender] = true
+		encoded = append(encoded, blob)
+	}
+	return encoded, nil
+}
+
+// checkMisbehaviour checks that evidence included in the block of the given
+// number is valid, recent and against a fullnode of the parent snapshot.
+func checkMisbehaviour(evidence *sport.Misbehaviour, snap *Snapshot, number uint64) error {
+	if err := smilobftcore.VerifyMisbehaviour(evidence); err != nil {
+		return err
+	}
+	sequence := evidence.View.Sequence
+	if !sequence.IsUint64() || sequence.Uint64() >= number || number-sequence.Uint64() > snap.Epoch {
+		return errInvalidMisbehaviour
+	}
+	if _, fullnode := snap.FullnodeSet.GetByAddress(evidence.Offender); fullnode == nil {
+		return errInvalidMisbehaviour
+	}
+	return nil
+}
+
+// decodeMisbehaviours returns the evidence carried by the header.
+func decodeMisbehaviours(header *types.Header) ([]*sport.Misbehaviour, error) {
+	sportExtra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	evidences := make([]*sport.Misbehaviour, 0, len(sportExtra.Misbehaviours))
+	for _, blob := range sportExtra.Misbehaviours {
+		evidence := new(sport.Misbehaviour)
+		if err := rlp.DecodeBytes(blob, evidence); err != nil {
+			return nil, errInvalidMisbehaviour
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences, nil
+}
+
+// verifyMisbehaviours checks the evidence carried by the header against its
+// parent snapshot. Blocks before the chain's misbehaviour eviction block and
+// transition checkpoints carry none, like votes, and at least one fullnode is
+// left to seal blocks.
+func verifyMisbehaviours(header *types.Header, snap *Snapshot) error {
+	evidences, err := decodeMisbehaviours(header)
+	if err != nil {
+		return err
+	}
+	if len(evidences) == 0 {
+		return nil
+	}
+	number := header.Number.Uint64()
+	if !snap.evictions(number) || (number%snap.Epoch == 0 && snap.epochTransitions(number)) || len(evidences) >= snap.FullnodeSet.Size() {
+		return errInvalidMisbehaviour
+	}
+	offenders := make(map[common.Address]bool, len(evidences))
+	for _, evidence := range evidences {
+		if offenders[evidence.Offender] {
+			return errInvalidMisbehaviour
+		}
+		offenders[evidence.Offender] = true
+
+		if err := checkMisbehaviour(evidence, snap, number); err != nil {
+			return errInvalidMisbehaviour
+		}
+	}
+	return nil
+}
+
+// evictMisbehaving removes the offenders of the evidence carried by the header
+// from the fullnode registry.
+func evictMisbehaving(header *types.Header, statedb *state.StateDB, registry common.Address) error {
+	evidences, err := decodeMisbehaviours(header)
+	if err != nil {
+		return err
+	}
+	for _, evidence := range evidences {
+		fullnodes.Evict(statedb, registry, evidence.Offender)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/fullnode"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// commitCode is the message code of smilobftcore COMMIT messages.
+const commitCode uint64 = 6
+
+// signedCommit returns the payload of a COMMIT message for digest signed by key.
+func signedCommit(key *ecdsa.PrivateKey, view *sport.View, digest common.Hash) []byte {
+	subject, _ := rlp.EncodeToBytes(&sport.Subject{View: view, Digest: digest})
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	data, _ := rlp.EncodeToBytes([]interface{}{commitCode, subject, address, []byte{}, []byte{}})
+	sig, _ := crypto.Sign(crypto.Keccak256(data), key)
+	payload, _ := rlp.EncodeToBytes([]interface{}{commitCode, subject, address, sig, []byte{}})
+	return payload
+}
+
+func TestReportMisbehaviour(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	config := *engine.config
+	config.EvictMisbehaving = true
+	engine.config = &config
+
+	key, _ := crypto.GenerateKey()
+	offender := crypto.PubkeyToAddress(key.PublicKey)
+	view := &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)}
+
+	// Invalid evidence is discarded
+	engine.ReportMisbehaviour(&sport.Misbehaviour{
+		Offender: offender, Code: commitCode, View: view,
+		First:  signedCommit(key, view, common.Hash{1}),
+		Second: signedCommit(key, view, common.Hash{1}),
+	})
+	api := &API{chain: chain, smilo: engine}
+	if evidences, err := api.GetMisbehaviour(nil); err != nil || len(evidences) != 0 {
+		t.Fatalf("invalid evidence stored: %v, %v", evidences, err)
+	}
+
+	evidence := &sport.Misbehaviour{
+		Offender: offender, Code: commitCode, View: view,
+		First:  signedCommit(key, view, common.Hash{1}),
+		Second: signedCommit(key, view, common.Hash{2}),
+	}
+	engine.ReportMisbehaviour(evidence)
+
+	evidences, err := api.GetMisbehaviour(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve evidence: %v", err)
+	}
+	if len(evidences) != 1 || evidences[0].Hash() != evidence.Hash() {
+		t.Fatalf("evidence mismatch: have %v, want %v", evidences, evidence)
+	}
+	if evidences, _ := api.GetMisbehaviour(&common.Address{1}); len(evidences) != 0 {
+		t.Errorf("evidence returned for another fullnode: %v", evidences)
+	}
+	// The evidence is served in the sport namespace next to the other methods
+	client := dialAPIs(chain, engine)
+	defer client.Close()
+
+	var served []interface{}
+	if err := client.Call(&served, "sport_getMisbehaviour", nil); err != nil || len(served) != 1 {
+		t.Errorf("sport_getMisbehaviour: have %v, %v, want 1 evidence", served, err)
+	}
+	var snap interface{}
+	if err := client.Call(&snap, "sport_getSnapshot", nil); err != nil || snap == nil {
+		t.Errorf("sport_getSnapshot: have %v, %v", snap, err)
+	}
+}
+
+// Tests that evidence carried in a block is verified against the parent
+// snapshot and evicts the offender once applied.
+func TestMisbehaviourEviction(t *testing.T) {
+	_, engine := newBlockChain(1)
+	config := *engine.config
+	config.EvictMisbehaving = true
+	engine.config = &config
+
+	accounts := newTesterAccountPool()
+	a, b, c := accounts.address("A"), accounts.address("B"), accounts.address("C")
+	snap := newSnapshot(10, 1, common.Hash{}, fullnode.NewFullnodeSet([]common.Address{a, b, c}, sport.RoundRobin))
+
+	view := &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)}
+	evidence := &sport.Misbehaviour{
+		Offender: c, Code: commitCode, View: view,
+		First:  signedCommit(accounts.accounts["C"], view, common.Hash{1}),
+		Second: signedCommit(accounts.accounts["C"], view, common.Hash{2}),
+	}
+	engine.ReportMisbehaviour(evidence)
+
+	// No evidence is included before the chain's misbehaviour eviction block
+	snap.Evictions = big.NewInt(3)
+	if misbehaviours, _ := engine.pendingMisbehaviours(snap, 2); len(misbehaviours) != 0 {
+		t.Fatalf("evidence included before the eviction block: %v", misbehaviours)
+	}
+	snap.Evictions = big.NewInt(2)
+
+	misbehaviours, err := engine.pendingMisbehaviours(snap, 2)
+	if err != nil || len(misbehaviours) != 1 {
+		t.Fatalf("evidence not included: %v, %v", misbehaviours, err)
+	}
+	header := func(number int64, misbehaviours [][]byte) *types.Header {
+		h := &types.Header{Number: big.NewInt(number), ParentHash: snap.Hash, Difficulty: defaultDifficulty, MixDigest: types.SportDigest}
+		h.Extra, _ = prepareExtra(h, snap.fullnodes())
+		if len(misbehaviours) > 0 {
+			writeMisbehaviours(h, misbehaviours)
+		}
+		accounts.sign(h, "A")
+		return h
+	}
+	if err := verifyMisbehaviours(header(2, misbehaviours), snap); err != nil {
+		t.Fatalf("valid evidence rejected: %v", err)
+	}
+	early := snap.copy()
+	early.Evictions = big.NewInt(3)
+	if err := verifyMisbehaviours(header(2, misbehaviours), early); err != errInvalidMisbehaviour {
+		t.Errorf("evidence before the eviction block: error mismatch: have %v, want %v", err, errInvalidMisbehaviour)
+	}
+	if applied, _ := early.apply([]*types.Header{header(2, misbehaviours)}); len(applied.fullnodes()) != 3 {
+		t.Errorf("fullnode evicted before the eviction block: %x", applied.fullnodes())
+	}
+	// Duplicated and stale evidence, and evidence against others are rejected
+	other, _ := crypto.GenerateKey()
+	foreign := &sport.Misbehaviour{
+		Offender: crypto.PubkeyToAddress(other.PublicKey), Code: commitCode, View: view,
+		First:  signedCommit(other, view, common.Hash{1}),
+		Second: signedCommit(other, view, common.Hash{2}),
+	}
+	blob, _ := rlp.EncodeToBytes(foreign)
+	for i, h := range []*types.Header{
+		header(2, append(misbehaviours, misbehaviours[0])),
+		header(12, misbehaviours),
+		header(2, [][]byte{blob}),
+	} {
+		if err := verifyMisbehaviours(h, snap); err != errInvalidMisbehaviour {
+			t.Errorf("header %d: error mismatch: have %v, want %v", i, err, errInvalidMisbehaviour)
+		}
+	}
+	// Applying the block evicts the offender
+	applied, err := snap.apply([]*types.Header{header(2, misbehaviours)})
+	if err != nil {
+		t.Fatalf("failed to apply evidence: %v", err)
+	}
+	if want := sortFullnodes([]common.Address{a, b}); !reflect.DeepEqual(applied.fullnodes(), want) {
+		t.Errorf("fullnodes mismatch: have %x, want %x", applied.fullnodes(), want)
+	}
+	if misbehaviours, _ := engine.pendingMisbehaviours(applied, 3); len(misbehaviours) != 0 {
+		t.Errorf("evidence against an evicted fullnode included: %v", misbehaviours)
+	}
+}
@@ -20,6 +20,7 @@ package backend
 import (
 	"crypto/ecdsa"
 	"encoding/json"
+	"math/big"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -52,6 +53,7 @@ type backend struct {
 	commitChBlock     chan *types.Block
 	proposedBlockHash common.Hash
 	sealMu            sync.Mutex
+	proposedNumber    uint64 // height of the last proposal handed to the core, accessed atomically
 	coreStarted       bool
 	coreMu            sync.RWMutex
 
@@ -67,6 +69,8 @@ type backend struct {
 
 	recentMessages *lru.ARCCache // the cache of peer's messages
 	knownMessages  *lru.ARCCache // the cache of self messages
+
+	recentSealSigners *lru.ARCCache // the cache of committed seal signers of recent blocks
 }
 
 // ----------------------------------------------------------------------------
@@ -100,6 +104,10 @@ type Snapshot struct {
 	Votes       []*Vote                  // List of votes cast in chronological order
 	Tally       map[common.Address]Tally // Current vote tally to avoid recalculating
 	FullnodeSet sport.FullnodeSet        // Set of authorized fullnodes at this moment
+	Pending     map[common.Address]bool  // Fullnode set changes waiting for the next epoch checkpoint
+
+	Transitions *big.Int // Block from which fullnode set changes wait for epoch checkpoints, nil if never
+	Evictions   *big.Int // Block from which misbehaving fullnodes are evicted, nil if never
 }
 
 // ----------------------------------------------------------------------------
@@ -111,6 +119,8 @@ type snapshotJSON struct {
 	Votes  []*Vote                  `json:"votes"`
 	Tally  map[common.Address]Tally `json:"tally"`
 
+	Pending map[common.Address]bool `json:"pending,omitempty"`
+
 	// for fullnode set
 	Fullnodes []common.Address    `json:"fullnodes"`
 	Policy    sport.SpeakerPolicy `json:"policy"`
@@ -123,6 +133,7 @@ func (s *Snapshot) toJSONStruct() *snapshotJSON {
 		Hash:      s.Hash,
 		Votes:     s.Votes,
 		Tally:     s.Tally,
+		Pending:   s.Pending,
 		Fullnodes: s.fullnodes(),
 		Policy:    s.FullnodeSet.Policy(),
 	}
@@ -140,6 +151,7 @@ func (s *Snapshot) UnmarshalJSON(b []byte) error {
 	s.Hash = j.Hash
 	s.Votes = j.Votes
 	s.Tally = j.Tally
+	s.Pending = j.Pending
 	s.FullnodeSet = fullnode.NewFullnodeSet(j.Fullnodes, j.Policy)
 	return nil
 }
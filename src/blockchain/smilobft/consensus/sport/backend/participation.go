@@ -0,0 +1,177 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/smilobftcore"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+const (
+	// participationWindow is the number of recent blocks seal participation is
+	// measured over.
+	participationWindow = 100
+
+	// inmemorySealSigners is the number of blocks whose seal signers are cached.
+	inmemorySealSigners = 2 * participationWindow
+
+	// dbKeySignersPrefix + hash -> committed seal signers of a canonical block
+	dbKeySignersPrefix = "smilobft-signers"
+)
+
+// participationGauge is the share of the recent blocks sealed, averaged over the
+// fullnodes, the gauges of every fullnode are registered below it.
+var participationGauge = metrics.NewRegisteredGaugeFloat64("consensus/sport/participation", nil)
+
+// Status is the consensus state of the fullnode returned by sport_status.
+type Status struct {
+	*smilobftcore.Status
+	Participation       map[common.Address]float64 `json:"participation"`       // Share of the recent blocks sealed by every fullnode
+	ParticipationBlocks uint64                     `json:"participationBlocks"` // Number of recent blocks the participation is measured over
+}
+
+// sealSigners returns the fullnodes whose committed seal is in the header, from
+// the index if the block was indexed on becoming the chain head.
+func (sb *backend) sealSigners(header *types.Header) ([]common.Address, error) {
+	hash := header.Hash()
+	if signers, ok := sb.recentSealSigners.Get(hash); ok {
+		return signers.([]common.Address), nil
+	}
+	if blob, err := sb.db.Get(append([]byte(dbKeySignersPrefix), hash[:]...)); err == nil {
+		var signers []common.Address
+		if err := rlp.DecodeBytes(blob, &signers); err == nil {
+			sb.recentSealSigners.Add(hash, signers)
+			return signers, nil
+		}
+	}
+	extra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	proposalSeal := smilobftcore.PrepareCommittedSeal(hash)
+
+	signers := make([]common.Address, 0, len(extra.CommittedSeal))
+	for _, seal := range extra.CommittedSeal {
+		addr, err := sport.GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return nil, errInvalidSignature
+		}
+		signers = append(signers, addr)
+	}
+	sb.recentSealSigners.Add(hash, signers)
+	return signers, nil
+}
+
+// indexSealSigners indexes the seal signers of a new chain head and of its
+// ancestors not indexed yet, at most a participation window of them. Heads of
+// batch imports are only announced once, the ancestors fill the gap.
+func (sb *backend) indexSealSigners(chain consensus.ChainReader, head *types.Header) {
+	for header, n := head, 0; header != nil && header.Number.Sign() > 0 && n < participationWindow; n++ {
+		key := append([]byte(dbKeySignersPrefix), header.Hash().Bytes()...)
+		if ok, _ := sb.db.Has(key); ok {
+			return
+		}
+		signers, err := sb.sealSigners(header)
+		if err != nil {
+			sb.logger.Debug("Failed to recover seal signers", "number", header.Number, "hash", header.Hash(), "err", err)
+			return
+		}
+		blob, err := rlp.EncodeToBytes(signers)
+		if err != nil {
+			sb.logger.Error("Failed to encode seal signers", "hash", header.Hash(), "err", err)
+			return
+		}
+		if err := sb.db.Put(key, blob); err != nil {
+			sb.logger.Error("Failed to store seal signers", "hash", header.Hash(), "err", err)
+			return
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+}
+
+// chainHeadUpdated indexes the seal signers of the new chain head and refreshes
+// the seal participation metrics.
+func (sb *backend) chainHeadUpdated(chain consensus.ChainReader) {
+	head := chain.CurrentHeader()
+	if head == nil {
+		return
+	}
+	sb.indexSealSigners(chain, head)
+	sb.updateParticipationMetrics(chain, head)
+}
+
+// sealParticipation returns the share of the blocks in the window ending at head
+// carrying the committed seal of every fullnode of head, and the number of blocks
+// in the window.
+func (sb *backend) sealParticipation(chain consensus.ChainReader, head *types.Header, window uint64) (map[common.Address]float64, uint64, error) {
+	snap, err := sb.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	sealed := make(map[common.Address]uint64)
+	for _, fullnode := range snap.fullnodes() {
+		sealed[fullnode] = 0
+	}
+	var blocks uint64
+	for header := head; header != nil && header.Number.Sign() > 0 && blocks < window; blocks++ {
+		signers, err := sb.sealSigners(header)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, signer := range signers {
+			if _, ok := sealed[signer]; ok {
+				sealed[signer]++
+			}
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	participation := make(map[common.Address]float64, len(sealed))
+	for fullnode, count := range sealed {
+		participation[fullnode] = 0
+		if blocks > 0 {
+			participation[fullnode] = float64(count) / float64(blocks)
+		}
+	}
+	return participation, blocks, nil
+}
+
+// updateParticipationMetrics refreshes the seal participation gauges with the
+// blocks up to the head of the chain.
+func (sb *backend) updateParticipationMetrics(chain consensus.ChainReader, head *types.Header) {
+	if !metrics.Enabled {
+		return
+	}
+	participation, _, err := sb.sealParticipation(chain, head, participationWindow)
+	if err != nil {
+		sb.logger.Debug("Failed to measure seal participation", "err", err)
+		return
+	}
+	var total float64
+	for fullnode, rate := range participation {
+		metrics.GetOrRegisterGaugeFloat64("consensus/sport/participation/"+fullnode.Hex(), nil).Update(rate)
+		total += rate
+	}
+	if len(participation) > 0 {
+		participationGauge.Update(total / float64(len(participation)))
+	}
+}
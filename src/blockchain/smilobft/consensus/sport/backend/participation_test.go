@@ -0,0 +1,61 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"testing"
+
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+func TestStatus(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	api := &API{chain: chain, smilo: engine}
+
+	parent := chain.Genesis()
+	for i := 0; i < 2; i++ {
+		block := makeBlock(chain, engine, parent)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", i, err)
+		}
+		parent = block
+	}
+	status, err := api.Status()
+	if err != nil {
+		t.Fatalf("failed to retrieve status: %v", err)
+	}
+	if status.Sequence == nil || status.Speaker != engine.address || !status.IsSpeaker {
+		t.Errorf("consensus state mismatch: %+v", status.Status)
+	}
+	if status.ParticipationBlocks != 2 {
+		t.Errorf("participation window mismatch: have %d, want 2", status.ParticipationBlocks)
+	}
+	if rate := status.Participation[engine.address]; rate != 1 || len(status.Participation) != 1 {
+		t.Errorf("participation mismatch: have %v, want 1 for %x", status.Participation, engine.address)
+	}
+
+	// Participation is measured over the window only
+	participation, blocks, err := engine.sealParticipation(chain, chain.CurrentHeader(), 1)
+	if err != nil || blocks != 1 || participation[engine.address] != 1 {
+		t.Errorf("windowed participation mismatch: have %v over %d blocks, err %v", participation, blocks, err)
+	}
+
+	engine.Stop()
+	if _, err := api.Status(); err == nil {
+		t.Errorf("status returned by stopped engine")
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+const (
+	// maxActivityBlocks is the largest range of blocks activity is reported for.
+	maxActivityBlocks = 10000
+)
+
+var (
+	// errInvalidBlockRange is returned if an activity range is empty or too large.
+	errInvalidBlockRange = errors.New("invalid block range")
+)
+
+// BlockSigners tells who proposed and sealed a block.
+type BlockSigners struct {
+	Number     uint64           `json:"number"`
+	Hash       common.Hash      `json:"hash"`
+	Author     common.Address   `json:"author"`     // Speaker that proposed the block
+	Committers []common.Address `json:"committers"` // Fullnodes whose committed seal is in the block
+	Round      uint64           `json:"round"`      // Round the block was decided in, modulo the number of fullnodes
+}
+
+// FullnodeActivity is the participation of a fullnode in a range of blocks.
+type FullnodeActivity struct {
+	Proposed   uint64 `json:"proposed"`   // Blocks proposed by the fullnode
+	Sealed     uint64 `json:"sealed"`     // Blocks carrying the committed seal of the fullnode
+	Missed     uint64 `json:"missed"`     // Blocks the fullnode could seal but did not
+	LastSealed uint64 `json:"lastSealed"` // Last block sealed by the fullnode, 0 if none
+}
+
+// Activity is the participation of the fullnodes in a range of blocks.
+type Activity struct {
+	From        uint64                               `json:"from"`
+	To          uint64                               `json:"to"`
+	RoundChange uint64                               `json:"roundChange"` // Blocks not decided in their first round
+	Fullnodes   map[common.Address]*FullnodeActivity `json:"fullnodes"`
+}
+
+// blockRound returns the first round whose speaker is the author of the header.
+// Speakers repeat after as many rounds as there are fullnodes, so the round is
+// only known modulo their number.
+func (sb *backend) blockRound(chain consensus.ChainReader, header *types.Header, author common.Address) (uint64, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return 0, nil
+	}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
+	}
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return 0, err
+	}
+	var lastSpeaker common.Address
+	if number > 1 {
+		if lastSpeaker, err = ecrecover(parent); err != nil {
+			return 0, err
+		}
+	}
+	fullnodes := snap.FullnodeSet.Copy()
+	for round := uint64(0); round < uint64(fullnodes.Size()); round++ {
+		fullnodes.CalcSpeaker(lastSpeaker, round)
+		if fullnodes.IsSpeaker(author) {
+			return round, nil
+		}
+	}
+	return 0, errUnauthorized
+}
+
+// blockSigners returns who proposed and sealed the block of the header.
+func (sb *backend) blockSigners(chain consensus.ChainReader, header *types.Header) (*BlockSigners, error) {
+	signers := &BlockSigners{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		Committers: []common.Address{},
+	}
+	if signers.Number == 0 {
+		return signers, nil
+	}
+	author, err := ecrecover(header)
+	if err != nil {
+		return nil, err
+	}
+	committers, err := sb.sealSigners(header)
+	if err != nil {
+		return nil, err
+	}
+	round, err := sb.blockRound(chain, header, author)
+	if err != nil {
+		return nil, err
+	}
+	signers.Author, signers.Committers, signers.Round = author, committers, round
+	return signers, nil
+}
+
+// fullnodeActivity returns the participation of the fullnodes in the canonical
+// blocks from and to, both included.
+func (sb *backend) fullnodeActivity(chain consensus.ChainReader, from, to uint64) (*Activity, error) {
+	if from > to || to-from >= maxActivityBlocks {
+		return nil, errInvalidBlockRange
+	}
+	activity := &Activity{From: from, To: to, Fullnodes: make(map[common.Address]*FullnodeActivity)}
+	fullnode := func(addr common.Address) *FullnodeActivity {
+		if activity.Fullnodes[addr] == nil {
+			activity.Fullnodes[addr] = new(FullnodeActivity)
+		}
+		return activity.Fullnodes[addr]
+	}
+	for number := from; number <= to; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		if number == 0 {
+			continue
+		}
+		signers, err := sb.blockSigners(chain, header)
+		if err != nil {
+			return nil, err
+		}
+		if signers.Round > 0 {
+			activity.RoundChange++
+		}
+		fullnode(signers.Author).Proposed++
+
+		// Fullnodes of the parent could seal the block
+		snap, err := sb.snapshot(chain, number-1, header.ParentHash, nil)
+		if err != nil {
+			return nil, err
+		}
+		sealed := make(map[common.Address]bool)
+		for _, committer := range signers.Committers {
+			sealed[committer] = true
+			fullnode(committer).Sealed++
+			fullnode(committer).LastSealed = number
+		}
+		for _, addr := range snap.fullnodes() {
+			if !sealed[addr] {
+				fullnode(addr).Missed++
+			}
+		}
+	}
+	return activity, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/rpc"
+)
+
+func TestSignersFromBlock(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	api := &API{chain: chain, smilo: engine}
+
+	parent := chain.Genesis()
+	for i := 0; i < 2; i++ {
+		block := makeBlock(chain, engine, parent)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", i, err)
+		}
+		parent = block
+	}
+	// Verifying the committed seals doesn't index the signers, the new chain head
+	// and its ancestors are, even if the node doesn't run the consensus core
+	head := chain.CurrentHeader()
+	key := append([]byte(dbKeySignersPrefix), head.Hash().Bytes()...)
+	if ok, _ := engine.db.Has(key); ok {
+		t.Fatalf("seal signers indexed on verification")
+	}
+	engine.Stop()
+	engine.NewChainHead(chain)
+	for i := 0; i < 100; i++ {
+		if ok, _ := engine.db.Has(key); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	engine.recentSealSigners.Purge()
+	if _, err := engine.db.Get(append([]byte(dbKeySignersPrefix), head.ParentHash.Bytes()...)); err != nil {
+		t.Fatalf("parent seal signers not indexed: %v", err)
+	}
+	if _, err := engine.db.Get(append([]byte(dbKeySignersPrefix), head.Hash().Bytes()...)); err != nil {
+		t.Fatalf("seal signers not indexed: %v", err)
+	}
+	number := rpc.BlockNumber(2)
+	signers, err := api.GetSignersFromBlock(&number)
+	if err != nil {
+		t.Fatalf("failed to retrieve signers: %v", err)
+	}
+	want := &BlockSigners{Number: 2, Hash: head.Hash(), Author: engine.address, Committers: []common.Address{engine.address}}
+	if !reflect.DeepEqual(signers, want) {
+		t.Errorf("signers mismatch: have %+v, want %+v", signers, want)
+	}
+	if byHash, err := api.GetSignersFromBlockByHash(head.Hash()); err != nil || !reflect.DeepEqual(byHash, want) {
+		t.Errorf("signers by hash mismatch: have %+v, want %+v, err %v", byHash, want, err)
+	}
+
+	activity, err := api.GetFullnodeActivity(0, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to retrieve activity: %v", err)
+	}
+	if have, want := activity.Fullnodes[engine.address], (&FullnodeActivity{Proposed: 2, Sealed: 2, LastSealed: 2}); !reflect.DeepEqual(have, want) {
+		t.Errorf("activity mismatch: have %+v, want %+v", have, want)
+	}
+	if activity.From != 0 || activity.To != 2 || activity.RoundChange != 0 {
+		t.Errorf("activity range mismatch: %+v", activity)
+	}
+	if _, err := api.GetFullnodeActivity(2, 1); err != errInvalidBlockRange {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidBlockRange)
+	}
+	// Both are served in the sport namespace
+	client := dialAPIs(chain, engine)
+	defer client.Close()
+
+	var served BlockSigners
+	if err := client.Call(&served, "sport_getSignersFromBlock", "0x2"); err != nil || !reflect.DeepEqual(&served, want) {
+		t.Errorf("sport_getSignersFromBlock: have %+v, want %+v, err %v", served, want, err)
+	}
+	var servedActivity Activity
+	if err := client.Call(&servedActivity, "sport_getFullnodeActivity", "0x0", "latest"); err != nil || servedActivity.To != 2 {
+		t.Errorf("sport_getFullnodeActivity: have %+v, err %v", servedActivity, err)
+	}
+}
@@ -20,6 +20,7 @@ package backend
 import (
 	"bytes"
 	"encoding/json"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -41,6 +42,7 @@ func newSnapshot(epoch uint64, number uint64, hash common.Hash, fullnodeSet spor
 		Hash:        hash,
 		FullnodeSet: fullnodeSet,
 		Tally:       make(map[common.Address]Tally),
+		Pending:     make(map[common.Address]bool),
 	}
 	return snap
 }
@@ -56,7 +58,9 @@ func loadSnapshot(epoch uint64, db ethdb.Database, hash common.Hash) (*Snapshot,
 		return nil, err
 	}
 	snap.Epoch = epoch
-
+	if snap.Pending == nil {
+		snap.Pending = make(map[common.Address]bool)
+	}
 	return snap, nil
 }
 
@@ -78,20 +82,66 @@ func (s *Snapshot) copy() *Snapshot {
 		FullnodeSet: s.FullnodeSet.Copy(),
 		Votes:       make([]*Vote, len(s.Votes)),
 		Tally:       make(map[common.Address]Tally),
+		Pending:     make(map[common.Address]bool),
+		Transitions: s.Transitions,
+		Evictions:   s.Evictions,
 	}
 
 	for address, tally := range s.Tally {
 		cpy.Tally[address] = tally
 	}
+	for address, authorize := range s.Pending {
+		cpy.Pending[address] = authorize
+	}
 	copy(cpy.Votes, s.Votes)
 
 	return cpy
 }
 
-// checkVote (clique override) return whether it's a valid vote
+// checkVote (clique override) return whether it's a valid vote, against the
+// fullnode set of the next epoch if changes are pending.
 func (s *Snapshot) checkVote(address common.Address, authorize bool) bool {
 	_, fullnode := s.FullnodeSet.GetByAddress(address)
-	return (fullnode != nil && !authorize) || (fullnode == nil && authorize)
+	member := fullnode != nil
+	if pending, ok := s.Pending[address]; ok {
+		member = pending
+	}
+	return member != authorize
+}
+
+// epochTransitions returns whether fullnode set changes of block number wait
+// for the next epoch checkpoint.
+func (s *Snapshot) epochTransitions(number uint64) bool {
+	return s.Transitions != nil && s.Transitions.Cmp(new(big.Int).SetUint64(number)) <= 0
+}
+
+// evictions returns whether block number may evict misbehaving fullnodes.
+func (s *Snapshot) evictions(number uint64) bool {
+	return s.Evictions != nil && s.Evictions.Cmp(new(big.Int).SetUint64(number)) <= 0
+}
+
+// nextFullnodes returns the fullnodes in ascending order once the pending
+// changes are applied.
+func (s *Snapshot) nextFullnodes() []common.Address {
+	next := s.FullnodeSet.Copy()
+	applyPending(next, s.Pending)
+
+	fullnodes := make([]common.Address, 0, next.Size())
+	for _, fullnode := range next.List() {
+		fullnodes = append(fullnodes, fullnode.Address())
+	}
+	return sortFullnodes(fullnodes)
+}
+
+// applyPending adds and removes the pending fullnodes to and from the set.
+func applyPending(set sport.FullnodeSet, pending map[common.Address]bool) {
+	for address, authorize := range pending {
+		if authorize {
+			set.AddFullnode(address)
+		} else {
+			set.RemoveFullnode(address)
+		}
+	}
 }
 
 // cast (clique override) adds a new vote into the tally.
@@ -131,6 +181,33 @@ func (s *Snapshot) uncast(address common.Address, authorize bool) bool {
 	return true
 }
 
+// discardVotes removes the votes around a just changed account, and the votes
+// it cast if it was deauthorized.
+func (s *Snapshot) discardVotes(address common.Address, deauthorized bool) {
+	if deauthorized {
+		// Discard any previous votes the deauthorized fullnode cast
+		for i := 0; i < len(s.Votes); i++ {
+			if s.Votes[i].Fullnode == address {
+				// Uncast the vote from the cached tally
+				s.uncast(s.Votes[i].Address, s.Votes[i].Authorize)
+
+				// Uncast the vote from the chronological list
+				s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+
+				i--
+			}
+		}
+	}
+	// Discard any previous votes around the just changed account
+	for i := 0; i < len(s.Votes); i++ {
+		if s.Votes[i].Address == address {
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+			i--
+		}
+	}
+	delete(s.Tally, address)
+}
+
 // apply (clique override) creates a new authorization snapshot by applying the given headers to the original one.
 func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	// Allow passing in no headers for cleaner code
@@ -150,13 +227,8 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	snap := s.copy()
 
 	for _, header := range headers {
-		// Remove any votes on checkpoint blocks
-		number := header.Number.Uint64()
-		if number%s.Epoch == 0 {
-			snap.Votes = nil
-			snap.Tally = make(map[common.Address]Tally)
-		}
 		// Resolve the authorization key and check against fullnodes
+		number := header.Number.Uint64()
 		fullnode, err := ecrecover(header)
 		if err != nil {
 			return nil, err
@@ -164,6 +236,20 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		if _, v := snap.FullnodeSet.GetByAddress(fullnode); v == nil {
 			return nil, errUnauthorized
 		}
+		// Remove any votes on checkpoint blocks
+		if number%s.Epoch == 0 {
+			snap.Votes = nil
+			snap.Tally = make(map[common.Address]Tally)
+
+			// The checkpoint hands over to the scheduled fullnode set, votes
+			// are not counted until the next block. Verification rejects
+			// such checkpoints casting a vote.
+			if snap.epochTransitions(number) {
+				applyPending(snap.FullnodeSet, snap.Pending)
+				snap.Pending = make(map[common.Address]bool)
+				continue
+			}
+		}
 
 		// Header authorized, discard any previous votes from the fullnode
 		for i, vote := range snap.Votes {
@@ -194,34 +280,43 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				Authorize: authorize,
 			})
 		}
-		// If the vote passed, update the list of fullnodes
+		// If the vote passed, update the list of fullnodes or schedule the
+		// change for the next checkpoint
 		if tally := snap.Tally[header.Coinbase]; tally.Votes > snap.FullnodeSet.Size()/2 {
-			if tally.Authorize {
+			switch {
+			case snap.epochTransitions(number):
+				// A vote reverting a scheduled change cancels it
+				if _, v := snap.FullnodeSet.GetByAddress(header.Coinbase); (v != nil) == tally.Authorize {
+					delete(snap.Pending, header.Coinbase)
+				} else {
+					snap.Pending[header.Coinbase] = tally.Authorize
+				}
+			case tally.Authorize:
 				snap.FullnodeSet.AddFullnode(header.Coinbase)
-			} else {
+			default:
 				snap.FullnodeSet.RemoveFullnode(header.Coinbase)
-
-				// Discard any previous votes the deauthorized fullnode cast
-				for i := 0; i < len(snap.Votes); i++ {
-					if snap.Votes[i].Fullnode == header.Coinbase {
-						// Uncast the vote from the cached tally
-						snap.uncast(snap.Votes[i].Address, snap.Votes[i].Authorize)
-
-						// Uncast the vote from the chronological list
-						snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
-
-						i--
-					}
-				}
 			}
-			// Discard any previous votes around the just changed account
-			for i := 0; i < len(snap.Votes); i++ {
-				if snap.Votes[i].Address == header.Coinbase {
-					snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
-					i--
-				}
+			snap.discardVotes(header.Coinbase, !tally.Authorize)
+		}
+		// Evict the fullnodes caught misbehaving, after the vote like a
+		// passed drop vote
+		if !snap.evictions(number) {
+			continue
+		}
+		evidences, err := decodeMisbehaviours(header)
+		if err != nil {
+			return nil, err
+		}
+		for _, evidence := range evidences {
+			if _, v := snap.FullnodeSet.GetByAddress(evidence.Offender); v == nil {
+				continue
+			}
+			if snap.epochTransitions(number) {
+				snap.Pending[evidence.Offender] = false
+			} else {
+				snap.FullnodeSet.RemoveFullnode(evidence.Offender)
 			}
-			delete(snap.Tally, header.Coinbase)
+			snap.discardVotes(evidence.Offender, true)
 		}
 	}
 	snap.Number += uint64(len(headers))
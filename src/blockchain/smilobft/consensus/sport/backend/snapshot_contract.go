@@ -0,0 +1,135 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/contracts/fullnodes"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// In contract governance mode the fullnode set is kept by the fullnode registry
+// deployed in genesis. Every block records in its extra-data the fullnodes
+// registered once its transactions are applied, which authorize the next block.
+// The recorded set is checked against the registry when the block is processed,
+// so headers can be verified without the state of their parent.
+
+// stateReader is implemented by chains that can open the state of past blocks.
+type stateReader interface {
+	StateAt(root common.Hash) (*state.StateDB, *state.StateDB, error)
+}
+
+// fullnodeRegistry returns the address of the fullnode registry governing the
+// chain, or the zero address if the fullnodes vote on the fullnode set.
+func fullnodeRegistry(chain consensus.ChainReader) common.Address {
+	if config := chain.Config(); config != nil {
+		return config.Sport.FullnodeRegistry()
+	}
+	return common.Address{}
+}
+
+// contractSnapshot returns the snapshot at the given block of a chain governed
+// by a fullnode registry, taking the fullnode set from its extra-data.
+func (sb *backend) contractSnapshot(chain consensus.ChainReader, registry common.Address, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
+	if s, ok := sb.recents.Get(hash); ok {
+		return s.(*Snapshot), nil
+	}
+	var header *types.Header
+	if len(parents) > 0 {
+		header = parents[len(parents)-1]
+		if header.Hash() != hash || header.Number.Uint64() != number {
+			return nil, consensus.ErrUnknownAncestor
+		}
+	} else if header = chain.GetHeader(hash, number); header == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	extra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	// Blocks are checked against the registry when processed, except genesis
+	if number == 0 {
+		if reader, ok := chain.(stateReader); ok {
+			statedb, _, err := reader.StateAt(header.Root)
+			if err != nil {
+				return nil, err
+			}
+			if !sameFullnodes(extra.Fullnodes, fullnodes.ReadFullnodes(statedb, registry)) {
+				return nil, errInvalidContractFullnodes
+			}
+		}
+	}
+	snap := newSnapshot(sb.config.Epoch, number, hash, sb.newFullnodeSet(chain, number, extra.Fullnodes))
+	sb.recents.Add(hash, snap)
+	return snap, nil
+}
+
+// commitFullnodes records the fullnodes registered in the registry after the
+// transactions of the block in its extra-data. Sealed headers are not modified,
+// their recorded fullnodes are checked against the registry instead.
+func commitFullnodes(header *types.Header, statedb *state.StateDB, registry common.Address) error {
+	extra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return err
+	}
+	registered := fullnodes.ReadFullnodes(statedb, registry)
+	if len(extra.Seal) > 0 {
+		if !sameFullnodes(extra.Fullnodes, registered) {
+			return errInvalidContractFullnodes
+		}
+		return nil
+	}
+	extra.Fullnodes = sortFullnodes(registered)
+
+	payload, err := rlp.EncodeToBytes(&extra)
+	if err != nil {
+		return err
+	}
+	header.Extra = append(header.Extra[:types.SportExtraVanity], payload...)
+	return nil
+}
+
+// sameFullnodes reports whether both lists hold the same fullnodes in any order.
+func sameFullnodes(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortFullnodes(a), sortFullnodes(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortFullnodes returns a copy of fullnodes in ascending order.
+func sortFullnodes(fullnodes []common.Address) []common.Address {
+	sorted := make([]common.Address, len(fullnodes))
+	copy(sorted, fullnodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	return sorted
+}
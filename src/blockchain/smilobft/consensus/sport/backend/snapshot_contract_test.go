@@ -0,0 +1,115 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/contracts/fullnodes"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/core/vm"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+var testRegistry = common.HexToAddress("0x0000000000000000000000000000000000000f01")
+
+// newContractBlockChain creates a single fullnode chain governed by a fullnode
+// registry with the given fullnodes registered in genesis.
+func newContractBlockChain(registered func(self common.Address) []common.Address) (*core.BlockChain, *backend) {
+	genesis, nodeKeys := getGenesisAndKeys(1)
+	self := crypto.PubkeyToAddress(nodeKeys[0].PublicKey)
+
+	config := *genesis.Config
+	config.Sport = &params.SportConfig{FullnodeContract: &testRegistry}
+	genesis.Config = &config
+	genesis.Alloc = core.GenesisAlloc{testRegistry: fullnodes.GenesisAccount(registered(self), big.NewInt(1))}
+
+	memDB := rawdb.NewMemoryDatabase()
+	b, _ := New(sport.DefaultConfig, nodeKeys[0], memDB).(*backend)
+	genesis.MustCommit(memDB)
+	blockchain, err := core.NewBlockChain(memDB, nil, genesis.Config, b, vm.Config{}, nil)
+	if err != nil {
+		panic(err)
+	}
+	b.Start(blockchain, blockchain.CurrentBlock, blockchain.HasBadBlock)
+	return blockchain, b
+}
+
+func TestContractSnapshot(t *testing.T) {
+	chain, engine := newContractBlockChain(func(self common.Address) []common.Address {
+		return []common.Address{self}
+	})
+	snap, err := engine.snapshot(chain, 0, chain.Genesis().Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to create genesis snapshot: %v", err)
+	}
+	if have, want := snap.fullnodes(), []common.Address{engine.address}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("genesis fullnodes mismatch: have %x, want %x", have, want)
+	}
+	block := makeBlock(chain, engine, chain.Genesis())
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+
+	// Registering a fullnode changes the fullnodes recorded by the next block
+	joiner := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	statedb, _, _ := chain.StateAt(block.Root())
+	for key, value := range fullnodes.GenesisAccount([]common.Address{engine.address, joiner}, big.NewInt(1)).Storage {
+		statedb.SetState(testRegistry, key, value)
+	}
+	header := makeHeader(block, engine.config)
+	if err := engine.Prepare(chain, header); err != nil {
+		t.Fatalf("failed to prepare header: %v", err)
+	}
+	if _, err := engine.Finalize(chain, header, statedb, nil, nil, nil); err != nil {
+		t.Fatalf("failed to finalize header: %v", err)
+	}
+	extra, _ := types.ExtractSportExtra(header)
+	if have, want := extra.Fullnodes, sortFullnodes([]common.Address{engine.address, joiner}); !reflect.DeepEqual(have, want) {
+		t.Fatalf("recorded fullnodes mismatch: have %x, want %x", have, want)
+	}
+
+	// Sealed blocks must record the registered fullnodes
+	header = block.Header()
+	if _, err := engine.Finalize(chain, header, statedb, nil, nil, nil); err != errInvalidContractFullnodes {
+		t.Fatalf("error mismatch: have %v, want %v", err, errInvalidContractFullnodes)
+	}
+
+	// Fullnodes are not voted in
+	api := &API{chain: chain, smilo: engine}
+	api.Propose(joiner, true)
+	if len(api.Proposals()) != 0 {
+		t.Fatalf("proposal accepted in contract governance mode")
+	}
+}
+
+func TestContractSnapshotGenesisMismatch(t *testing.T) {
+	chain, engine := newContractBlockChain(func(self common.Address) []common.Address {
+		return []common.Address{common.HexToAddress("0x000000000000000000000000000000000000dead")}
+	})
+	if _, err := engine.snapshot(chain, 0, chain.Genesis().Hash(), nil); err != errInvalidContractFullnodes {
+		t.Fatalf("error mismatch: have %v, want %v", err, errInvalidContractFullnodes)
+	}
+}
@@ -19,6 +19,7 @@ package backend
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"math/big"
 	"reflect"
@@ -37,6 +38,9 @@ import (
 	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/core/vm"
+	"go-didux/src/blockchain/smilobft/ethdb"
+	"go-didux/src/blockchain/smilobft/light"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 type testerVote struct {
@@ -414,6 +418,228 @@ func TestVoting(t *testing.T) {
 
 }
 
+// Tests that fullnode set changes voted in after the epoch transitions block wait
+// for the next checkpoint.
+func TestEpochTransitions(t *testing.T) {
+	accounts := newTesterAccountPool()
+	a, b, c := accounts.address("A"), accounts.address("B"), accounts.address("C")
+
+	snap := newSnapshot(4, 0, common.Hash{}, fullnode.NewFullnodeSet([]common.Address{a, b}, sport.RoundRobin))
+	snap.Transitions = big.NewInt(0)
+
+	var parent common.Hash
+	header := func(number int64, fullnode string, voted common.Address, auth bool) *types.Header {
+		h := &types.Header{
+			Number:     big.NewInt(number),
+			ParentHash: parent,
+			Coinbase:   voted,
+			Difficulty: defaultDifficulty,
+			MixDigest:  types.SportDigest,
+		}
+		h.Extra, _ = prepareExtra(h, []common.Address{a, b})
+		if auth {
+			copy(h.Nonce[:], nonceAuthVote)
+		}
+		accounts.sign(h, fullnode)
+		parent = h.Hash()
+		return h
+	}
+	// Authorizing C passes at block 2 but waits for the checkpoint
+	snap, err := snap.apply([]*types.Header{
+		header(1, "A", c, true),
+		header(2, "B", c, true),
+		header(3, "A", common.Address{}, false),
+	})
+	if err != nil {
+		t.Fatalf("failed to apply votes: %v", err)
+	}
+	if _, v := snap.FullnodeSet.GetByAddress(c); v != nil {
+		t.Errorf("fullnode added before the checkpoint")
+	}
+	if !reflect.DeepEqual(snap.Pending, map[common.Address]bool{c: true}) {
+		t.Errorf("pending mismatch: have %v, want %v", snap.Pending, map[common.Address]bool{c: true})
+	}
+	if snap.checkVote(c, true) {
+		t.Errorf("vote for a pending fullnode accepted")
+	}
+	if want := sortFullnodes([]common.Address{a, b, c}); !reflect.DeepEqual(snap.nextFullnodes(), want) {
+		t.Errorf("next fullnodes mismatch: have %x, want %x", snap.nextFullnodes(), want)
+	}
+	// A checkpoint handing over the fullnode set can't cast a vote
+	checkpoint := &types.Header{Number: big.NewInt(4), Coinbase: c}
+	checkpoint.Extra, _ = prepareExtra(checkpoint, []common.Address{a, b})
+	if err := writeNextFullnodes(checkpoint, snap.nextFullnodes()); err != nil {
+		t.Fatalf("failed to write next fullnodes: %v", err)
+	}
+	if err := verifyNextFullnodes(checkpoint, snap); err != errInvalidCheckpointVote {
+		t.Errorf("checkpoint vote error mismatch: have %v, want %v", err, errInvalidCheckpointVote)
+	}
+	checkpoint.Coinbase = common.Address{}
+	if err := verifyNextFullnodes(checkpoint, snap); err != nil {
+		t.Errorf("checkpoint rejected: %v", err)
+	}
+	// The checkpoint hands over to the next fullnode set
+	if snap, err = snap.apply([]*types.Header{header(4, "B", common.Address{}, false)}); err != nil {
+		t.Fatalf("failed to apply checkpoint: %v", err)
+	}
+	if len(snap.Pending) != 0 || !reflect.DeepEqual(snap.fullnodes(), sortFullnodes([]common.Address{a, b, c})) {
+		t.Errorf("fullnode set not switched at the checkpoint: fullnodes %x, pending %v", snap.fullnodes(), snap.Pending)
+	}
+	if _, err = snap.apply([]*types.Header{header(5, "C", common.Address{}, false)}); err != nil {
+		t.Errorf("new fullnode not authorized after the checkpoint: %v", err)
+	}
+}
+
+// Tests that the scheduled transitions are served in the sport namespace.
+func TestTransitionsAPI(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	client := dialAPIs(chain, engine)
+	defer client.Close()
+
+	var transitions Transitions
+	if err := client.Call(&transitions, "sport_getTransitions", "latest"); err != nil {
+		t.Fatalf("sport_getTransitions failed: %v", err)
+	}
+	if transitions.Number != 0 || transitions.Checkpoint != engine.config.Epoch {
+		t.Errorf("checkpoint mismatch: have #%d at #%d, want #%d at #0", transitions.Checkpoint, transitions.Number, engine.config.Epoch)
+	}
+	if want := []common.Address{engine.address}; !reflect.DeepEqual(transitions.Fullnodes, want) || !reflect.DeepEqual(transitions.NextFullnodes, want) {
+		t.Errorf("fullnodes mismatch: have %x -> %x, want %x", transitions.Fullnodes, transitions.NextFullnodes, want)
+	}
+}
+
+// Tests that headers following an epoch checkpoint verify without its ancestors,
+// as light clients syncing from a trusted checkpoint need.
+func TestCheckpointSnapshot(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	config := *engine.config
+	config.Epoch = 2
+	engine.config = &config
+	engine.recents.Purge()
+
+	parent := chain.Genesis()
+	for i := 0; i < 3; i++ {
+		block := makeBlock(chain, engine, parent)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", i, err)
+		}
+		parent = block
+	}
+	// Assemble a header chain knowing only the genesis and the checkpoint
+	db := rawdb.NewMemoryDatabase()
+	for _, header := range []*types.Header{chain.Genesis().Header(), chain.GetHeaderByNumber(2)} {
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+		rawdb.WriteTd(db, header.Hash(), header.Number.Uint64(), new(big.Int).Add(header.Number, common.Big1))
+	}
+	light := New(&config, engine.privateKey, db).(*backend)
+	headers, err := core.NewHeaderChain(db, chain.Config(), light, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	if err := light.VerifyHeader(headers, chain.GetHeaderByNumber(3), true); err != nil {
+		t.Fatalf("failed to verify header after the checkpoint: %v", err)
+	}
+	snap, err := light.snapshot(headers, 2, chain.GetHeaderByNumber(2).Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve checkpoint snapshot: %v", err)
+	}
+	if want := []common.Address{engine.address}; !reflect.DeepEqual(snap.fullnodes(), want) {
+		t.Errorf("fullnodes mismatch: have %x, want %x", snap.fullnodes(), want)
+	}
+}
+
+// checkpointOdr serves the headers of a full chain to a light client, as les
+// servers answer CHT requests.
+type checkpointOdr struct {
+	light.OdrBackend
+	db     ethdb.Database
+	cht    *core.ChainIndexer
+	config *light.IndexerConfig
+	chain  *core.BlockChain
+}
+
+func (odr *checkpointOdr) Database() ethdb.Database             { return odr.db }
+func (odr *checkpointOdr) ChtIndexer() *core.ChainIndexer       { return odr.cht }
+func (odr *checkpointOdr) BloomTrieIndexer() *core.ChainIndexer { return nil }
+func (odr *checkpointOdr) BloomIndexer() *core.ChainIndexer     { return nil }
+func (odr *checkpointOdr) IndexerConfig() *light.IndexerConfig  { return odr.config }
+
+func (odr *checkpointOdr) Retrieve(ctx context.Context, req light.OdrRequest) error {
+	if req, ok := req.(*light.ChtRequest); ok {
+		req.Header = odr.chain.GetHeaderByNumber(req.BlockNum)
+		req.Td = odr.chain.GetTd(req.Header.Hash(), req.BlockNum)
+	}
+	req.StoreResult(odr.db)
+	return nil
+}
+
+// Tests that a light client syncing from a trusted checkpoint starts from the
+// last epoch checkpoint it covers and verifies the following headers against
+// its fullnodes, while full nodes reject blocks recording forged fullnodes.
+func TestLightCheckpointSync(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	config := *engine.config
+	config.Epoch = 2
+	engine.config = &config
+	engine.recents.Purge()
+
+	parent := chain.Genesis()
+	for i := 0; i < 5; i++ {
+		block := makeBlock(chain, engine, parent)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", i, err)
+		}
+		parent = block
+	}
+	// Blocks recording other fullnodes than their parent snapshot are rejected,
+	// so the checkpoints covered by CHTs record the fullnodes that sealed them
+	forged := makeBlockWithoutSeal(chain, engine, parent)
+	header := forged.Header()
+	header.Extra, _ = prepareExtra(header, []common.Address{engine.address, {0xf}})
+	forged, _ = engine.updateBlock(parent.Header(), forged.WithSeal(header))
+	if err := engine.VerifyHeader(chain, forged.Header(), false); err != errInvalidFullnodes {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidFullnodes)
+	}
+
+	// Sync a light client from a checkpoint covering the first 4 blocks
+	db := rawdb.NewMemoryDatabase()
+	genesis := chain.Genesis()
+	rawdb.WriteTd(db, genesis.Hash(), 0, genesis.Difficulty())
+	rawdb.WriteBlock(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), 0)
+	rawdb.WriteHeadHeaderHash(db, genesis.Hash())
+	rawdb.WriteChainConfig(db, genesis.Hash(), chain.Config())
+
+	indexerConfig := &light.IndexerConfig{ChtSize: 4, ChtConfirms: 1}
+	odr := &checkpointOdr{db: db, config: indexerConfig, chain: chain}
+	odr.cht = light.NewChtIndexer(db, odr, indexerConfig.ChtSize, indexerConfig.ChtConfirms)
+	defer odr.cht.Close()
+
+	lightEngine := New(&config, engine.privateKey, db)
+	lc, err := light.NewLightChain(odr, chain.Config(), lightEngine, nil)
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	defer lc.Stop()
+
+	checkpoint := &params.TrustedCheckpoint{SectionHead: chain.GetHeaderByNumber(3).Hash()}
+	lc.AddTrustedCheckpoint(checkpoint)
+	if !lc.SyncCheckpoint(context.Background(), checkpoint) {
+		t.Fatal("failed to sync checkpoint")
+	}
+	if head := lc.CurrentHeader().Number.Uint64(); head != 2 {
+		t.Fatalf("light client head mismatch: have %d, want epoch checkpoint 2", head)
+	}
+	headers := []*types.Header{chain.GetHeaderByNumber(3), chain.GetHeaderByNumber(4), chain.GetHeaderByNumber(5)}
+	if _, err := lc.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to insert headers after the checkpoint: %v", err)
+	}
+	if head := lc.CurrentHeader().Hash(); head != chain.CurrentHeader().Hash() {
+		t.Errorf("light client head mismatch: have %x, want %x", head, chain.CurrentHeader().Hash())
+	}
+}
+
 func TestSaveAndLoad(t *testing.T) {
 	snap := &Snapshot{
 		Epoch:  5,
@@ -437,6 +663,9 @@ func TestSaveAndLoad(t *testing.T) {
 			cmn.StringToAddress("1234567894"),
 			cmn.StringToAddress("1234567895"),
 		}, sport.RoundRobin),
+		Pending: map[common.Address]bool{
+			cmn.StringToAddress("1234567896"): true,
+		},
 	}
 	db := rawdb.NewMemoryDatabase()
 	err := snap.store(db)
@@ -460,6 +689,9 @@ func TestSaveAndLoad(t *testing.T) {
 	if !reflect.DeepEqual(snap.Tally, snap.Tally) {
 		t.Errorf("tally mismatch: have %v, want %v", snap1.Tally, snap.Tally)
 	}
+	if !reflect.DeepEqual(snap.Pending, snap1.Pending) {
+		t.Errorf("pending mismatch: have %v, want %v", snap1.Pending, snap.Pending)
+	}
 	if !reflect.DeepEqual(snap.FullnodeSet, snap.FullnodeSet) {
 		t.Errorf("fullnode set mismatch: have %v, want %v", snap1.FullnodeSet, snap.FullnodeSet)
 	}
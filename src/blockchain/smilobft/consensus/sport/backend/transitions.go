@@ -0,0 +1,59 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// Transitions are the fullnode set changes scheduled for the next epoch
+// checkpoint.
+type Transitions struct {
+	Number        uint64                  `json:"number"`        // Block the transitions are reported at
+	Checkpoint    uint64                  `json:"checkpoint"`    // Next epoch checkpoint, handing over to NextFullnodes
+	Enabled       bool                    `json:"enabled"`       // Whether changes wait for the checkpoint at all
+	Pending       map[common.Address]bool `json:"pending"`       // Fullnodes added (true) or removed (false) at the checkpoint
+	Fullnodes     []common.Address        `json:"fullnodes"`     // Fullnodes until the checkpoint
+	NextFullnodes []common.Address        `json:"nextFullnodes"` // Fullnodes after the checkpoint
+}
+
+// transitions returns the fullnode set changes scheduled after the block of the
+// header.
+func (sb *backend) transitions(chain consensus.ChainReader, header *types.Header) (*Transitions, error) {
+	number := header.Number.Uint64()
+	snap, err := sb.snapshot(chain, number, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := (number/sb.config.Epoch + 1) * sb.config.Epoch
+
+	transitions := &Transitions{
+		Number:        number,
+		Checkpoint:    checkpoint,
+		Enabled:       snap.epochTransitions(checkpoint),
+		Pending:       make(map[common.Address]bool, len(snap.Pending)),
+		Fullnodes:     snap.fullnodes(),
+		NextFullnodes: snap.nextFullnodes(),
+	}
+	for address, authorize := range snap.Pending {
+		transitions.Pending[address] = authorize
+	}
+	return transitions, nil
+}
@@ -48,3 +48,49 @@ func roundRobinSpeaker(fullnodeSet sport.FullnodeSet, speaker common.Address, ro
 	pick := seed % uint64(fullnodeSet.Size())
 	return fullnodeSet.GetByIndex(pick)
 }
+
+func stickySpeaker(fullnodeSet sport.FullnodeSet, speaker common.Address, round uint64) sport.Fullnode {
+	if fullnodeSet.Size() == 0 {
+		return nil
+	}
+	seed := uint64(0)
+	if emptyAddress(speaker) {
+		seed = round
+	} else {
+		seed = calcSeed(fullnodeSet, speaker, round)
+	}
+	pick := seed % uint64(fullnodeSet.Size())
+	return fullnodeSet.GetByIndex(pick)
+}
+
+// weightedSpeaker returns a selector giving every fullnode as many consecutive
+// slots as its weight, the speaker of block number being the one owning slot
+// number + round. Over any total weight blocks, each fullnode proposes as many
+// blocks as its weight unless rounds change.
+func weightedSpeaker(weights map[common.Address]uint64, number uint64) sport.BlockProposalSelector {
+	weightOf := func(addr common.Address) uint64 {
+		if weight, ok := weights[addr]; ok && weight > 0 {
+			return weight
+		}
+		return 1
+	}
+	return func(fullnodeSet sport.FullnodeSet, speaker common.Address, round uint64) sport.Fullnode {
+		fullnodes := fullnodeSet.List()
+		if len(fullnodes) == 0 {
+			return nil
+		}
+		total := uint64(0)
+		for _, fullnode := range fullnodes {
+			total += weightOf(fullnode.Address())
+		}
+		slot := (number + round) % total
+		for _, fullnode := range fullnodes {
+			if weight := weightOf(fullnode.Address()); slot >= weight {
+				slot -= weight
+			} else {
+				return fullnode
+			}
+		}
+		return nil
+	}
+}
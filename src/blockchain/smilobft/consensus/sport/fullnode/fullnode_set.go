@@ -112,7 +112,7 @@ func (fullnodeSet *fullnodeSet) Copy() sport.FullnodeSet {
 	for _, v := range fullnodeSet.fullnodes {
 		addresses = append(addresses, v.Address())
 	}
-	return NewFullnodeSet(addresses, fullnodeSet.policy)
+	return newFullnodeSetAt(addresses, fullnodeSet.policy, fullnodeSet.weights, fullnodeSet.number)
 }
 
 func (fullnodeSet *fullnodeSet) MaxFaulty() int {
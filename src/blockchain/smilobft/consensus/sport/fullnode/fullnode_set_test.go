@@ -176,3 +176,38 @@ func TestAddAndRemoveFullnode(t *testing.T) {
 	require.Len(t, fullnodeSet.List(), 0, "the size of fullnode set should be 0")
 
 }
+
+func TestStickySpeaker(t *testing.T) {
+	addr1, addr2 := common.HexToAddress(testAddress), common.HexToAddress(testAddress2)
+	fullnodeSet := NewFullnodeSetAt([]common.Address{addr1, addr2}, sport.Sticky, nil, 1)
+
+	// the speaker stays until a round change
+	fullnodeSet.CalcSpeaker(addr1, 0)
+	require.Equal(t, addr1, fullnodeSet.GetSpeaker().Address())
+	fullnodeSet.CalcSpeaker(addr1, 1)
+	require.Equal(t, addr2, fullnodeSet.GetSpeaker().Address())
+	fullnodeSet.CalcSpeaker(addr2, 0)
+	require.Equal(t, addr2, fullnodeSet.GetSpeaker().Address())
+	require.Equal(t, sport.Sticky, fullnodeSet.Copy().Policy())
+}
+
+func TestWeightedSpeaker(t *testing.T) {
+	addr1, addr2 := common.HexToAddress(testAddress), common.HexToAddress(testAddress2)
+	weights := map[common.Address]uint64{addr2: 3}
+
+	// every 4 blocks addr1 proposes once and addr2 three times
+	proposed := make(map[common.Address]int)
+	for number := uint64(100); number < 108; number++ {
+		fullnodeSet := NewFullnodeSetAt([]common.Address{addr1, addr2}, sport.Weighted, weights, number)
+		fullnodeSet.CalcSpeaker(addr1, 0)
+		proposed[fullnodeSet.GetSpeaker().Address()]++
+	}
+	require.Equal(t, map[common.Address]int{addr1: 2, addr2: 6}, proposed)
+
+	// round changes move on to the next slot, copies keep the weights
+	fullnodeSet := NewFullnodeSetAt([]common.Address{addr1, addr2}, sport.Weighted, weights, 100).Copy()
+	fullnodeSet.CalcSpeaker(addr1, 0)
+	require.Equal(t, addr1, fullnodeSet.GetSpeaker().Address())
+	fullnodeSet.CalcSpeaker(addr1, 1)
+	require.Equal(t, addr2, fullnodeSet.GetSpeaker().Address())
+}
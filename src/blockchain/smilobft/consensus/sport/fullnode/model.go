@@ -50,16 +50,32 @@ type fullnodeSet struct {
 	speaker    sport.Fullnode
 	fullnodeMu sync.RWMutex
 	selector   sport.BlockProposalSelector
+
+	weights map[common.Address]uint64 // Proposal weights of the Weighted policy
+	number  uint64                    // Block the speakers are selected for
 }
 
 func NewFullnodeSet(addrs []common.Address, policy sport.SpeakerPolicy) sport.FullnodeSet {
 	return newFullnodeSet(addrs, policy)
 }
 
+// NewFullnodeSetAt creates the fullnode set selecting the speakers of block
+// number. Weights are only used by the Weighted policy, unlisted fullnodes
+// weigh 1.
+func NewFullnodeSetAt(addrs []common.Address, policy sport.SpeakerPolicy, weights map[common.Address]uint64, number uint64) sport.FullnodeSet {
+	return newFullnodeSetAt(addrs, policy, weights, number)
+}
+
 func newFullnodeSet(addrs []common.Address, policy sport.SpeakerPolicy) *fullnodeSet {
+	return newFullnodeSetAt(addrs, policy, nil, 0)
+}
+
+func newFullnodeSetAt(addrs []common.Address, policy sport.SpeakerPolicy, weights map[common.Address]uint64, number uint64) *fullnodeSet {
 	fullnodeSet := &fullnodeSet{}
 
 	fullnodeSet.policy = policy
+	fullnodeSet.weights = weights
+	fullnodeSet.number = number
 	// init fullnodes
 	fullnodeSet.fullnodes = make([]sport.Fullnode, len(addrs))
 	for i, addr := range addrs {
@@ -72,7 +88,14 @@ func newFullnodeSet(addrs []common.Address, policy sport.SpeakerPolicy) *fullnod
 		fullnodeSet.speaker = fullnodeSet.GetByIndex(0)
 		log.Debug("newFullnodeSet, Going to set initial speaker, ", "new speaker", fullnodeSet.speaker.String())
 	}
-	fullnodeSet.selector = roundRobinSpeaker
+	switch policy {
+	case sport.Sticky:
+		fullnodeSet.selector = stickySpeaker
+	case sport.Weighted:
+		fullnodeSet.selector = weightedSpeaker(weights, number)
+	default:
+		fullnodeSet.selector = roundRobinSpeaker
+	}
 
 	return fullnodeSet
 }
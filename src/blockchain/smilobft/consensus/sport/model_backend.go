@@ -61,6 +61,10 @@ type Backend interface {
 	// LastBlockProposal retrieves latest committed proposal and the address of speaker
 	LastBlockProposal() (BlockProposal, common.Address)
 
+	// ProposalDelay returns how long the speaker may hold back the proposal
+	// following the given one, waiting for transactions until an empty block is due
+	ProposalDelay(proposal BlockProposal) time.Duration
+
 	// HasBlockProposal checks if the combination of the given hash and height matches any existing blocks
 	HasBlockProposal(hash common.Hash, number *big.Int) bool
 
@@ -73,5 +77,9 @@ type Backend interface {
 	// HasBadBlock returns whether the block with the hash is a bad block
 	HasBadBlockProposal(hash common.Hash) bool
 
+	// ReportMisbehaviour records the evidence of a fullnode signing conflicting
+	// consensus messages
+	ReportMisbehaviour(evidence *Misbehaviour)
+
 	Close() error
 }
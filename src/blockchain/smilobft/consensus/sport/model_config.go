@@ -16,12 +16,18 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 package sport
 
-import "math/big"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
 
 type SpeakerPolicy uint64
 
 const (
-	RoundRobin SpeakerPolicy = iota
+	RoundRobin SpeakerPolicy = iota // The speaker rotates every block and round change
+	Sticky                          // The speaker only rotates on round changes
+	Weighted                        // Fullnodes propose blocks in proportion to their weight
 )
 
 type Config struct {
@@ -32,8 +38,13 @@ type Config struct {
 	Epoch                uint64        `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	DataDir              string        `toml:",omitempty"` // The default datadir for permissioned-nodes.json file
 	MinFunds             int64         `toml:",omitempty"` // The minimum funds a node should have to be a full node
-	CommunityAddress     string        `toml:",omitempty"` // The community address for miner donations
+	CommunityAddress     string        `toml:",omitempty"` // The community address for miner donations (only before the genesis rewards are activated)
 	MinBlocksEmptyMining *big.Int      `toml:",omitempty"` // Min Blocks to mine before Stop Mining Empty Blocks
+	WAL                  string        `toml:",omitempty"` // Path of the consensus write-ahead log (empty = disabled, geth defaults it to sportwal in the datadir)
+	NoWAL                bool          `toml:",omitempty"` // Disable the consensus write-ahead log, whatever the path
+	EvictMisbehaving     bool          `toml:",omitempty"` // Include evidence of fullnodes signing conflicting consensus messages in proposed blocks, evicting them (from the genesis misbehaviour eviction block)
+	Clock                mclock.Clock  `toml:"-"`          // Source of the consensus timeouts (nil = system clock), simulations run their own
+	Events               *EventQueue   `toml:"-"`          // Queue of the events the engine posts to its core (nil = one goroutine each), simulations wait on it
 }
 
 var DefaultConfig = &Config{
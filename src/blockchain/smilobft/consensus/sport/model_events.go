@@ -14,9 +14,16 @@
 //
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
-
 package sport
 
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
 // RequestEvent is posted to propose a proposal
 type RequestEvent struct {
 	BlockProposal BlockProposal
@@ -30,3 +37,109 @@ type MessageEvent struct {
 // FinalCommittedEvent is posted when a proposal is committed
 type FinalCommittedEvent struct {
 }
+
+// EventQueue posts the events the engine sends to its own core one at a time
+// and in order, and tracks them until the core handled them, letting simulations
+// wait for the engine to settle before advancing their clock. A nil queue posts
+// every event from a goroutine of its own and tracks nothing.
+type EventQueue struct {
+	lock     sync.Mutex
+	cond     *sync.Cond
+	queue    []queuedEvent
+	draining bool
+	pending  int
+}
+
+// queuedEvent is an event waiting to be posted to mux.
+type queuedEvent struct {
+	mux *event.TypeMux
+	ev  interface{}
+}
+
+// NewEventQueue creates a queue without pending events.
+func NewEventQueue() *EventQueue {
+	q := new(EventQueue)
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Post posts ev to mux without waiting for the core to take it, after the
+// events posted before.
+func (q *EventQueue) Post(mux *event.TypeMux, ev interface{}) {
+	if q == nil {
+		go postEvent(mux, ev)
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.pending++
+	q.queue = append(q.queue, queuedEvent{mux: mux, ev: ev})
+	if !q.draining {
+		q.draining = true
+		go q.drain()
+	}
+}
+
+// drain posts the queued events until none is left.
+func (q *EventQueue) drain() {
+	for {
+		q.lock.Lock()
+		if len(q.queue) == 0 {
+			q.draining = false
+			q.lock.Unlock()
+			return
+		}
+		next := q.queue[0]
+		q.queue = q.queue[1:]
+		q.lock.Unlock()
+
+		if !postEvent(next.mux, next.ev) {
+			q.Done()
+		}
+	}
+}
+
+// Add records an event about to be posted to the core directly.
+func (q *EventQueue) Add() {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	q.pending++
+	q.lock.Unlock()
+}
+
+// Done records an event handled by the core, or lost on its way.
+func (q *EventQueue) Done() {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	q.pending--
+	if q.pending == 0 {
+		q.cond.Broadcast()
+	}
+	q.lock.Unlock()
+}
+
+// Wait blocks until every event posted was handled.
+func (q *EventQueue) Wait() {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	for q.pending > 0 {
+		q.cond.Wait()
+	}
+	q.lock.Unlock()
+}
+
+// postEvent posts ev to mux and reports whether it succeeded.
+func postEvent(mux *event.TypeMux, ev interface{}) bool {
+	if err := mux.Post(ev); err != nil {
+		log.Error("Could not post event to the core", "event", reflect.TypeOf(ev), "err", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package sport
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Misbehaviour is the evidence that a fullnode signed two conflicting consensus
+// messages of the same kind for the same view.
+type Misbehaviour struct {
+	Offender common.Address `json:"offender"` // Fullnode that signed both messages
+	Code     uint64         `json:"code"`     // Consensus message code of both messages
+	View     *View          `json:"view"`     // View both messages were signed for
+	First    hexutil.Bytes  `json:"first"`    // Signed payload of the first message
+	Second   hexutil.Bytes  `json:"second"`   // Signed payload of the conflicting message
+}
+
+// Hash returns the identifier of the evidence.
+func (m *Misbehaviour) Hash() common.Hash {
+	return RLPHash(m)
+}
+
+func (m *Misbehaviour) String() string {
+	return fmt.Sprintf("{Offender: %v, Code: %d, View: %v}", m.Offender.String(), m.Code, m.View)
+}
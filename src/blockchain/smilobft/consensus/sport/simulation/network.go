@@ -0,0 +1,689 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package simulation runs a network of Sport fullnodes in a single process to
+// test the consensus under faults. Every node is a complete Sport backend with
+// its own chain, running as a service of a p2p/simulations in-memory node, and
+// the consensus messages travel over the devp2p connections between them.
+//
+// The network is deterministic: no wall clock is involved, the caller advances
+// a simulated clock with Run or WaitForHeight, and every message, round change
+// timeout and block import is handed to the engines from that single loop, one
+// at a time and in an order depending only on the seed, after the engines have
+// handled the previous one. Delays, drops, partitions and crashes are injected
+// between runs, so the same seed and the same faults replay the same run.
+package simulation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+	"go-didux/src/blockchain/smilobft/consensus/sport/backend"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/core/vm"
+	"go-didux/src/blockchain/smilobft/ethdb"
+	"go-didux/src/blockchain/smilobft/node"
+	"go-didux/src/blockchain/smilobft/p2p/enode"
+	"go-didux/src/blockchain/smilobft/p2p/simulations/adapters"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+// proposer is the part of the Sport backend the network drives in place of the
+// miner.
+type proposer interface {
+	EventMux() *event.TypeMux
+	SealHash(header *types.Header) common.Hash
+}
+
+// syncInterval is how often, in simulated time, lagging nodes import the blocks
+// of the nodes they are connected to, standing in for the eth downloader.
+const syncInterval = 100 * time.Millisecond
+
+var (
+	errNodeRunning = errors.New("node running")
+	errNodeCrashed = errors.New("node crashed")
+	errNoProgress  = errors.New("network did not reach the height")
+)
+
+// Config is the setup of a simulated network.
+type Config struct {
+	Nodes                int           // Number of fullnodes
+	Seed                 int64         // Seed of the node keys and of the message drops
+	RequestTimeout       uint64        // Timeout of the first round in simulated milliseconds (0 = sport.DefaultConfig)
+	Epoch                uint64        // Blocks between checkpoints (0 = sport.DefaultConfig)
+	SixtySixPercentBlock *big.Int      // Block after which 2/3 of the fullnodes must seal blocks (nil = genesis)
+	WALDir               string        // Directory of the write-ahead logs of the nodes (empty = disabled)
+	Tick                 time.Duration // Step of the simulated clock between checks of the network (0 = 10ms)
+}
+
+// Network is a simulated network of Sport fullnodes.
+type Network struct {
+	config      Config
+	chainConfig *params.ChainConfig
+	clock       *mclock.Simulated
+	timers      *timers
+	adapter     *adapters.SimAdapter
+	keys        []*ecdsa.PrivateKey
+	addrs       []common.Address
+	index       map[enode.ID]int // Node of every devp2p identity, fixed at creation
+
+	lock     sync.Mutex // Protects the nodes, the links and the messages below
+	cond     *sync.Cond // Signalled when a link goes up or down or a message arrives
+	nodes    []*fullnode
+	links    [][]link
+	group    []int // Partition of every node, messages only flow within one
+	dropRate float64
+	queue    deliveries             // Messages sent, by delivery time
+	inbox    map[msgKey]*envelope   // Messages arrived and not delivered yet
+	transit  int                    // Messages written to a connection and not arrived yet
+	sent     int                    // Messages sent so far
+	dropped  int                    // Messages dropped so far
+	commits  map[uint64]common.Hash // First block seen committed at every height
+
+	violations []error // Conflicting blocks committed at the same height
+}
+
+// fullnode is a node of the network, the database outlives crashes.
+type fullnode struct {
+	sim    *adapters.SimNode
+	db     ethdb.Database
+	up     bool               // Whether the devp2p node runs
+	engine consensus.SmiloBFT // Nil while crashed
+	chain  *core.BlockChain
+	events *sport.EventQueue // Events the engine posted to its core
+}
+
+// New creates a network of fullnodes sharing a genesis block and starts it at
+// simulated time zero.
+func New(config Config) (*Network, error) {
+	if config.Nodes <= 0 {
+		return nil, fmt.Errorf("invalid number of nodes %d", config.Nodes)
+	}
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = sport.DefaultConfig.RequestTimeout
+	}
+	if config.Epoch == 0 {
+		config.Epoch = sport.DefaultConfig.Epoch
+	}
+	if config.SixtySixPercentBlock == nil {
+		config.SixtySixPercentBlock = new(big.Int)
+	}
+	if config.Tick == 0 {
+		config.Tick = 10 * time.Millisecond
+	}
+	chainConfig := *params.TestChainConfig
+	chainConfig.Ethash = nil
+	chainConfig.Sport = &params.SportConfig{Epoch: config.Epoch}
+	chainConfig.SixtySixPercentBlock = config.SixtySixPercentBlock
+
+	n := &Network{
+		config:      config,
+		chainConfig: &chainConfig,
+		clock:       new(mclock.Simulated),
+		index:       make(map[enode.ID]int),
+		nodes:       make([]*fullnode, config.Nodes),
+		links:       make([][]link, config.Nodes),
+		group:       make([]int, config.Nodes),
+		inbox:       make(map[msgKey]*envelope),
+		commits:     make(map[uint64]common.Hash),
+	}
+	n.cond = sync.NewCond(&n.lock)
+	n.timers = &timers{net: n}
+	n.adapter = adapters.NewSimAdapter(map[string]adapters.ServiceFunc{
+		protocolName: func(ctx *adapters.ServiceContext) (node.Service, error) {
+			return &service{net: n, self: n.index[ctx.Config.ID]}, nil
+		},
+	})
+	for i := 0; i < config.Nodes; i++ {
+		seed := crypto.Keccak256(big.NewInt(config.Seed).Bytes(), big.NewInt(int64(i)).Bytes())
+		key, err := crypto.ToECDSA(seed)
+		if err != nil {
+			return nil, err
+		}
+		n.keys = append(n.keys, key)
+		n.addrs = append(n.addrs, crypto.PubkeyToAddress(key.PublicKey))
+		n.links[i] = make([]link, config.Nodes)
+	}
+	genesis, err := n.genesis()
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range n.keys {
+		id := enode.PubkeyToIDV4(&key.PublicKey)
+		sim, err := n.adapter.NewNode(&adapters.NodeConfig{
+			ID:         id,
+			PrivateKey: key,
+			Name:       fmt.Sprintf("node%d", i),
+			Services:   []string{protocolName},
+		})
+		if err != nil {
+			return nil, err
+		}
+		n.index[id] = i
+		n.nodes[i] = &fullnode{sim: sim.(*adapters.SimNode), db: rawdb.NewMemoryDatabase()}
+		if _, err := genesis.Commit(n.nodes[i].db); err != nil {
+			return nil, err
+		}
+	}
+	// Connect every node before starting the engines, so none of them misses
+	// the first messages of the others
+	for i := range n.nodes {
+		if err := n.connect(i); err != nil {
+			n.Stop()
+			return nil, err
+		}
+	}
+	for i := range n.nodes {
+		if err := n.start(i); err != nil {
+			n.Stop()
+			return nil, err
+		}
+	}
+	n.clock.AfterFunc(syncInterval, n.sync)
+	return n, nil
+}
+
+// genesis returns the genesis block listing every node as fullnode.
+func (n *Network) genesis() (*core.Genesis, error) {
+	extra, err := rlp.EncodeToBytes(&types.SportExtra{Fullnodes: n.addrs, Seal: []byte{}, CommittedSeal: [][]byte{}})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Genesis{
+		Config:     n.chainConfig,
+		ExtraData:  append(make([]byte, types.SportExtraVanity), extra...),
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+		Mixhash:    types.SportDigest,
+		Alloc:      core.GenesisAlloc{},
+	}, nil
+}
+
+// Stop crashes every node.
+func (n *Network) Stop() {
+	for i := range n.nodes {
+		n.Crash(i)
+	}
+}
+
+// Address returns the fullnode address of node i.
+func (n *Network) Address(i int) common.Address {
+	return n.addrs[i]
+}
+
+// Clock returns the simulated clock of the network.
+func (n *Network) Clock() *mclock.Simulated {
+	return n.clock
+}
+
+// Chain returns the chain of node i, nil while it is crashed.
+func (n *Network) Chain(i int) *core.BlockChain {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.nodes[i].engine == nil {
+		return nil
+	}
+	return n.nodes[i].chain
+}
+
+// Height returns the head block number of node i, which is kept while crashed.
+func (n *Network) Height(i int) uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.nodes[i].chain == nil {
+		return 0
+	}
+	return n.nodes[i].chain.CurrentBlock().NumberU64()
+}
+
+// Stats returns the number of messages sent and dropped so far.
+func (n *Network) Stats() (sent, dropped int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.sent, n.dropped
+}
+
+// ----------------------------------------------------------------------------
+
+// SetDelay sets the latency of every link of the network.
+func (n *Network) SetDelay(delay time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for from := range n.links {
+		for to := range n.links[from] {
+			n.links[from][to].delay = delay
+		}
+	}
+}
+
+// SetLinkDelay sets the latency of the messages sent from one node to another.
+func (n *Network) SetLinkDelay(from, to int, delay time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.links[from][to].delay = delay
+}
+
+// SetDropRate sets the probability of any message to be lost.
+func (n *Network) SetDropRate(rate float64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.dropRate = rate
+}
+
+// Partition splits the network into the given groups of nodes, those not listed
+// being isolated on their own. Messages in flight across groups are lost.
+func (n *Network) Partition(groups ...[]int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for i := range n.group {
+		n.group[i] = len(groups) + i
+	}
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			n.group[i] = g
+		}
+	}
+}
+
+// Heal reconnects every node.
+func (n *Network) Heal() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for i := range n.group {
+		n.group[i] = 0
+	}
+}
+
+// Crash stops node i as if its process was killed, its chain and write-ahead log
+// are kept for Restart.
+func (n *Network) Crash(i int) error {
+	n.lock.Lock()
+	nd := n.nodes[i]
+	engine := nd.engine
+	nd.engine = nil
+	n.lock.Unlock()
+
+	if engine == nil {
+		return errNodeCrashed
+	}
+	engine.Stop()
+	nd.chain.Stop()
+	if err := nd.sim.Stop(); err != nil {
+		return err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	nd.up = false
+	for !n.isolated(i) {
+		n.cond.Wait()
+	}
+	return nil
+}
+
+// Restart starts node i again on the chain and write-ahead log it crashed with.
+func (n *Network) Restart(i int) error {
+	n.lock.Lock()
+	running := n.nodes[i].engine != nil
+	n.lock.Unlock()
+
+	if running {
+		return errNodeRunning
+	}
+	if err := n.connect(i); err != nil {
+		return err
+	}
+	return n.start(i)
+}
+
+// connect starts the devp2p node of node i and waits until it is linked to
+// every other running node.
+func (n *Network) connect(i int) error {
+	nd := n.nodes[i]
+	if err := nd.sim.Start(nil); err != nil {
+		return err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	nd.up = true
+	for j, peer := range n.nodes {
+		if j != i && peer.up {
+			nd.sim.Server().AddPeer(peer.sim.Node())
+		}
+	}
+	for !n.linked() {
+		n.cond.Wait()
+	}
+	return nil
+}
+
+// start creates the engine and the chain of node i and proposes a block.
+func (n *Network) start(i int) error {
+	config := *sport.DefaultConfig
+	config.RequestTimeout = n.config.RequestTimeout
+	config.Epoch = n.config.Epoch
+	config.BlockPeriod = 0
+	config.Clock = n.timers
+	config.Events = sport.NewEventQueue()
+	if n.config.WALDir != "" {
+		config.WAL = filepath.Join(n.config.WALDir, fmt.Sprintf("node%d.wal", i))
+	}
+	nd := n.nodes[i]
+	engine := backend.New(&config, n.keys[i], nd.db)
+	chain, err := core.NewBlockChain(nd.db, &core.CacheConfig{TrieDirtyDisabled: true}, n.chainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		return err
+	}
+	engine.(consensus.Handler).SetBroadcaster(&broadcaster{net: n, from: i})
+
+	// Track the events before the engine starts, it may resume a round from its
+	// write-ahead log
+	n.lock.Lock()
+	nd.engine, nd.chain, nd.events = engine, chain, config.Events
+	n.lock.Unlock()
+
+	if err := engine.Start(chain, chain.CurrentBlock, chain.HasBadBlock); err != nil {
+		n.lock.Lock()
+		nd.engine = nil
+		n.lock.Unlock()
+
+		chain.Stop()
+		return err
+	}
+	n.settle()
+	n.propose(i)
+	return nil
+}
+
+// propose hands a block on top of the head of node i to its engine, as the
+// miner does through Seal.
+func (n *Network) propose(i int) {
+	n.lock.Lock()
+	nd := n.nodes[i]
+	engine, chain, events := nd.engine, nd.chain, nd.events
+	n.lock.Unlock()
+
+	if engine == nil {
+		return
+	}
+	block, err := n.proposal(i, engine, chain)
+	if err != nil {
+		return
+	}
+	events.Add()
+	if err := engine.(proposer).EventMux().Post(sport.RequestEvent{BlockProposal: block}); err != nil {
+		events.Done()
+	}
+	n.settle()
+}
+
+// proposal creates an empty block on top of the head of the chain, sealed by
+// node i. Its time is the simulated one, unlike Prepare and Seal which follow
+// the wall clock.
+func (n *Network) proposal(i int, engine consensus.SmiloBFT, chain *core.BlockChain) (*types.Block, error) {
+	parent := chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   core.CalcGasLimit(parent, params.GenesisGasLimit, params.GenesisGasLimit),
+	}
+	if err := engine.Prepare(chain, header); err != nil {
+		return nil, err
+	}
+	header.Time = parent.Time()
+	if now := uint64(time.Duration(n.clock.Now()) / time.Second); now > header.Time {
+		header.Time = now
+	}
+	state, _, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	block, err := engine.Finalize(chain, header, state, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	header = block.Header()
+	seal, err := crypto.Sign(crypto.Keccak256(engine.(proposer).SealHash(header).Bytes()), n.keys[i])
+	if err != nil {
+		return nil, err
+	}
+	extra, err := types.ExtractSportExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	extra.Seal = seal
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		return nil, err
+	}
+	header.Extra = append(header.Extra[:types.SportExtraVanity], payload...)
+	return block.WithSeal(header), nil
+}
+
+// commit records the block committed by node i and imports it, as the eth
+// protocol manager does with the blocks enqueued by the engine.
+func (n *Network) commit(i int, block *types.Block) {
+	n.lock.Lock()
+	n.record(block)
+	n.lock.Unlock()
+
+	n.insert(i, types.Blocks{block})
+}
+
+// insert imports the blocks into the chain of node i and proposes a block on
+// top of the new head, if any.
+func (n *Network) insert(i int, blocks types.Blocks) {
+	n.lock.Lock()
+	nd := n.nodes[i]
+	engine, chain := nd.engine, nd.chain
+	n.lock.Unlock()
+
+	if engine == nil {
+		return
+	}
+	head := chain.CurrentBlock().Hash()
+	if _, err := chain.InsertChain(blocks); err != nil || chain.CurrentBlock().Hash() == head {
+		return
+	}
+	engine.(consensus.Handler).NewChainHead(chain)
+	n.settle()
+	n.propose(i)
+}
+
+// record checks no other block was committed at the height of block, the caller
+// must hold the lock.
+func (n *Network) record(block *types.Block) {
+	number, hash := block.NumberU64(), block.Hash()
+	if committed, ok := n.commits[number]; !ok {
+		n.commits[number] = hash
+	} else if committed != hash {
+		n.violations = append(n.violations, fmt.Errorf("conflicting blocks committed at height %d: %x and %x", number, committed, hash))
+	}
+}
+
+// sync imports into every running node, in turn, the blocks of the most advanced
+// node it is connected to, and schedules the next sync.
+func (n *Network) sync() {
+	for i := range n.nodes {
+		n.settle()
+		if blocks := n.missing(i); len(blocks) > 0 {
+			n.insert(i, blocks)
+		}
+	}
+	n.clock.AfterFunc(syncInterval, n.sync)
+}
+
+// missing returns the blocks node i lacks from the most advanced node it is
+// connected to, the first one on ties.
+func (n *Network) missing(i int) types.Blocks {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	nd := n.nodes[i]
+	if nd.engine == nil {
+		return nil
+	}
+	best := nd.chain
+	for j, peer := range n.nodes {
+		if j != i && n.connected(j, i) && peer.chain.CurrentBlock().NumberU64() > best.CurrentBlock().NumberU64() {
+			best = peer.chain
+		}
+	}
+	var blocks types.Blocks
+	for number := nd.chain.CurrentBlock().NumberU64() + 1; number <= best.CurrentBlock().NumberU64(); number++ {
+		block := best.GetBlockByNumber(number)
+		if block == nil {
+			break
+		}
+		n.record(block)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// settle waits until every running engine handled the events posted to it and
+// every message written to a connection arrived. Engines only act upon events,
+// which only the loop of the network posts, so the network is then idle.
+func (n *Network) settle() {
+	n.lock.Lock()
+	var queues []*sport.EventQueue
+	for _, nd := range n.nodes {
+		if nd.engine != nil {
+			queues = append(queues, nd.events)
+		}
+	}
+	n.lock.Unlock()
+
+	for _, queue := range queues {
+		queue.Wait()
+	}
+	n.lock.Lock()
+	for n.transit > 0 {
+		n.cond.Wait()
+	}
+	n.lock.Unlock()
+}
+
+// ----------------------------------------------------------------------------
+
+// Run advances the simulated clock by d, firing the timers and delivering the
+// messages falling due in turn.
+func (n *Network) Run(d time.Duration) {
+	end := n.clock.Now() + mclock.AbsTime(d)
+	for now := n.clock.Now(); now < end; now = n.clock.Now() {
+		step := n.config.Tick
+		if remaining := time.Duration(end - now); remaining < step {
+			step = remaining
+		}
+		n.step(step)
+	}
+}
+
+// step advances the simulated clock by d and lets the network settle.
+func (n *Network) step(d time.Duration) {
+	n.clock.Run(d)
+	n.settle()
+}
+
+// WaitForHeight runs the network until every running node has imported the
+// block at the given height, or until the simulated timeout elapsed.
+func (n *Network) WaitForHeight(height uint64, timeout time.Duration) error {
+	end := n.clock.Now() + mclock.AbsTime(timeout)
+	for !n.reached(height) {
+		if n.clock.Now() >= end {
+			return errNoProgress
+		}
+		n.step(n.config.Tick)
+	}
+	return nil
+}
+
+// reached returns whether every running node has imported the block at the
+// given height.
+func (n *Network) reached(height uint64) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for _, nd := range n.nodes {
+		if nd.engine != nil && nd.chain.CurrentBlock().NumberU64() < height {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxHeight returns the height of the most advanced node.
+func (n *Network) MaxHeight() uint64 {
+	var max uint64
+	for i := range n.nodes {
+		if height := n.Height(i); height > max {
+			max = height
+		}
+	}
+	return max
+}
+
+// CheckSafety returns an error if two blocks were committed at the same height,
+// or if the canonical chains of any two nodes diverge.
+func (n *Network) CheckSafety() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if len(n.violations) > 0 {
+		return n.violations[0]
+	}
+	for i, nd := range n.nodes {
+		if nd.chain == nil {
+			continue
+		}
+		for number := uint64(1); number <= nd.chain.CurrentBlock().NumberU64(); number++ {
+			hash := rawdb.ReadCanonicalHash(nd.db, number)
+			for j := i + 1; j < len(n.nodes); j++ {
+				if n.nodes[j].chain == nil {
+					continue
+				}
+				if other := rawdb.ReadCanonicalHash(n.nodes[j].db, number); other != (common.Hash{}) && other != hash {
+					return fmt.Errorf("nodes %d and %d diverge at height %d: %x and %x", i, j, number, hash, other)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+// waitTimeout is in simulated time, long enough for several round changes.
+const waitTimeout = 5 * time.Minute
+
+func newTestNetwork(t *testing.T, config Config) *Network {
+	n, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create network: %v", err)
+	}
+	return n
+}
+
+func waitForHeight(t *testing.T, n *Network, height uint64) {
+	if err := n.WaitForHeight(height, waitTimeout); err != nil {
+		t.Fatalf("height %d: %v, heads at %d", height, err, n.MaxHeight())
+	}
+	if err := n.CheckSafety(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNetworkProgress(t *testing.T) {
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 1})
+	defer n.Stop()
+
+	n.SetDelay(50 * time.Millisecond)
+	waitForHeight(t, n, 5)
+}
+
+func TestNetworkCrashedFullnode(t *testing.T) {
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 2})
+	defer n.Stop()
+
+	waitForHeight(t, n, 1)
+
+	// One faulty fullnode out of four is tolerated, its turns as speaker time out
+	// and are taken over after a round change
+	if err := n.Crash(0); err != nil {
+		t.Fatalf("failed to crash node: %v", err)
+	}
+	waitForHeight(t, n, n.MaxHeight()+5)
+
+	// Two are not
+	if err := n.Crash(1); err != nil {
+		t.Fatalf("failed to crash node: %v", err)
+	}
+	n.Run(time.Second)
+	height := n.MaxHeight()
+	if err := n.WaitForHeight(height+1, time.Minute); err != errNoProgress {
+		t.Fatalf("progress without quorum: %v", err)
+	}
+}
+
+func TestNetworkPartition(t *testing.T) {
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 3})
+	defer n.Stop()
+
+	waitForHeight(t, n, 1)
+
+	// Neither half reaches a quorum
+	n.Partition([]int{0, 1}, []int{2, 3})
+	n.Run(time.Second)
+	height := n.MaxHeight()
+	if err := n.WaitForHeight(height+1, time.Minute); err != errNoProgress {
+		t.Fatalf("progress across partition: %v", err)
+	}
+	n.Heal()
+	waitForHeight(t, n, height+3)
+}
+
+func TestNetworkDrops(t *testing.T) {
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 4})
+	defer n.Stop()
+
+	n.SetDropRate(0.1)
+	waitForHeight(t, n, 5)
+
+	if _, dropped := n.Stats(); dropped == 0 {
+		t.Errorf("no message dropped")
+	}
+}
+
+func TestNetworkRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sport-simulation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 5, WALDir: dir})
+	defer n.Stop()
+
+	waitForHeight(t, n, 2)
+
+	// A restarted fullnode replays its write-ahead log and catches up
+	if err := n.Crash(3); err != nil {
+		t.Fatalf("failed to crash node: %v", err)
+	}
+	waitForHeight(t, n, n.MaxHeight()+2)
+	if err := n.Restart(3); err != nil {
+		t.Fatalf("failed to restart node: %v", err)
+	}
+	waitForHeight(t, n, n.MaxHeight()+2)
+}
+
+func TestNetworkSixtySixPercentBlock(t *testing.T) {
+	// Blocks keep being accepted when the 66% seal rule kicks in
+	n := newTestNetwork(t, Config{Nodes: 4, Seed: 6, SixtySixPercentBlock: big.NewInt(3)})
+	defer n.Stop()
+
+	waitForHeight(t, n, 6)
+}
+
+func TestNetworkDeterminism(t *testing.T) {
+	// The same seed and faults replay the same run
+	run := func() string {
+		n := newTestNetwork(t, Config{Nodes: 4, Seed: 7})
+		defer n.Stop()
+
+		n.SetDelay(30 * time.Millisecond)
+		n.SetLinkDelay(0, 1, 80*time.Millisecond)
+		n.SetDropRate(0.05)
+		waitForHeight(t, n, 3)
+		n.Partition([]int{0, 1, 2}, []int{3})
+		n.Run(20 * time.Second)
+		n.Heal()
+		waitForHeight(t, n, n.MaxHeight()+2)
+
+		sent, dropped := n.Stats()
+		trace := fmt.Sprintf("time %v sent %d dropped %d", n.Clock().Now(), sent, dropped)
+		for i := 0; i < 4; i++ {
+			head := n.Chain(i).CurrentBlock()
+			trace += fmt.Sprintf(", node %d head %d %x", i, head.NumberU64(), head.Hash())
+		}
+		return trace
+	}
+	if first, second := run(), run(); first != second {
+		t.Fatalf("runs differ:\n%s\n%s", first, second)
+	}
+}
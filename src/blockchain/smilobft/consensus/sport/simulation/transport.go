@@ -0,0 +1,433 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/p2p"
+	"go-didux/src/blockchain/smilobft/rpc"
+)
+
+const (
+	// protocolName is the name of the devp2p service and protocol carrying the
+	// consensus messages between the nodes.
+	protocolName = "sportsim"
+
+	// envelopeMsg is the only message code of the protocol.
+	envelopeMsg = 0x00
+)
+
+var (
+	// errLinkDown is returned when sending to a node that is crashed or on the
+	// other side of a partition. Gossip only logs it, like a dropped p2p peer.
+	errLinkDown = errors.New("link down")
+
+	errUnknownNode = errors.New("unknown node")
+)
+
+// link is the connection of a node to another one.
+type link struct {
+	delay   time.Duration     // Latency of every message, in simulated time
+	rw      p2p.MsgReadWriter // Devp2p connection the messages are written to, nil while down
+	seq     uint64            // Messages sent over the link so far
+	transit int               // Messages written to the connection and not arrived yet
+}
+
+// envelope is a consensus message on the wire.
+type envelope struct {
+	Seq     uint64 // Sequence number of the message on its link
+	Code    uint64 // Code of the consensus message
+	Payload []byte // Encoded consensus message
+}
+
+// msgKey identifies a message sent over a link.
+type msgKey struct {
+	from, to int
+	seq      uint64
+}
+
+// delivery is a message due to be handed to its receiver at a simulated time.
+type delivery struct {
+	msgKey
+	at mclock.AbsTime
+}
+
+// deliveries is a priority queue of messages, by delivery time and then by link
+// and sequence number, so that messages due at the same time are delivered in
+// the same order whatever order they were sent in.
+type deliveries []*delivery
+
+func (d deliveries) Len() int      { return len(d) }
+func (d deliveries) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+func (d deliveries) Less(i, j int) bool {
+	a, b := d[i], d[j]
+	if a.at != b.at {
+		return a.at < b.at
+	}
+	if a.from != b.from {
+		return a.from < b.from
+	}
+	if a.to != b.to {
+		return a.to < b.to
+	}
+	return a.seq < b.seq
+}
+
+func (d *deliveries) Push(x interface{}) { *d = append(*d, x.(*delivery)) }
+
+func (d *deliveries) Pop() interface{} {
+	old := *d
+	x := old[len(old)-1]
+	*d = old[:len(old)-1]
+	return x
+}
+
+// ----------------------------------------------------------------------------
+
+// service is the devp2p service of a node, running the protocol which carries
+// the consensus messages to the network.
+type service struct {
+	net  *Network
+	self int
+}
+
+// Protocols implements node.Service.
+func (s *service) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    protocolName,
+		Version: 1,
+		Length:  1,
+		Run:     s.run,
+	}}
+}
+
+// APIs implements node.Service.
+func (s *service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service.
+func (s *service) Start(server *p2p.Server) error { return nil }
+
+// Stop implements node.Service.
+func (s *service) Stop() error { return nil }
+
+// run links the node to the remote one for as long as the connection lasts, and
+// hands the messages read from it to the network until they are due.
+func (s *service) run(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	remote, ok := s.net.index[p.ID()]
+	if !ok {
+		return errUnknownNode
+	}
+	s.net.link(s.self, remote, rw)
+	defer s.net.unlink(s.self, remote, rw)
+
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		var env envelope
+		if err := msg.Decode(&env); err != nil {
+			return err
+		}
+		s.net.arrive(remote, s.self, &env)
+	}
+}
+
+// link records the connection of a node to another one, which carries the
+// messages sent to it.
+func (n *Network) link(from, to int, rw p2p.MsgReadWriter) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.links[from][to].rw = rw
+	n.cond.Broadcast()
+}
+
+// unlink forgets the connection of a node to another one, and the messages on
+// their way in both directions.
+func (n *Network) unlink(from, to int, rw p2p.MsgReadWriter) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.links[from][to].rw == rw {
+		n.links[from][to].rw = nil
+	}
+	for _, l := range []*link{&n.links[from][to], &n.links[to][from]} {
+		n.transit -= l.transit
+		l.transit = 0
+	}
+	n.cond.Broadcast()
+}
+
+// linked returns whether every running node is connected to every other, the
+// caller must hold the lock.
+func (n *Network) linked() bool {
+	for from := range n.nodes {
+		for to := range n.nodes {
+			if from != to && n.nodes[from].up && n.nodes[to].up && n.links[from][to].rw == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isolated returns whether node i has no connection left, the caller must hold
+// the lock.
+func (n *Network) isolated(i int) bool {
+	for j := range n.nodes {
+		if n.links[i][j].rw != nil || n.links[j][i].rw != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// arrive stores a message read from a connection until it is due.
+func (n *Network) arrive(from, to int, env *envelope) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	l := &n.links[from][to]
+	if l.transit == 0 {
+		return // The message was given up with its connection
+	}
+	l.transit--
+	n.transit--
+	n.inbox[msgKey{from: from, to: to, seq: env.Seq}] = env
+	if n.transit == 0 {
+		n.cond.Broadcast()
+	}
+}
+
+// ----------------------------------------------------------------------------
+
+// broadcaster is the consensus.Broadcaster of a node, standing in for the eth
+// protocol manager: peers are the other nodes of the network and committed
+// blocks are inserted into the local chain.
+type broadcaster struct {
+	net  *Network
+	from int
+}
+
+// Enqueue implements consensus.Broadcaster, importing the block committed by the
+// node once the engines settled.
+func (b *broadcaster) Enqueue(id string, block *types.Block) {
+	b.net.clock.AfterFunc(0, func() {
+		b.net.settle()
+		b.net.commit(b.from, block)
+	})
+}
+
+// FindPeers implements consensus.Broadcaster, every other node is connected.
+func (b *broadcaster) FindPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	peers := make(map[common.Address]consensus.Peer)
+	for to, addr := range b.net.addrs {
+		if to != b.from && targets[addr] {
+			peers[addr] = &peer{net: b.net, from: b.from, to: to}
+		}
+	}
+	return peers
+}
+
+// peer is the connection of a node to another one as seen by the engine.
+type peer struct {
+	net      *Network
+	from, to int
+}
+
+// Send implements consensus.Peer, writing the message to the devp2p connection
+// and delivering it after the delay of the link unless it is dropped.
+func (p *peer) Send(msgcode uint64, data interface{}) error {
+	payload, err := rlp.EncodeToBytes(data)
+	if err != nil {
+		return err
+	}
+	seq, rw, err := p.net.route(p.from, p.to)
+	if err != nil || rw == nil {
+		return err // Messages lost on the wire are not reported to the sender
+	}
+	if err := p2p.Send(rw, envelopeMsg, &envelope{Seq: seq, Code: msgcode, Payload: payload}); err != nil {
+		p.net.unlink(p.from, p.to, rw)
+		return err
+	}
+	return nil
+}
+
+func (p *peer) String() string {
+	return fmt.Sprintf("sim-%d->%d", p.from, p.to)
+}
+
+// route decides the fate of a message sent from one node to another, returning
+// its sequence number on the link and the connection to write it to, nil if it
+// is lost. Delivery is scheduled after the delay of the link.
+func (n *Network) route(from, to int) (uint64, p2p.MsgReadWriter, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.connected(from, to) {
+		return 0, nil, errLinkDown
+	}
+	l := &n.links[from][to]
+	l.seq++
+	if n.lost(from, to, l.seq) {
+		n.dropped++
+		return l.seq, nil, nil
+	}
+	n.sent++
+	l.transit++
+	n.transit++
+
+	d := &delivery{msgKey: msgKey{from: from, to: to, seq: l.seq}, at: n.clock.Now() + mclock.AbsTime(l.delay)}
+	heap.Push(&n.queue, d)
+	n.clock.AfterFunc(l.delay, func() { n.deliver(d.at) })
+	return l.seq, l.rw, nil
+}
+
+// lost decides whether a message is dropped, from the seed and the position of
+// the message on its link only, so that runs replay whatever order the engines
+// send their messages in. The caller must hold the lock.
+func (n *Network) lost(from, to int, seq uint64) bool {
+	if n.dropRate <= 0 {
+		return false
+	}
+	var buf [32]byte
+	binary.BigEndian.PutUint64(buf[0:], uint64(n.config.Seed))
+	binary.BigEndian.PutUint64(buf[8:], uint64(from))
+	binary.BigEndian.PutUint64(buf[16:], uint64(to))
+	binary.BigEndian.PutUint64(buf[24:], seq)
+	roll := binary.BigEndian.Uint64(crypto.Keccak256(buf[:])) >> 11
+	return float64(roll)/(1<<53) < n.dropRate
+}
+
+// connected returns whether messages flow from one node to the other, the caller
+// must hold the lock.
+func (n *Network) connected(from, to int) bool {
+	return n.nodes[from].engine != nil && n.nodes[to].engine != nil && n.group[from] == n.group[to] && n.links[from][to].rw != nil
+}
+
+// deliver hands the messages due by the given time to their receivers one by
+// one, letting the network settle in between. Messages to or from a node which
+// crashed or got partitioned away while they were in flight are lost.
+func (n *Network) deliver(at mclock.AbsTime) {
+	for {
+		n.settle()
+
+		n.lock.Lock()
+		if len(n.queue) == 0 || n.queue[0].at > at {
+			n.lock.Unlock()
+			return
+		}
+		d := heap.Pop(&n.queue).(*delivery)
+		env := n.inbox[d.msgKey]
+		delete(n.inbox, d.msgKey)
+
+		var handler consensus.Handler
+		if env != nil && n.connected(d.from, d.to) {
+			handler = n.nodes[d.to].engine.(consensus.Handler)
+		}
+		n.lock.Unlock()
+
+		if handler != nil {
+			handler.HandleMsg(n.addrs[d.from], p2p.Msg{Code: env.Code, Size: uint32(len(env.Payload)), Payload: bytes.NewReader(env.Payload)})
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+
+// timers is the clock of the engines: their timers are scheduled on the
+// simulated clock of the network and fire once it settled.
+type timers struct {
+	net *Network
+}
+
+// timer is a timer of an engine, which may be cancelled until it fires even if
+// the simulated clock already picked it.
+type timer struct {
+	event mclock.Event
+
+	lock      sync.Mutex
+	fired     bool
+	cancelled bool
+}
+
+// Now implements mclock.Clock.
+func (t *timers) Now() mclock.AbsTime {
+	return t.net.clock.Now()
+}
+
+// Sleep implements mclock.Clock.
+func (t *timers) Sleep(d time.Duration) {
+	<-t.After(d)
+}
+
+// After implements mclock.Clock.
+func (t *timers) After(d time.Duration) <-chan time.Time {
+	after := make(chan time.Time, 1)
+	t.AfterFunc(d, func() {
+		after <- (time.Time{}).Add(time.Duration(t.Now()))
+	})
+	return after
+}
+
+// AfterFunc implements mclock.Clock.
+func (t *timers) AfterFunc(d time.Duration, f func()) mclock.Event {
+	tm := new(timer)
+	tm.event = t.net.clock.AfterFunc(d, func() {
+		t.net.settle()
+
+		tm.lock.Lock()
+		if tm.cancelled {
+			tm.lock.Unlock()
+			return
+		}
+		tm.fired = true
+		tm.lock.Unlock()
+
+		f()
+	})
+	return tm
+}
+
+// Cancel implements mclock.Event.
+func (tm *timer) Cancel() bool {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	if tm.fired || tm.cancelled {
+		return false
+	}
+	tm.cancelled = true
+	tm.event.Cancel()
+	return true
+}
@@ -76,6 +76,9 @@ func (c *core) broadcast(msg *message) {
 		return
 	}
 
+	// Record the message before it leaves the fullnode
+	c.writeWAL(walSent, payload)
+
 	// Broadcast payload
 	if err = c.backend.Broadcast(c.fullnodeSet, payload); err != nil {
 		logger.Error("Failed to broadcast message", "msg", msg, "err", err)
@@ -122,7 +125,8 @@ func (c *core) commit() {
 		}
 
 		if err := c.backend.Commit(proposal, committedSeals); err != nil {
-			c.current.UnlockHash() //Unlock block when insertion fails
+			c.unlockHash() //Unlock block when insertion fails
+			c.setRoundChangeReason(roundChangeCommitFailure)
 			c.sendNextRoundChange()
 			return
 		}
@@ -236,7 +240,9 @@ func (c *core) updateRoundState(view *sport.View, fullnodeSet sport.FullnodeSet,
 		}
 	} else {
 		c.current = newRoundState(view, fullnodeSet, common.Hash{}, nil, nil, c.backend.HasBadBlockProposal)
+		c.signed = make(map[signedKey]*signedMessage)
 	}
+	c.writeWALView(view)
 }
 
 func (c *core) setState(state State) {
@@ -255,14 +261,14 @@ func (c *core) Address() common.Address {
 
 func (c *core) stopFuturePreprepareTimer() {
 	if c.futurePreprepareTimer != nil {
-		c.futurePreprepareTimer.Stop()
+		c.futurePreprepareTimer.Cancel()
 	}
 }
 
 func (c *core) stopTimer() {
 	c.stopFuturePreprepareTimer()
 	if c.roundChangeTimer != nil {
-		c.roundChangeTimer.Stop()
+		c.roundChangeTimer.Cancel()
 	}
 }
 
@@ -272,7 +278,12 @@ func (c *core) newRoundChangeTimer() {
 	// set timeout based on the round number
 	timeout := time.Duration(c.config.RequestTimeout) * time.Millisecond
 	round := c.current.Round().Uint64()
-	if round > 0 {
+	if round == 0 {
+		// Give the speaker the time to wait for transactions before an empty block
+		if lastBlockProposal, _ := c.backend.LastBlockProposal(); lastBlockProposal != nil {
+			timeout += c.backend.ProposalDelay(lastBlockProposal)
+		}
+	} else {
 		timeout += time.Duration(math.Pow(2, float64(round))) * time.Second
 		thisTimeout := time.Duration(c.config.MaxTimeout) * time.Second
 		if timeout > thisTimeout {
@@ -283,7 +294,7 @@ func (c *core) newRoundChangeTimer() {
 		}
 	}
 
-	c.roundChangeTimer = time.AfterFunc(timeout, func() {
+	c.roundChangeTimer = c.clock.AfterFunc(timeout, func() {
 		c.logger.Debug("newRoundChangeTimer, Timeout for round !", "round", round, "timeout", timeout, "timeoutOriginal", time.Duration(c.config.RequestTimeout)*time.Millisecond)
 		c.sendEvent(timeoutEvent{})
 	})
@@ -116,14 +116,17 @@ func (c *core) processBacklog() {
 	c.backlogsMu.Lock()
 	defer c.backlogsMu.Unlock()
 
-	for srcAddress, backlog := range c.backlogs {
-		if backlog == nil {
-			continue
-		}
-		_, src := c.fullnodeSet.GetByAddress(srcAddress)
-		if src == nil {
+	for srcAddress := range c.backlogs {
+		if _, src := c.fullnodeSet.GetByAddress(srcAddress); src == nil {
 			// fullnode is not available
 			delete(c.backlogs, srcAddress)
+		}
+	}
+	// Walk the backlogs in fullnode order, so that their messages are handled
+	// in the same order on every run
+	for _, src := range c.fullnodeSet.List() {
+		backlog := c.backlogs[src.Address()]
+		if backlog == nil {
 			continue
 		}
 		logger := c.logger.New("from", src, "state", c.state)
@@ -169,7 +172,7 @@ func (c *core) processBacklog() {
 			}
 			logger.Trace("Post backlog event", "msg", msg)
 
-			go c.sendEvent(backlogEvent{
+			c.sendEventAsync(backlogEvent{
 				src: src,
 				msg: msg,
 			})
@@ -84,7 +84,7 @@ func (c *core) handleCommit(msg *message, src sport.Fullnode) error {
 
 	if actualCommits >= requiredMinApprovers && c.state.Cmp(StateCommitted) < 0 {
 		// Still need to call LockHash here since state can skip Prepared state and jump directly to the Committed state.
-		c.current.LockHash()
+		c.lockHash()
 		c.commit()
 	} else {
 		logger := c.logger.New("state", c.state)
@@ -48,6 +48,7 @@ type testSystemBackend struct {
 
 	committedMsgs []testCommittedMsgs
 	sentMsgs      [][]byte // store the message when Send is called by core
+	misbehaviours []*sport.Misbehaviour
 
 	address common.Address
 	db      ethdb.Database
@@ -149,6 +150,10 @@ func (self *testSystemBackend) LastBlockProposal() (sport.BlockProposal, common.
 	return makeBlock(0), common.Address{}
 }
 
+func (self *testSystemBackend) ProposalDelay(proposal sport.BlockProposal) time.Duration {
+	return 0
+}
+
 // Only block height 5 will return true
 func (self *testSystemBackend) HasBlockProposal(hash common.Hash, number *big.Int) bool {
 	return number.Cmp(big.NewInt(5)) == 0
@@ -162,6 +167,10 @@ func (self *testSystemBackend) ParentFullnodes(proposal sport.BlockProposal) spo
 	return self.peers
 }
 
+func (self *testSystemBackend) ReportMisbehaviour(evidence *sport.Misbehaviour) {
+	self.misbehaviours = append(self.misbehaviours, evidence)
+}
+
 func (sb *testSystemBackend) Close() error {
 	return nil
 }
@@ -25,12 +25,21 @@ import (
 
 // Start implements core.Engine.Start
 func (c *core) Start() error {
+	records, err := c.openWAL()
+	if err != nil {
+		return err
+	}
+	// Tests will handle events itself, so we have to make subscribeEvents()
+	// be able to call in test. Subscribe first so that the messages sent to
+	// ourselves while resuming are not lost.
+	c.subscribeEvents()
+
 	// Start a new round from last sequence + 1
 	c.startNewRound(common.Big0)
+	// Resume the round left when the fullnode stopped
+	c.replayWAL(records)
+	c.publishStatus()
 
-	// Tests will handle events itself, so we have to make subscribeEvents()
-	// be able to call in test.
-	c.subscribeEvents()
 	go c.handleEvents()
 
 	return nil
@@ -43,6 +52,7 @@ func (c *core) Stop() error {
 
 	// Make sure the handler goroutine exits
 	c.handlerWg.Wait()
+	c.closeWAL()
 	return nil
 }
 
@@ -76,6 +86,7 @@ func (c *core) handleEvents() {
 	// Clear state
 	defer func() {
 		c.current = nil
+		c.publishStatus()
 		c.handlerWg.Done()
 	}()
 
@@ -113,22 +124,22 @@ func (c *core) handleEvents() {
 			case backlogEvent:
 				// No need to check signature for internal messages
 				if err := c.handleCheckedMsg(ev.msg, ev.src); err == nil {
-					p, err := ev.msg.Payload()
-					if err != nil {
+					if p, err := ev.msg.Payload(); err != nil {
 						c.logger.Warn("handleEvents, Get message payload failed", "err", err)
-						continue
-					}
-					err = c.backend.Gossip(c.fullnodeSet, p)
-					if err != nil {
+					} else if err := c.backend.Gossip(c.fullnodeSet, p); err != nil {
 						c.logger.Error("$$$ SmiloBFT, handleEvents, handleCheckedMsg, backend.Gossip ", "err", err)
 					}
 				}
 			}
+			c.publishStatus()
+			c.eventQueue.Done()
 		case _, ok := <-c.timeoutSub.Chan():
 			if !ok {
 				return
 			}
 			c.handleTimeoutMsg()
+			c.publishStatus()
+			c.eventQueue.Done()
 		case event, ok := <-c.finalCommittedSub.Chan():
 			if !ok {
 				return
@@ -140,18 +151,27 @@ func (c *core) handleEvents() {
 					c.logger.Error("$$$ SmiloBFT, handleEvents, FinalCommittedEvent, handleFinalCommitted", "err", err)
 				}
 			}
+			c.publishStatus()
+			c.eventQueue.Done()
 		}
 	}
 }
 
 // sendEvent sends events to mux
 func (c *core) sendEvent(ev interface{}) {
+	c.eventQueue.Add()
 	err := c.backend.EventMux().Post(ev)
 	if err != nil {
 		c.logger.Error("$$$ SmiloBFT, sendEvent", "err", err)
+		c.eventQueue.Done()
 	}
 }
 
+// sendEventAsync sends events to mux without waiting for the core to take them
+func (c *core) sendEventAsync(ev interface{}) {
+	c.eventQueue.Post(c.backend.EventMux(), ev)
+}
+
 func (c *core) handleMsg(payload []byte) error {
 	logger := c.logger.New()
 
@@ -168,6 +188,7 @@ func (c *core) handleMsg(payload []byte) error {
 		logger.Error("Invalid address in message", "msg", msg)
 		return sport.ErrUnauthorizedAddress
 	}
+	c.writeWAL(walReceived, payload)
 
 	return c.handleCheckedMsg(msg, src)
 }
@@ -184,6 +205,8 @@ func (c *core) handleCheckedMsg(msg *message, src sport.Fullnode) error {
 		return err
 	}
 
+	c.checkMisbehaviour(msg)
+
 	switch msg.Code {
 	case msgPreprepare:
 		return testBacklog(c.handlePreprepare(msg, src))
@@ -209,6 +232,7 @@ func (c *core) handleTimeoutMsg() {
 		maxRound := c.roundChangeSet.MaxRound(FE)
 		if maxRound != nil && maxRound.Cmp(c.current.Round()) > 0 {
 			c.logger.Debug("********** handleTimeoutMsg, sendRoundChange", "maxRound", maxRound, "FE", FE)
+			c.setRoundChangeReason(roundChangeCatchUp)
 			c.sendRoundChange(maxRound)
 			return
 		} else {
@@ -221,6 +245,7 @@ func (c *core) handleTimeoutMsg() {
 		c.logger.Warn("********** handleTimeoutMsg, round change timeout, catch up latest sequence", "number", lastBlockProposal.Number().Uint64())
 		c.startNewRound(common.Big0)
 	} else {
+		c.setRoundChangeReason(roundChangeTimeout)
 		c.sendNextRoundChange()
 	}
 }
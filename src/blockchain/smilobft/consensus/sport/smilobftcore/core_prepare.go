@@ -66,7 +66,7 @@ func (c *core) handlePrepare(msg *message, src sport.Fullnode) error {
 	if ((c.current.IsHashLocked() && prepare.Digest == c.current.GetLockedHash()) || c.current.GetPrepareOrCommitSize() >= minApprovers) &&
 		c.state.Cmp(StatePrepared) < 0 {
 		c.logger.Debug("66% CONSENSUS REACHED!!!", "Required Votes:", c.fullnodeSet.MinApprovers(), "Total Votes:", c.current.GetPrepareOrCommitSize())
-		c.current.LockHash()
+		c.lockHash()
 		c.setState(StatePrepared)
 		c.sendCommit()
 	} else {
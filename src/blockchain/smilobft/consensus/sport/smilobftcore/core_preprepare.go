@@ -99,13 +99,14 @@ func (c *core) handlePreprepare(msg *message, src sport.Fullnode) error {
 		// if it's a future block, we will handle it again after the duration
 		if err == consensus.ErrFutureBlock {
 			c.stopFuturePreprepareTimer()
-			c.futurePreprepareTimer = time.AfterFunc(duration, func() {
+			c.futurePreprepareTimer = c.clock.AfterFunc(duration, func() {
 				c.sendEvent(backlogEvent{
 					src: src,
 					msg: msg,
 				})
 			})
 		} else {
+			c.setRoundChangeReason(roundChangeInvalid)
 			c.sendNextRoundChange()
 		}
 		return err
@@ -127,6 +128,7 @@ func (c *core) handlePreprepare(msg *message, src sport.Fullnode) error {
 			} else {
 				// Send round change
 				logger.Debug("$$$ SmiloBFT, handlePreprepare, StateAcceptRequest, Send round change")
+				c.setRoundChangeReason(roundChangeLockConflict)
 				c.sendNextRoundChange()
 			}
 		} else {
@@ -96,7 +96,7 @@ func (c *core) processPendingRequests() {
 		}
 		c.logger.Trace("Post pending request", "number", r.BlockProposal.Number(), "hash", r.BlockProposal.Hash())
 
-		go c.sendEvent(sport.RequestEvent{
+		c.sendEventAsync(sport.RequestEvent{
 			BlockProposal: r.BlockProposal,
 		})
 	}
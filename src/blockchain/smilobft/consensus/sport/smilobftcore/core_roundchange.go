@@ -133,6 +133,7 @@ func (c *core) handleRoundChange(msg *message, src sport.Fullnode) error {
 				"E", c.fullnodeSet.E(),
 				"messageCount==MinApprovers", messageCount == c.fullnodeSet.MinApprovers(),
 			)
+			c.setRoundChangeReason(roundChangeCatchUp)
 			c.sendRoundChange(roundView.Round)
 		} else {
 			logger.Debug("handleRoundChange, F+1 ROUND CHANGE, waitingForRoundChange && isRoundNumberSmallerThenCertificate, but Still Waiting For Round Change, (same round number and sequence number)",
@@ -42,4 +42,7 @@ var (
 	errFailedDecodePrepare = errors.New("failed to decode PREPARE")
 	// errFailedDecodeCommit is returned when the COMMIT message is malformed.
 	errFailedDecodeCommit = errors.New("failed to decode COMMIT")
+	// errInvalidMisbehaviour is returned when the evidence does not prove its
+	// offender signed conflicting messages.
+	errInvalidMisbehaviour = errors.New("invalid misbehaviour evidence")
 )
@@ -0,0 +1,135 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+)
+
+// signedKey identifies the messages a fullnode may sign only once per view.
+type signedKey struct {
+	code    uint64
+	address common.Address
+	round   uint64
+}
+
+// signedMessage is the first message a fullnode signed for a signedKey.
+type signedMessage struct {
+	digest   common.Hash
+	payload  []byte
+	reported bool
+}
+
+// messageDigest returns the view a PRE-PREPARE, PREPARE or COMMIT message was
+// signed for and the digest of the proposal it endorses.
+func messageDigest(msg *message) (*sport.View, common.Hash, error) {
+	switch msg.Code {
+	case msgPreprepare:
+		var preprepare *sport.Preprepare
+		if err := msg.Decode(&preprepare); err != nil {
+			return nil, common.Hash{}, errFailedDecodePreprepare
+		}
+		return preprepare.View, preprepare.BlockProposal.Hash(), nil
+	case msgPrepare, msgCommit:
+		var subject *sport.Subject
+		if err := msg.Decode(&subject); err != nil {
+			return nil, common.Hash{}, errInvalidMessage
+		}
+		return subject.View, subject.Digest, nil
+	}
+	return nil, common.Hash{}, errIgnored
+}
+
+// checkMisbehaviour reports the sender of msg to the backend if it signed a
+// message endorsing another proposal for the same view of the current height.
+func (c *core) checkMisbehaviour(msg *message) {
+	view, digest, err := messageDigest(msg)
+	if err != nil || c.current == nil || view.Sequence.Cmp(c.current.Sequence()) != 0 {
+		return
+	}
+	key := signedKey{code: msg.Code, address: msg.Address, round: view.Round.Uint64()}
+
+	seen, ok := c.signed[key]
+	if !ok {
+		payload, err := msg.Payload()
+		if err != nil {
+			return
+		}
+		c.signed[key] = &signedMessage{digest: digest, payload: payload}
+		return
+	}
+	if seen.digest == digest || seen.reported {
+		return
+	}
+	payload, err := msg.Payload()
+	if err != nil {
+		return
+	}
+	seen.reported = true
+
+	evidence := &sport.Misbehaviour{
+		Offender: msg.Address,
+		Code:     msg.Code,
+		View:     view,
+		First:    seen.payload,
+		Second:   payload,
+	}
+	c.logger.Warn("Fullnode signed conflicting messages", "evidence", evidence, "first", seen.digest, "second", digest)
+	c.backend.ReportMisbehaviour(evidence)
+}
+
+// VerifyMisbehaviour checks the evidence proves its offender signed two
+// messages endorsing different proposals for the same view.
+func VerifyMisbehaviour(evidence *sport.Misbehaviour) error {
+	if evidence.View == nil || evidence.View.Round == nil || evidence.View.Sequence == nil {
+		return errInvalidMisbehaviour
+	}
+	var digests [2]common.Hash
+	for i, payload := range [][]byte{evidence.First, evidence.Second} {
+		msg := new(message)
+		err := msg.FromPayload(payload, func(data []byte, sig []byte) (common.Address, error) {
+			signer, err := sport.GetSignatureAddress(data, sig)
+			if err != nil {
+				return common.Address{}, err
+			}
+			if signer != evidence.Offender {
+				return common.Address{}, sport.ErrUnauthorizedAddress
+			}
+			return signer, nil
+		})
+		if err != nil {
+			return err
+		}
+		if msg.Address != evidence.Offender || msg.Code != evidence.Code {
+			return errInvalidMisbehaviour
+		}
+		view, digest, err := messageDigest(msg)
+		if err != nil {
+			return err
+		}
+		if view.Cmp(evidence.View) != 0 {
+			return errInvalidMisbehaviour
+		}
+		digests[i] = digest
+	}
+	if digests[0] == digests[1] {
+		return errInvalidMisbehaviour
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+)
+
+// signedSubject returns a message of the given code endorsing digest, signed by key.
+func signedSubject(t *testing.T, key *ecdsa.PrivateKey, code uint64, round int64, digest common.Hash) *message {
+	subject, err := Encode(&sport.Subject{
+		View:   &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(round)},
+		Digest: digest,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode subject: %v", err)
+	}
+	msg := &message{Code: code, Msg: subject, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	data, _ := msg.PayloadNoSig()
+	if msg.Signature, err = crypto.Sign(crypto.Keccak256(data), key); err != nil {
+		t.Fatalf("failed to sign message: %v", err)
+	}
+	return msg
+}
+
+func TestCheckMisbehaviour(t *testing.T) {
+	sys := NewTestSystemWithBackend(4)
+	backend := sys.backends[0]
+	c := backend.engine.(*core)
+	key, _ := crypto.GenerateKey()
+
+	c.checkMisbehaviour(signedSubject(t, key, msgPrepare, 0, common.Hash{1}))
+	c.checkMisbehaviour(signedSubject(t, key, msgPrepare, 0, common.Hash{1}))
+	c.checkMisbehaviour(signedSubject(t, key, msgCommit, 0, common.Hash{2}))
+	c.checkMisbehaviour(signedSubject(t, key, msgPrepare, 1, common.Hash{2}))
+	if len(backend.misbehaviours) != 0 {
+		t.Fatalf("misbehaviour reported for consistent messages: %v", backend.misbehaviours)
+	}
+	c.checkMisbehaviour(signedSubject(t, key, msgPrepare, 0, common.Hash{2}))
+	c.checkMisbehaviour(signedSubject(t, key, msgPrepare, 0, common.Hash{3}))
+	if len(backend.misbehaviours) != 1 {
+		t.Fatalf("misbehaviour reports mismatch: have %d, want 1", len(backend.misbehaviours))
+	}
+	evidence := backend.misbehaviours[0]
+	if evidence.Offender != crypto.PubkeyToAddress(key.PublicKey) || evidence.Code != msgPrepare {
+		t.Fatalf("evidence mismatch: %v", evidence)
+	}
+	if err := VerifyMisbehaviour(evidence); err != nil {
+		t.Fatalf("failed to verify evidence: %v", err)
+	}
+
+	// A new height forgets the messages signed before
+	c.updateRoundState(&sport.View{Sequence: big.NewInt(2), Round: big.NewInt(0)}, c.fullnodeSet, false)
+	if len(c.signed) != 0 {
+		t.Errorf("signed messages kept for a new height: %d", len(c.signed))
+	}
+}
+
+func TestVerifyMisbehaviour(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	payload := func(msg *message) []byte {
+		payload, _ := msg.Payload()
+		return payload
+	}
+	view := &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)}
+	tests := []struct {
+		evidence *sport.Misbehaviour
+		valid    bool
+	}{
+		{
+			evidence: &sport.Misbehaviour{
+				Offender: crypto.PubkeyToAddress(key.PublicKey), Code: msgCommit, View: view,
+				First:  payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+				Second: payload(signedSubject(t, key, msgCommit, 0, common.Hash{2})),
+			},
+			valid: true,
+		},
+		{
+			// Same proposal endorsed twice
+			evidence: &sport.Misbehaviour{
+				Offender: crypto.PubkeyToAddress(key.PublicKey), Code: msgCommit, View: view,
+				First:  payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+				Second: payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+			},
+		},
+		{
+			// Messages signed by different fullnodes
+			evidence: &sport.Misbehaviour{
+				Offender: crypto.PubkeyToAddress(key.PublicKey), Code: msgCommit, View: view,
+				First:  payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+				Second: payload(signedSubject(t, other, msgCommit, 0, common.Hash{2})),
+			},
+		},
+		{
+			// Messages signed for different rounds
+			evidence: &sport.Misbehaviour{
+				Offender: crypto.PubkeyToAddress(key.PublicKey), Code: msgCommit, View: view,
+				First:  payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+				Second: payload(signedSubject(t, key, msgCommit, 1, common.Hash{2})),
+			},
+		},
+		{
+			// Messages of different kinds
+			evidence: &sport.Misbehaviour{
+				Offender: crypto.PubkeyToAddress(key.PublicKey), Code: msgCommit, View: view,
+				First:  payload(signedSubject(t, key, msgCommit, 0, common.Hash{1})),
+				Second: payload(signedSubject(t, key, msgPrepare, 0, common.Hash{2})),
+			},
+		},
+	}
+	for i, tt := range tests {
+		if err := VerifyMisbehaviour(tt.evidence); (err == nil) != tt.valid {
+			t.Errorf("test %d: verification mismatch: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
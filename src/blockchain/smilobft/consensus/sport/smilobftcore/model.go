@@ -20,9 +20,11 @@ package smilobftcore
 import (
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -34,16 +36,18 @@ import (
 // ----------------------------------------------------------------------------
 
 type core struct {
-	config  *sport.Config
-	address common.Address
-	state   State
-	logger  log.Logger
+	config     *sport.Config
+	address    common.Address
+	state      State
+	logger     log.Logger
+	clock      mclock.Clock      // Source of the round change and future proposal timeouts
+	eventQueue *sport.EventQueue // Queue of the events posted to the core, nil if untracked
 
 	backend               sport.Backend
 	events                *event.TypeMuxSubscription
 	finalCommittedSub     *event.TypeMuxSubscription
 	timeoutSub            *event.TypeMuxSubscription
-	futurePreprepareTimer *time.Timer
+	futurePreprepareTimer mclock.Event
 
 	fullnodeSet           sport.FullnodeSet
 	waitingForRoundChange bool
@@ -56,11 +60,19 @@ type core struct {
 	handlerWg *sync.WaitGroup
 
 	roundChangeSet   *roundChangeSet
-	roundChangeTimer *time.Timer
+	roundChangeTimer mclock.Event
 
 	pendingRequests   *prque.Prque
 	pendingRequestsMu *sync.Mutex
 
+	wal       *wal // Consensus write-ahead log, nil if disabled
+	replaying bool // Whether the write-ahead log is being replayed
+
+	signed map[signedKey]*signedMessage // Messages signed by the fullnodes for the current height
+
+	lastRoundChange atomic.Value // Reason of the last round change asked for
+	status          atomic.Value // Snapshot of the round state, published by the event handler
+
 	consensusTimestamp time.Time
 	// the meter to record the round change rate
 	roundMeter metrics.Meter
@@ -73,8 +85,14 @@ type core struct {
 // New creates an smilobft consensus core
 func New(backend sport.Backend, config *sport.Config) Engine {
 	r := metrics.NewRegistry()
+	clock := config.Clock
+	if clock == nil {
+		clock = mclock.System{}
+	}
 	c := &core{
 		config:             config,
+		clock:              clock,
+		eventQueue:         config.Events,
 		address:            backend.Address(),
 		state:              StateAcceptRequest,
 		handlerWg:          new(sync.WaitGroup),
@@ -84,6 +102,7 @@ func New(backend sport.Backend, config *sport.Config) Engine {
 		backlogsMu:         new(sync.Mutex),
 		pendingRequests:    prque.New(),
 		pendingRequestsMu:  new(sync.Mutex),
+		signed:             make(map[signedKey]*signedMessage),
 		consensusTimestamp: time.Time{},
 		roundMeter:         metrics.NewMeter(),
 		sequenceMeter:      metrics.NewMeter(),
@@ -193,6 +212,9 @@ type Engine interface {
 	IsSpeaker() bool
 
 	IsCurrentBlockProposal(blockHash common.Hash) bool
+
+	// Status returns the consensus state of the fullnode, nil if stopped
+	Status() *Status
 }
 
 // ----------------------------------------------------------------------------
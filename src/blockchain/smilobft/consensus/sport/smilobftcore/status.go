@@ -0,0 +1,106 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Reasons for a fullnode to ask for a round change.
+const (
+	roundChangeTimeout       = "round timeout"
+	roundChangeCatchUp       = "catching up with the round of other fullnodes"
+	roundChangeInvalid       = "invalid proposal"
+	roundChangeLockConflict  = "proposal conflicts with the locked proposal"
+	roundChangeCommitFailure = "failed to commit the proposal"
+)
+
+// Status is a snapshot of the consensus state of a fullnode.
+type Status struct {
+	Sequence              *big.Int               `json:"sequence"`              // Height being decided
+	Round                 *big.Int               `json:"round"`                 // Current round of the height
+	State                 string                 `json:"state"`                 // Step of the round
+	Speaker               common.Address         `json:"speaker"`               // Speaker of the round
+	IsSpeaker             bool                   `json:"isSpeaker"`             // Whether this fullnode is the speaker
+	LockedHash            common.Hash            `json:"lockedHash"`            // Proposal locked by this fullnode, if any
+	WaitingForRoundChange bool                   `json:"waitingForRoundChange"` // Whether the round waits for round changes
+	Prepares              []common.Address       `json:"prepares"`              // Fullnodes whose PREPARE was collected
+	Commits               []common.Address       `json:"commits"`               // Fullnodes whose COMMIT was collected
+	Backlogs              map[common.Address]int `json:"backlogs"`              // Future messages queued per fullnode
+	LastRoundChange       string                 `json:"lastRoundChange"`       // Why this fullnode last asked for a round change
+}
+
+// Status implements core.Engine.Status, it returns nil if the core is stopped.
+// The round state is the copy last published by the event handler, the backlogs
+// and the last round change are read live.
+func (c *core) Status() *Status {
+	published, _ := c.status.Load().(*Status)
+	if published == nil {
+		return nil
+	}
+	status := *published
+	status.Backlogs = make(map[common.Address]int)
+	if reason, ok := c.lastRoundChange.Load().(string); ok {
+		status.LastRoundChange = reason
+	}
+	c.backlogsMu.Lock()
+	for address, backlog := range c.backlogs {
+		status.Backlogs[address] = backlog.Size()
+	}
+	c.backlogsMu.Unlock()
+
+	return &status
+}
+
+// publishStatus snapshots the round state for Status. It must run on the
+// goroutine handling the events, the only one changing the round state.
+func (c *core) publishStatus() {
+	if c.current == nil || c.fullnodeSet == nil {
+		c.status.Store((*Status)(nil))
+		return
+	}
+	status := &Status{
+		Sequence:              new(big.Int).Set(c.current.Sequence()),
+		Round:                 new(big.Int).Set(c.current.Round()),
+		State:                 c.state.String(),
+		IsSpeaker:             c.fullnodeSet.IsSpeaker(c.address),
+		LockedHash:            c.current.GetLockedHash(),
+		WaitingForRoundChange: c.waitingForRoundChange,
+		Prepares:              messageSenders(c.current.Prepares),
+		Commits:               messageSenders(c.current.Commits),
+	}
+	if speaker := c.fullnodeSet.GetSpeaker(); speaker != nil {
+		status.Speaker = speaker.Address()
+	}
+	c.status.Store(status)
+}
+
+// setRoundChangeReason records why the fullnode asks for a round change.
+func (c *core) setRoundChangeReason(reason string) {
+	c.lastRoundChange.Store(reason)
+}
+
+// messageSenders returns the fullnodes whose message is in the set.
+func messageSenders(set *messageSet) []common.Address {
+	senders := []common.Address{}
+	for _, msg := range set.Values() {
+		senders = append(senders, msg.Address)
+	}
+	return senders
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+)
+
+func TestStatus(t *testing.T) {
+	sys := NewTestSystemWithBackend(4)
+	c := sys.backends[0].engine.(*core)
+	peer := sys.backends[1].engine.(*core)
+
+	c.state = StatePreprepared
+	c.setRoundChangeReason(roundChangeTimeout)
+
+	prepare, _ := Encode(&sport.Subject{View: c.currentView(), Digest: common.Hash{1}})
+	c.current.Prepares.Add(&message{Code: msgPrepare, Msg: prepare, Address: peer.address})
+
+	future, _ := Encode(&sport.Subject{View: &sport.View{Sequence: big.NewInt(5), Round: big.NewInt(0)}})
+	_, src := c.fullnodeSet.GetByAddress(peer.address)
+	c.storeBacklog(&message{Code: msgPrepare, Msg: future, Address: peer.address}, src)
+
+	// The round state is read from the copy published by the event handler
+	c.publishStatus()
+	c.state = StateCommitted
+
+	status := c.Status()
+	if status.Sequence.Uint64() != 1 || status.Round.Uint64() != 0 || status.State != StatePreprepared.String() {
+		t.Errorf("view mismatch: %+v", status)
+	}
+	if status.Speaker != c.fullnodeSet.GetSpeaker().Address() {
+		t.Errorf("speaker mismatch: have %x, want %x", status.Speaker, c.fullnodeSet.GetSpeaker().Address())
+	}
+	if want := []common.Address{peer.address}; !reflect.DeepEqual(status.Prepares, want) || len(status.Commits) != 0 {
+		t.Errorf("collected messages mismatch: prepares %x, commits %x", status.Prepares, status.Commits)
+	}
+	if want := map[common.Address]int{peer.address: 1}; !reflect.DeepEqual(status.Backlogs, want) {
+		t.Errorf("backlogs mismatch: have %v, want %v", status.Backlogs, want)
+	}
+	if status.LastRoundChange != roundChangeTimeout {
+		t.Errorf("round change reason mismatch: have %q, want %q", status.LastRoundChange, roundChangeTimeout)
+	}
+
+	c.current = nil
+	c.publishStatus()
+	if status := c.Status(); status != nil {
+		t.Errorf("status returned by stopped core: %+v", status)
+	}
+}
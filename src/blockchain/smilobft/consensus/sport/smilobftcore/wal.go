@@ -0,0 +1,304 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+)
+
+// The write-ahead log keeps the consensus messages and the lock of the height
+// being decided, so a restarted fullnode resumes the round it left and never
+// prepares or commits a block conflicting with what it already sent. Records are
+// framed by their length and checksum. The messages sent by the fullnode and its
+// lock changes are synced to disk before they are acted upon, received messages
+// and views only help resuming faster and ride along with the next sync. The log
+// is truncated whenever a new height starts.
+
+// Record kinds of the write-ahead log.
+const (
+	walView     uint64 = iota // The view entered by the fullnode
+	walSent                   // A message broadcast by the fullnode
+	walReceived               // A message received from a fullnode
+	walLock                   // The PRE-PREPARE of the locked proposal
+	walUnlock                 // The lock was released
+)
+
+// walHeaderSize is the size of the length and checksum preceding every record.
+const walHeaderSize = 8
+
+// maxWALRecordSize bounds the records read back, larger ones are corrupted.
+const maxWALRecordSize = 32 * 1024 * 1024
+
+var errWALRecordTooLarge = errors.New("wal record too large")
+
+// walRecord is an entry of the write-ahead log.
+type walRecord struct {
+	Kind     uint64
+	Sequence *big.Int
+	Round    *big.Int
+	Data     []byte
+}
+
+// wal is the consensus write-ahead log of a fullnode.
+type wal struct {
+	file     *os.File
+	sequence *big.Int // Height the records are kept for
+}
+
+// openWAL opens the write-ahead log at path, creating it if needed, and returns
+// the records it holds. A torn record left by a crash ends the log and is
+// discarded along with anything after it.
+func openWAL(path string) (*wal, []*walRecord, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, size := readWAL(file)
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	w := &wal{file: file}
+	if len(records) > 0 {
+		w.sequence = records[0].Sequence
+	}
+	return w, records, nil
+}
+
+// readWAL reads the valid records at the start of r and returns them with the
+// number of bytes they take.
+func readWAL(r io.Reader) ([]*walRecord, int64) {
+	var (
+		reader  = bufio.NewReader(r)
+		records []*walRecord
+		size    int64
+		header  [walHeaderSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			return records, size
+		}
+		length := binary.BigEndian.Uint32(header[:4])
+		if length > maxWALRecordSize {
+			return records, size
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return records, size
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			return records, size
+		}
+		record := new(walRecord)
+		if err := rlp.DecodeBytes(payload, record); err != nil {
+			return records, size
+		}
+		records = append(records, record)
+		size += walHeaderSize + int64(length)
+	}
+}
+
+// write appends a record to the log, syncing it to disk if requested.
+func (w *wal) write(record *walRecord, sync bool) error {
+	payload, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxWALRecordSize {
+		return errWALRecordTooLarge
+	}
+	buf := make([]byte, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:walHeaderSize], crc32.ChecksumIEEE(payload))
+	copy(buf[walHeaderSize:], payload)
+
+	if _, err := w.file.Write(buf); err != nil {
+		return err
+	}
+	if !sync {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// writeView records the view entered, dropping the records of previous heights.
+func (w *wal) writeView(view *sport.View) error {
+	if w.sequence == nil || w.sequence.Cmp(view.Sequence) != 0 {
+		if err := w.file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		w.sequence = new(big.Int).Set(view.Sequence)
+	}
+	return w.write(&walRecord{Kind: walView, Sequence: view.Sequence, Round: view.Round}, false)
+}
+
+// close closes the log file.
+func (w *wal) close() error {
+	return w.file.Close()
+}
+
+// ----------------------------------------------------------------------------
+
+// openWAL opens the write-ahead log configured for the fullnode, if any, and
+// returns the records left by its previous run.
+func (c *core) openWAL() ([]*walRecord, error) {
+	if c.config.NoWAL || c.config.WAL == "" {
+		return nil, nil
+	}
+	w, records, err := openWAL(c.config.WAL)
+	if err != nil {
+		return nil, err
+	}
+	c.wal = w
+	return records, nil
+}
+
+// closeWAL closes the write-ahead log of the fullnode.
+func (c *core) closeWAL() {
+	if c.wal == nil {
+		return
+	}
+	if err := c.wal.close(); err != nil {
+		c.logger.Error("Failed to close consensus WAL", "err", err)
+	}
+	c.wal = nil
+}
+
+// writeWAL records data of the given kind in the current view. Only what the
+// fullnode itself sent or locked on is synced, losing a received message only
+// costs waiting for it again.
+func (c *core) writeWAL(kind uint64, data []byte) {
+	if c.wal == nil || c.replaying || c.current == nil {
+		return
+	}
+	sync := kind != walReceived
+	if err := c.wal.write(&walRecord{Kind: kind, Sequence: c.current.Sequence(), Round: c.current.Round(), Data: data}, sync); err != nil {
+		c.logger.Error("Failed to write consensus WAL", "kind", kind, "err", err)
+	}
+}
+
+// writeWALView records the view entered by the fullnode.
+func (c *core) writeWALView(view *sport.View) {
+	if c.wal == nil || c.replaying {
+		return
+	}
+	if err := c.wal.writeView(view); err != nil {
+		c.logger.Error("Failed to write consensus WAL", "view", view, "err", err)
+	}
+}
+
+// lockHash locks the current proposal and records the lock.
+func (c *core) lockHash() {
+	c.current.LockHash()
+	if c.current.IsHashLocked() {
+		preprepare, err := Encode(c.current.Preprepare)
+		if err != nil {
+			c.logger.Error("Failed to encode locked PRE-PREPARE", "err", err)
+			return
+		}
+		c.writeWAL(walLock, preprepare)
+	}
+}
+
+// unlockHash releases the lock on the current proposal and records it.
+func (c *core) unlockHash() {
+	c.current.UnlockHash()
+	c.writeWAL(walUnlock, nil)
+}
+
+// replayWAL restores the view, the lock and the messages of the current height
+// recorded by the previous run of the fullnode.
+func (c *core) replayWAL(records []*walRecord) {
+	var (
+		sequence = c.current.Sequence()
+		round    = c.current.Round()
+		locked   *sport.Preprepare
+		msgs     []*message
+	)
+	for _, record := range records {
+		if record.Sequence.Cmp(sequence) != 0 {
+			continue
+		}
+		switch record.Kind {
+		case walView:
+			if record.Round.Cmp(round) > 0 {
+				round = record.Round
+			}
+		case walLock:
+			preprepare := new(sport.Preprepare)
+			if err := rlp.DecodeBytes(record.Data, preprepare); err != nil {
+				c.logger.Warn("Failed to decode locked PRE-PREPARE from WAL", "err", err)
+				continue
+			}
+			locked = preprepare
+		case walUnlock:
+			locked = nil
+		case walSent, walReceived:
+			msg := new(message)
+			if err := msg.FromPayload(record.Data, c.validateFn); err != nil {
+				c.logger.Warn("Failed to decode message from WAL", "err", err)
+				continue
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	if locked == nil && len(msgs) == 0 && round.Cmp(c.current.Round()) == 0 {
+		return
+	}
+	c.logger.Info("Replaying consensus WAL", "sequence", sequence, "round", round, "locked", locked != nil, "messages", len(msgs))
+
+	c.replaying = true
+	defer func() { c.replaying = false }()
+
+	if locked != nil {
+		c.current.SetPreprepare(locked)
+		c.current.LockHash()
+	}
+	if round.Cmp(c.current.Round()) > 0 {
+		c.startNewRound(round)
+	}
+	for _, msg := range msgs {
+		_, src := c.fullnodeSet.GetByAddress(msg.Address)
+		if src == nil {
+			continue
+		}
+		if err := c.handleCheckedMsg(msg, src); err != nil {
+			c.logger.Trace("Replayed message not applied", "msg", msg, "err", err)
+		}
+	}
+}
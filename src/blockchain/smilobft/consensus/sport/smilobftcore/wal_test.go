@@ -0,0 +1,160 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package smilobftcore
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/consensus/sport"
+)
+
+func newTestWAL(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "sport-wal")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	return filepath.Join(dir, "wal"), func() { os.RemoveAll(dir) }
+}
+
+func TestWALTornRecord(t *testing.T) {
+	path, cleanup := newTestWAL(t)
+	defer cleanup()
+
+	w, records, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("failed to open wal: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("records in new wal: %d", len(records))
+	}
+	view := &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)}
+	if err := w.writeView(view); err != nil {
+		t.Fatalf("failed to write view: %v", err)
+	}
+	if err := w.write(&walRecord{Kind: walReceived, Sequence: view.Sequence, Round: view.Round, Data: []byte{1, 2, 3}}, false); err != nil {
+		t.Fatalf("failed to write record: %v", err)
+	}
+	info, _ := os.Stat(path)
+	size := info.Size()
+
+	// Simulate a crash in the middle of a write
+	w.file.Write([]byte{0, 0, 0, 100, 1, 2})
+	w.close()
+
+	w, records, err = openWAL(path)
+	if err != nil {
+		t.Fatalf("failed to reopen wal: %v", err)
+	}
+	defer w.close()
+	if len(records) != 2 || records[0].Kind != walView || records[1].Kind != walReceived {
+		t.Fatalf("records mismatch: %v", records)
+	}
+	if info, _ := os.Stat(path); info.Size() != size {
+		t.Errorf("torn record not truncated: have size %d, want %d", info.Size(), size)
+	}
+}
+
+func TestWALNewHeight(t *testing.T) {
+	path, cleanup := newTestWAL(t)
+	defer cleanup()
+
+	w, _, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("failed to open wal: %v", err)
+	}
+	w.writeView(&sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)})
+	w.write(&walRecord{Kind: walSent, Sequence: big.NewInt(1), Round: big.NewInt(0)}, true)
+	w.writeView(&sport.View{Sequence: big.NewInt(1), Round: big.NewInt(1)})
+	w.writeView(&sport.View{Sequence: big.NewInt(2), Round: big.NewInt(0)})
+	w.close()
+
+	w, records, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("failed to reopen wal: %v", err)
+	}
+	defer w.close()
+	if len(records) != 1 || records[0].Sequence.Uint64() != 2 {
+		t.Fatalf("records of previous height kept: %v", records)
+	}
+}
+
+func TestReplayWAL(t *testing.T) {
+	path, cleanup := newTestWAL(t)
+	defer cleanup()
+
+	proposal := newTestBlockProposal()
+	preprepare, _ := Encode(&sport.Preprepare{
+		View:          &sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)},
+		BlockProposal: proposal,
+	})
+	w, _, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("failed to open wal: %v", err)
+	}
+	w.writeView(&sport.View{Sequence: big.NewInt(1), Round: big.NewInt(0)})
+	w.write(&walRecord{Kind: walLock, Sequence: big.NewInt(1), Round: big.NewInt(0), Data: preprepare}, true)
+	w.writeView(&sport.View{Sequence: big.NewInt(1), Round: big.NewInt(2)})
+	w.close()
+
+	sys := NewTestSystemWithBackend(4)
+	go sys.listen()
+	defer close(sys.quit)
+
+	c := sys.backends[0].engine.(*core)
+	config := *c.config
+	config.WAL = path
+	c.config = &config
+	c.current = nil
+
+	records, err := c.openWAL()
+	if err != nil {
+		t.Fatalf("failed to open wal: %v", err)
+	}
+	defer c.closeWAL()
+	c.startNewRound(common.Big0)
+	c.replayWAL(records)
+	defer c.stopTimer()
+
+	if have := c.current.Round().Uint64(); have != 2 {
+		t.Errorf("round mismatch: have %d, want 2", have)
+	}
+	if !c.current.IsHashLocked() || c.current.GetLockedHash() != proposal.Hash() {
+		t.Errorf("lock not restored: have %x, want %x", c.current.GetLockedHash(), proposal.Hash())
+	}
+}
+
+func TestWALDisabled(t *testing.T) {
+	path, cleanup := newTestWAL(t)
+	defer cleanup()
+
+	c := &core{config: &sport.Config{WAL: path, NoWAL: true}}
+	if _, err := c.openWAL(); err != nil {
+		t.Fatalf("failed to open disabled wal: %v", err)
+	}
+	if c.wal != nil {
+		t.Fatalf("wal opened while disabled")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("wal file created while disabled: %v", err)
+	}
+}
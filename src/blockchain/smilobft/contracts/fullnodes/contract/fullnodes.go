@@ -0,0 +1,26 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package contract contains the compiled fullnode registry system contract.
+package contract
+
+// FullnodeRegistryABI is the ABI of the fullnode registry contract.
+const FullnodeRegistryABI = "[{\"constant\":false,\"inputs\":[],\"name\":\"register\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"exit\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getFullnodes\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"fullnode\",\"type\":\"address\"}],\"name\":\"stakeOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"minStake\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"fullnode\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"stake\",\"type\":\"uint256\"}],\"name\":\"Registered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"fullnode\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"stake\",\"type\":\"uint256\"}],\"name\":\"Exited\",\"type\":\"event\"}]"
+
+// FullnodeRegistryCode is the runtime bytecode of fullnodes.easm. The contract
+// has no constructor, it is placed in the genesis allocation together with its
+// initial storage.
+const FullnodeRegistryCode = `0x7c01000000000000000000000000000000000000000000000000000000006000350480631aa3a008146300000068578063e9fad8ee146300000109578063edef70021463000001f957806342623360146300000254578063375b3c0a146300000285575b600080fd5b5033600052600360205260406000205463000000c857600154341063000000635760005433817f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5630155600101803360005260036020526040600020556000555b336000526002602052604060002080543401806000529055337f6f3bf3fa84e4763a43b3d23f9d79be242d6d5c834941ff4c1111b67469e1150c60206000a2005b50346300000063573360005260036020526040600020805480156300000063576000546002811063000000635760019003806000557f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5630180548083600190037f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563015560005290600360205260406000205560009055600090553360005260026020526040600020805460008255905080600052600060006000600084335af11563000000635750337f920bb94eb3842a728db98228c375ff6b00c5bc5a54fac6736155517a0a20a61a60206000a2005b5060206000526000548060205260005b81811015630000024957807f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563015481602002604001526001016300000209565b506020026040016000f35b5060043573ffffffffffffffffffffffffffffffffffffffff16600052600260205260406000205460005260206000f35b5060015460005260206000f3`
@@ -0,0 +1,180 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fullnodes is the fullnode registry system contract of Sport chains
+// governing their fullnode set on-chain.
+package fullnodes
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi"
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind"
+	"go-didux/src/blockchain/smilobft/contracts/fullnodes/contract"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// Storage layout of the registry, see contract/fullnodes.easm.
+var (
+	countSlot    = common.Hash{}
+	minStakeSlot = common.BigToHash(big.NewInt(1))
+	listSlot     = crypto.Keccak256Hash(countSlot[:])
+)
+
+const (
+	stakeMapping = 2
+	indexMapping = 3
+)
+
+// StateReader is the part of the state database the registry is read from.
+type StateReader interface {
+	GetState(addr common.Address, key common.Hash) common.Hash
+}
+
+// StateDB is the part of the state database the registry is modified in.
+type StateDB interface {
+	StateReader
+	SetState(addr common.Address, key common.Hash, value common.Hash)
+}
+
+// ReadFullnodes returns the fullnodes registered in the registry at address in
+// the given state, in registration order.
+func ReadFullnodes(db StateReader, address common.Address) []common.Address {
+	count := db.GetState(address, countSlot).Big().Uint64()
+	fullnodes := make([]common.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		fullnodes = append(fullnodes, common.BytesToAddress(db.GetState(address, listEntrySlot(i)).Bytes()))
+	}
+	return fullnodes
+}
+
+// ReadStake returns the stake deposited by fullnode in the registry at address.
+func ReadStake(db StateReader, address, fullnode common.Address) *big.Int {
+	return db.GetState(address, mappingSlot(fullnode, stakeMapping)).Big()
+}
+
+// ReadMinStake returns the stake required to register in the registry at address.
+func ReadMinStake(db StateReader, address common.Address) *big.Int {
+	return db.GetState(address, minStakeSlot).Big()
+}
+
+// Evict removes fullnode from the registry at address the way exit does, except
+// that its stake is forfeited and stays in the registry. Like exit it never
+// removes the last fullnode. It returns whether the fullnode was removed.
+func Evict(db StateDB, address, fullnode common.Address) bool {
+	index := db.GetState(address, mappingSlot(fullnode, indexMapping)).Big().Uint64()
+	count := db.GetState(address, countSlot).Big().Uint64()
+	if index == 0 || count < 2 {
+		return false
+	}
+
+	// Move the last fullnode into the slot of the evicted one
+	last := db.GetState(address, listEntrySlot(count-1))
+	db.SetState(address, listEntrySlot(index-1), last)
+	db.SetState(address, mappingSlot(common.BytesToAddress(last.Bytes()), indexMapping), common.BigToHash(new(big.Int).SetUint64(index)))
+
+	// Clear the last slot, the index and the stake of the evicted fullnode
+	db.SetState(address, listEntrySlot(count-1), common.Hash{})
+	db.SetState(address, mappingSlot(fullnode, indexMapping), common.Hash{})
+	db.SetState(address, mappingSlot(fullnode, stakeMapping), common.Hash{})
+	db.SetState(address, countSlot, common.BigToHash(new(big.Int).SetUint64(count-1)))
+	return true
+}
+
+// GenesisAccount returns the genesis allocation of a registry with the given
+// fullnodes registered without stake, requiring minStake from new fullnodes.
+// There is no default minimum stake: it must be positive, otherwise anyone could
+// register for free and core.SetupGenesisBlock rejects the genesis.
+func GenesisAccount(fullnodes []common.Address, minStake *big.Int) core.GenesisAccount {
+	storage := map[common.Hash]common.Hash{
+		countSlot: common.BigToHash(new(big.Int).SetUint64(uint64(len(fullnodes)))),
+	}
+	if minStake != nil && minStake.Sign() > 0 {
+		storage[minStakeSlot] = common.BigToHash(minStake)
+	}
+	for i, fullnode := range fullnodes {
+		storage[listEntrySlot(uint64(i))] = common.BytesToHash(fullnode.Bytes())
+		storage[mappingSlot(fullnode, indexMapping)] = common.BigToHash(new(big.Int).SetUint64(uint64(i + 1)))
+	}
+	return core.GenesisAccount{
+		Code:    common.FromHex(contract.FullnodeRegistryCode),
+		Storage: storage,
+		Balance: new(big.Int),
+	}
+}
+
+// listEntrySlot returns the storage slot of the i-th fullnode.
+func listEntrySlot(i uint64) common.Hash {
+	slot := new(big.Int).Add(listSlot.Big(), new(big.Int).SetUint64(i))
+	return common.BigToHash(math.U256(slot))
+}
+
+// mappingSlot returns the storage slot of key in the mapping at slot.
+func mappingSlot(key common.Address, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(key.Bytes(), 32), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+}
+
+// FullnodeRegistry is a Go wrapper around the fullnode registry contract.
+type FullnodeRegistry struct {
+	contract *bind.BoundContract
+}
+
+// NewFullnodeRegistry binds the registry at address.
+func NewFullnodeRegistry(address common.Address, backend bind.ContractBackend) (*FullnodeRegistry, error) {
+	parsed, err := abi.JSON(strings.NewReader(contract.FullnodeRegistryABI))
+	if err != nil {
+		return nil, err
+	}
+	return &FullnodeRegistry{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// Register joins the fullnode set with the stake sent in opts.Value, or tops up
+// the stake of an already registered fullnode.
+func (r *FullnodeRegistry) Register(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return r.contract.Transact(opts, "register")
+}
+
+// Exit leaves the fullnode set and withdraws the stake of the sender.
+func (r *FullnodeRegistry) Exit(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return r.contract.Transact(opts, "exit")
+}
+
+// Fullnodes returns the registered fullnodes.
+func (r *FullnodeRegistry) Fullnodes(opts *bind.CallOpts) ([]common.Address, error) {
+	var out []common.Address
+	err := r.contract.Call(opts, &out, "getFullnodes")
+	return out, err
+}
+
+// StakeOf returns the stake deposited by fullnode.
+func (r *FullnodeRegistry) StakeOf(opts *bind.CallOpts, fullnode common.Address) (*big.Int, error) {
+	out := new(big.Int)
+	err := r.contract.Call(opts, &out, "stakeOf", fullnode)
+	return out, err
+}
+
+// MinStake returns the stake required to register.
+func (r *FullnodeRegistry) MinStake(opts *bind.CallOpts) (*big.Int, error) {
+	out := new(big.Int)
+	err := r.contract.Call(opts, &out, "minStake")
+	return out, err
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package fullnodes
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind"
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind/backends"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+var (
+	registryAddr = common.HexToAddress("0x0000000000000000000000000000000000000f01")
+	minStake     = big.NewInt(1e18)
+	funds        = new(big.Int).Mul(minStake, big.NewInt(10))
+
+	genesisKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	joinerKey, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	genesisAddr   = crypto.PubkeyToAddress(genesisKey.PublicKey)
+	joinerAddr    = crypto.PubkeyToAddress(joinerKey.PublicKey)
+)
+
+func TestFullnodeRegistry(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		registryAddr: GenesisAccount([]common.Address{genesisAddr}, minStake),
+		genesisAddr:  {Balance: funds},
+		joinerAddr:   {Balance: funds},
+	}, 180000000)
+	defer backend.Close()
+
+	registry, err := NewFullnodeRegistry(registryAddr, backend)
+	if err != nil {
+		t.Fatalf("failed to bind registry: %v", err)
+	}
+	// transact sends a transaction to the registry and reports whether it succeeded
+	transact := func(send func(*bind.TransactOpts) (*types.Transaction, error), opts *bind.TransactOpts, value *big.Int) bool {
+		opts.Value, opts.GasLimit = value, 200000
+		tx, err := send(opts)
+		if err != nil {
+			t.Fatalf("failed to send transaction: %v", err)
+		}
+		backend.Commit()
+		receipt, _ := backend.TransactionReceipt(context.Background(), tx.Hash())
+		return receipt.Status == types.ReceiptStatusSuccessful
+	}
+	check := func(want ...common.Address) {
+		t.Helper()
+		have, err := registry.Fullnodes(nil)
+		if err != nil {
+			t.Fatalf("failed to call registry: %v", err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("fullnodes mismatch: have %x, want %x", have, want)
+		}
+		statedb, _, _ := backend.Blockchain().State()
+		if have := ReadFullnodes(statedb, registryAddr); !reflect.DeepEqual(have, want) {
+			t.Fatalf("fullnodes in state mismatch: have %x, want %x", have, want)
+		}
+	}
+	genesisOpts, joinerOpts := bind.NewKeyedTransactor(genesisKey), bind.NewKeyedTransactor(joinerKey)
+
+	check(genesisAddr)
+	if have, _ := registry.MinStake(nil); have.Cmp(minStake) != 0 {
+		t.Fatalf("minimum stake mismatch: have %v, want %v", have, minStake)
+	}
+	if transact(registry.Register, joinerOpts, big.NewInt(1)) {
+		t.Fatalf("registered below minimum stake")
+	}
+	if transact(registry.Exit, genesisOpts, nil) {
+		t.Fatalf("last fullnode exited")
+	}
+	if !transact(registry.Register, joinerOpts, minStake) {
+		t.Fatalf("failed to register")
+	}
+	check(genesisAddr, joinerAddr)
+	if !transact(registry.Register, joinerOpts, big.NewInt(5)) {
+		t.Fatalf("failed to top up stake")
+	}
+	want := new(big.Int).Add(minStake, big.NewInt(5))
+	if have, _ := registry.StakeOf(nil, joinerAddr); have.Cmp(want) != 0 {
+		t.Fatalf("stake mismatch: have %v, want %v", have, want)
+	}
+	if !transact(registry.Exit, genesisOpts, nil) {
+		t.Fatalf("failed to exit")
+	}
+	check(joinerAddr)
+	if transact(registry.Exit, genesisOpts, nil) {
+		t.Fatalf("exited twice")
+	}
+	// Make room for the joiner to leave and check the stake is paid back
+	if !transact(registry.Register, genesisOpts, minStake) {
+		t.Fatalf("failed to register again")
+	}
+	balance, _ := backend.BalanceAt(context.Background(), joinerAddr, nil)
+	if !transact(registry.Exit, joinerOpts, nil) {
+		t.Fatalf("failed to exit")
+	}
+	check(genesisAddr)
+	statedb, _, _ := backend.Blockchain().State()
+	if have := ReadStake(statedb, registryAddr, joinerAddr); have.Sign() != 0 {
+		t.Fatalf("stake left after exit: %v", have)
+	}
+	if have, _ := backend.BalanceAt(context.Background(), joinerAddr, nil); have.Cmp(balance) <= 0 {
+		t.Fatalf("stake not withdrawn: balance %v, was %v", have, balance)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	first, second, third := common.Address{1}, common.Address{2}, common.Address{3}
+	for slot, value := range GenesisAccount([]common.Address{first, second, third}, minStake).Storage {
+		statedb.SetState(registryAddr, slot, value)
+	}
+	statedb.SetState(registryAddr, mappingSlot(first, stakeMapping), common.BigToHash(minStake))
+
+	if Evict(statedb, registryAddr, common.Address{4}) {
+		t.Fatalf("unregistered fullnode evicted")
+	}
+	if !Evict(statedb, registryAddr, first) {
+		t.Fatalf("registered fullnode not evicted")
+	}
+	if have, want := ReadFullnodes(statedb, registryAddr), []common.Address{third, second}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("fullnodes mismatch: have %x, want %x", have, want)
+	}
+	if stake := ReadStake(statedb, registryAddr, first); stake.Sign() != 0 {
+		t.Fatalf("stake of evicted fullnode not forfeited: %v", stake)
+	}
+	// The moved fullnode is evicted from its new slot
+	if !Evict(statedb, registryAddr, third) {
+		t.Fatalf("moved fullnode not evicted")
+	}
+	if have, want := ReadFullnodes(statedb, registryAddr), []common.Address{second}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("fullnodes mismatch: have %x, want %x", have, want)
+	}
+	if Evict(statedb, registryAddr, second) {
+		t.Fatalf("last fullnode evicted")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package contract contains the compiled permissions system contract and its
+// Go binding.
+package contract
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi"
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+// PermissionsABI is the ABI of the permissions contract.
+const PermissionsABI = "[{\"constant\":false,\"inputs\":[{\"name\":\"node\",\"type\":\"bytes32\"},{\"name\":\"org\",\"type\":\"bytes32\"}],\"name\":\"addNode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"node\",\"type\":\"bytes32\"}],\"name\":\"removeNode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"},{\"name\":\"org\",\"type\":\"bytes32\"},{\"name\":\"role\",\"type\":\"uint8\"}],\"name\":\"setAccount\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"org\",\"type\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\"}],\"name\":\"setOrgStatus\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"node\",\"type\":\"bytes32\"}],\"name\":\"nodeOrg\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"accountRole\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"accountOrg\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"org\",\"type\":\"bytes32\"}],\"name\":\"orgStatus\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"defaultRole\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"node\",\"type\":\"bytes32\"},{\"indexed\":false,\"name\":\"org\",\"type\":\"bytes32\"}],\"name\":\"NodeChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"account\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"org\",\"type\":\"bytes32\"},{\"indexed\":false,\"name\":\"role\",\"type\":\"uint8\"}],\"name\":\"AccountChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"org\",\"type\":\"bytes32\"},{\"indexed\":false,\"name\":\"status\",\"type\":\"uint8\"}],\"name\":\"OrgChanged\",\"type\":\"event\"}]"
+
+// PermissionsCode is the runtime bytecode of permissions.easm. The contract has
+// no constructor, it is placed in the genesis allocation together with its
+// initial storage.
+const PermissionsCode = `0x34630000009e577c010000000000000000000000000000000000000000000000000000000060003504806326f881571463000000a357806316c58824146300000143578063a31ee3351463000001f5578063b8c3c946146300000365578063cd2b8e081463000003cc5780632f1d60001463000003e757806394729e2c146300000418578063ffc7108814630000044957806314afba3b146300000464575b600080fd5b506024358015630000009e573360005260026020526040600020548060041463000001025760031415630000009e57336000526003602052604060002054811415630000009e57806000526004602052604060002054630000009e5760005b508060043560005260016020526040600020556000526004357f372244732146de96e8b4d0d1e2f388708cf1d62ebc3f08b2b803fb6c3adcadd160206000a2005b5060043560005260016020526040600020548015630000009e573360005260026020526040600020548060041463000001b05760031415630000009e57336000526003602052604060002054811415630000009e57806000526004602052604060002054630000009e5760005b50506000600435600052600160205260406000205560006000526004357f372244732146de96e8b4d0d1e2f388708cf1d62ebc3f08b2b803fb6c3adcadd160206000a2005b5060443560048111630000009e573360005260026020526040600020548060041463000002c85760031415630000009e576003811015630000009e57336000526003602052604060002054806024351415630000009e57806000526004602052604060002054630000009e5760043573ffffffffffffffffffffffffffffffffffffffff16600052600360205260406000205480159082141715630000009e5760043573ffffffffffffffffffffffffffffffffffffffff1660005260026020526040600020546003901015630000009e575b508060043573ffffffffffffffffffffffffffffffffffffffff16600052600260205260406000205560243560043573ffffffffffffffffffffffffffffffffffffffff16600052600360205260406000205560205260243560005260043573ffffffffffffffffffffffffffffffffffffffff167f4f31916f8a3fd2a715e83b8248b8418d94ab6f53618b1ba2c8cc9384f055776960406000a2005b5033600052600260205260406000205460041415630000009e5760243560018111630000009e578060043560005260046020526040600020556000526004357ffcd3f610dc33b11fac3a0b0617a033914a6d54ef2128a30a1cb8777e488f22c960206000a2005b50600435600052600160205260406000205460005260206000f35b5060043573ffffffffffffffffffffffffffffffffffffffff16600052600260205260406000205460005260206000f35b5060043573ffffffffffffffffffffffffffffffffffffffff16600052600360205260406000205460005260206000f35b50600435600052600460205260406000205460005260206000f35b5060005460005260206000f3`
+
+// Permissions is a Go wrapper around the permissions contract.
+type Permissions struct {
+	contract *bind.BoundContract
+}
+
+// NewPermissions binds the contract at address.
+func NewPermissions(address common.Address, backend bind.ContractBackend) (*Permissions, error) {
+	parsed, err := abi.JSON(strings.NewReader(PermissionsABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Permissions{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// AddNode permissions the node in the organisation.
+func (p *Permissions) AddNode(opts *bind.TransactOpts, node [32]byte, org [32]byte) (*types.Transaction, error) {
+	return p.contract.Transact(opts, "addNode", node, org)
+}
+
+// RemoveNode withdraws the permission of the node.
+func (p *Permissions) RemoveNode(opts *bind.TransactOpts, node [32]byte) (*types.Transaction, error) {
+	return p.contract.Transact(opts, "removeNode", node)
+}
+
+// SetAccount sets the organisation and the role of the account.
+func (p *Permissions) SetAccount(opts *bind.TransactOpts, account common.Address, org [32]byte, role uint8) (*types.Transaction, error) {
+	return p.contract.Transact(opts, "setAccount", account, org, role)
+}
+
+// SetOrgStatus suspends or reactivates the organisation.
+func (p *Permissions) SetOrgStatus(opts *bind.TransactOpts, org [32]byte, status uint8) (*types.Transaction, error) {
+	return p.contract.Transact(opts, "setOrgStatus", org, status)
+}
+
+// NodeOrg returns the organisation of the node, zero if it is not permissioned.
+func (p *Permissions) NodeOrg(opts *bind.CallOpts, node [32]byte) ([32]byte, error) {
+	var out [32]byte
+	err := p.contract.Call(opts, &out, "nodeOrg", node)
+	return out, err
+}
+
+// AccountRole returns the role set for the account.
+func (p *Permissions) AccountRole(opts *bind.CallOpts, account common.Address) (uint8, error) {
+	var out uint8
+	err := p.contract.Call(opts, &out, "accountRole", account)
+	return out, err
+}
+
+// AccountOrg returns the organisation of the account.
+func (p *Permissions) AccountOrg(opts *bind.CallOpts, account common.Address) ([32]byte, error) {
+	var out [32]byte
+	err := p.contract.Call(opts, &out, "accountOrg", account)
+	return out, err
+}
+
+// OrgStatus returns the status of the organisation.
+func (p *Permissions) OrgStatus(opts *bind.CallOpts, org [32]byte) (uint8, error) {
+	var out uint8
+	err := p.contract.Call(opts, &out, "orgStatus", org)
+	return out, err
+}
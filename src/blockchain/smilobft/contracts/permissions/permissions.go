@@ -0,0 +1,130 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package permissions reads the permissions system contract keeping the nodes
+// allowed to connect and the roles of the accounts of a network on-chain. The
+// compiled contract and its binding are in the contract package.
+package permissions
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/p2p/enode"
+)
+
+// Role is the permission of an account.
+type Role uint8
+
+// Roles of the accounts, every role includes the permissions of the lower ones.
+const (
+	RoleNone         Role = iota // Not allowed to send transactions
+	RoleTransact                 // Allowed to send transactions
+	RoleDeploy                   // Allowed to deploy contracts
+	RoleOrgAdmin                 // Manages the nodes and accounts of its organisation
+	RoleNetworkAdmin             // Manages every node, account and organisation
+)
+
+// Statuses of the organisations.
+const (
+	OrgActive    uint8 = iota
+	OrgSuspended       // Nodes and accounts of the organisation lose their permissions
+)
+
+// Storage layout of the contract, see contract/permissions.easm.
+var defaultRoleSlot = common.Hash{}
+
+const (
+	nodeOrgMapping     = 1
+	accountRoleMapping = 2
+	accountOrgMapping  = 3
+	orgStatusMapping   = 4
+)
+
+// StateReader is the part of the state database the permissions are read from.
+type StateReader interface {
+	GetState(addr common.Address, key common.Hash) common.Hash
+}
+
+// NodePermitted returns whether the node belongs to an active organisation in
+// the contract at address.
+func NodePermitted(db StateReader, address common.Address, node enode.ID) bool {
+	org := db.GetState(address, mappingSlot(common.Hash(node), nodeOrgMapping))
+	return org != (common.Hash{}) && orgActive(db, address, org)
+}
+
+// AccountRole returns the role of the account in the contract at address, at
+// least the default role unless its organisation is suspended.
+func AccountRole(db StateReader, address, account common.Address) Role {
+	key := common.BytesToHash(account.Bytes())
+	if org := db.GetState(address, mappingSlot(key, accountOrgMapping)); org != (common.Hash{}) && !orgActive(db, address, org) {
+		return RoleNone
+	}
+	role := readRole(db.GetState(address, mappingSlot(key, accountRoleMapping)))
+	if def := readRole(db.GetState(address, defaultRoleSlot)); def > role {
+		role = def
+	}
+	return role
+}
+
+// AccountPermitted returns whether the account may send a transaction, or create
+// a contract if create is set, according to the contract at address.
+func AccountPermitted(db StateReader, address, account common.Address, create bool) bool {
+	if create {
+		return AccountRole(db, address, account) >= RoleDeploy
+	}
+	return AccountRole(db, address, account) >= RoleTransact
+}
+
+// GenesisStorage returns the genesis storage of a contract where the admins are
+// network admins of org and the nodes belong to org, every other account having
+// defaultRole. The contract code to allocate with it is contract.PermissionsCode.
+func GenesisStorage(org common.Hash, admins []common.Address, nodes []enode.ID, defaultRole Role) map[common.Hash]common.Hash {
+	storage := make(map[common.Hash]common.Hash)
+	if defaultRole != RoleNone {
+		storage[defaultRoleSlot] = common.BigToHash(big.NewInt(int64(defaultRole)))
+	}
+	for _, admin := range admins {
+		key := common.BytesToHash(admin.Bytes())
+		storage[mappingSlot(key, accountRoleMapping)] = common.BigToHash(big.NewInt(int64(RoleNetworkAdmin)))
+		storage[mappingSlot(key, accountOrgMapping)] = org
+	}
+	for _, node := range nodes {
+		storage[mappingSlot(common.Hash(node), nodeOrgMapping)] = org
+	}
+	return storage
+}
+
+// orgActive returns whether the organisation is not suspended.
+func orgActive(db StateReader, address common.Address, org common.Hash) bool {
+	return db.GetState(address, mappingSlot(org, orgStatusMapping)) == (common.Hash{})
+}
+
+// readRole decodes a role stored in the contract, unknown ones grant nothing.
+func readRole(value common.Hash) Role {
+	role := value.Big()
+	if !role.IsUint64() || role.Uint64() > uint64(RoleNetworkAdmin) {
+		return RoleNone
+	}
+	return Role(role.Uint64())
+}
+
+// mappingSlot returns the storage slot of key in the mapping at slot.
+func mappingSlot(key common.Hash, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package permissions_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind"
+	"go-didux/src/blockchain/smilobft/accounts/abi/bind/backends"
+	"go-didux/src/blockchain/smilobft/contracts/permissions"
+	"go-didux/src/blockchain/smilobft/contracts/permissions/contract"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/p2p/enode"
+)
+
+var (
+	permissionsAddr = common.HexToAddress("0x0000000000000000000000000000000000000f02")
+	funds           = big.NewInt(1e18)
+
+	adminKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	orgAdminKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	adminAddr      = crypto.PubkeyToAddress(adminKey.PublicKey)
+	orgAdminAddr   = crypto.PubkeyToAddress(orgAdminKey.PublicKey)
+	userAddr       = common.HexToAddress("0x0000000000000000000000000000000000001234")
+
+	networkOrg = common.HexToHash("0x01")
+	memberOrg  = common.HexToHash("0x02")
+	otherOrg   = common.HexToHash("0x03")
+
+	genesisNode = enode.ID{1}
+	memberNode  = enode.ID{2}
+)
+
+func TestPermissions(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		permissionsAddr: {
+			Code:    common.FromHex(contract.PermissionsCode),
+			Storage: permissions.GenesisStorage(networkOrg, []common.Address{adminAddr}, []enode.ID{genesisNode}, permissions.RoleNone),
+			Balance: new(big.Int),
+		},
+		adminAddr:    {Balance: funds},
+		orgAdminAddr: {Balance: funds},
+	}, 180000000)
+	defer backend.Close()
+
+	binding, err := contract.NewPermissions(permissionsAddr, backend)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+	// transact sends a transaction to the contract and reports whether it succeeded
+	transact := func(key func() *bind.TransactOpts, send func(*bind.TransactOpts) (*types.Transaction, error)) bool {
+		t.Helper()
+		opts := key()
+		opts.GasLimit = 200000
+		tx, err := send(opts)
+		if err != nil {
+			t.Fatalf("failed to send transaction: %v", err)
+		}
+		backend.Commit()
+		receipt, _ := backend.TransactionReceipt(context.Background(), tx.Hash())
+		return receipt.Status == types.ReceiptStatusSuccessful
+	}
+	admin := func() *bind.TransactOpts { return bind.NewKeyedTransactor(adminKey) }
+	orgAdmin := func() *bind.TransactOpts { return bind.NewKeyedTransactor(orgAdminKey) }
+	state := func() permissions.StateReader {
+		statedb, _, _ := backend.Blockchain().State()
+		return statedb
+	}
+
+	if !permissions.NodePermitted(state(), permissionsAddr, genesisNode) || permissions.NodePermitted(state(), permissionsAddr, memberNode) {
+		t.Fatalf("genesis nodes mismatch")
+	}
+	if role := permissions.AccountRole(state(), permissionsAddr, adminAddr); role != permissions.RoleNetworkAdmin {
+		t.Fatalf("genesis admin role mismatch: have %d, want %d", role, permissions.RoleNetworkAdmin)
+	}
+	// Accounts without a role can't manage anything
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.AddNode(opts, memberNode, memberOrg)
+	}) {
+		t.Fatalf("node added by account without role")
+	}
+	// Network admins appoint organisation admins
+	if !transact(admin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetAccount(opts, orgAdminAddr, memberOrg, uint8(permissions.RoleOrgAdmin))
+	}) {
+		t.Fatalf("failed to appoint organisation admin")
+	}
+	if role, _ := binding.AccountRole(nil, orgAdminAddr); role != uint8(permissions.RoleOrgAdmin) {
+		t.Fatalf("organisation admin role mismatch: have %d", role)
+	}
+	// Organisation admins manage their own organisation only, below their role
+	if !transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.AddNode(opts, memberNode, memberOrg)
+	}) {
+		t.Fatalf("failed to add node of own organisation")
+	}
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.AddNode(opts, enode.ID{3}, otherOrg)
+	}) {
+		t.Fatalf("node added to another organisation")
+	}
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.RemoveNode(opts, genesisNode)
+	}) {
+		t.Fatalf("node of another organisation removed")
+	}
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetAccount(opts, userAddr, memberOrg, uint8(permissions.RoleOrgAdmin))
+	}) {
+		t.Fatalf("organisation admin granted its own role")
+	}
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetAccount(opts, adminAddr, memberOrg, uint8(permissions.RoleNone))
+	}) {
+		t.Fatalf("organisation admin demoted a network admin")
+	}
+	if !transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetAccount(opts, userAddr, memberOrg, uint8(permissions.RoleTransact))
+	}) {
+		t.Fatalf("failed to grant role in own organisation")
+	}
+	if org, _ := binding.NodeOrg(nil, memberNode); common.Hash(org) != memberOrg || !permissions.NodePermitted(state(), permissionsAddr, memberNode) {
+		t.Fatalf("member node not permissioned: org %x", org)
+	}
+	if !permissions.AccountPermitted(state(), permissionsAddr, userAddr, false) || permissions.AccountPermitted(state(), permissionsAddr, userAddr, true) {
+		t.Fatalf("member account permissions mismatch: role %d", permissions.AccountRole(state(), permissionsAddr, userAddr))
+	}
+	// Suspending the organisation withdraws the permissions of its nodes and accounts
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetOrgStatus(opts, memberOrg, permissions.OrgSuspended)
+	}) {
+		t.Fatalf("organisation suspended by organisation admin")
+	}
+	if !transact(admin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.SetOrgStatus(opts, memberOrg, permissions.OrgSuspended)
+	}) {
+		t.Fatalf("failed to suspend organisation")
+	}
+	if permissions.NodePermitted(state(), permissionsAddr, memberNode) || permissions.AccountPermitted(state(), permissionsAddr, userAddr, false) {
+		t.Fatalf("suspended organisation still permitted")
+	}
+	if transact(orgAdmin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.RemoveNode(opts, memberNode)
+	}) {
+		t.Fatalf("node removed by admin of suspended organisation")
+	}
+	if !transact(admin, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return binding.RemoveNode(opts, memberNode)
+	}) {
+		t.Fatalf("failed to remove node")
+	}
+	if org, _ := binding.NodeOrg(nil, memberNode); org != ([32]byte{}) {
+		t.Fatalf("removed node still in organisation %x", org)
+	}
+}
+
+func TestDefaultRole(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		permissionsAddr: {
+			Code:    common.FromHex(contract.PermissionsCode),
+			Storage: permissions.GenesisStorage(networkOrg, nil, nil, permissions.RoleDeploy),
+			Balance: new(big.Int),
+		},
+	}, 180000000)
+	defer backend.Close()
+
+	statedb, _, _ := backend.Blockchain().State()
+	if !permissions.AccountPermitted(statedb, permissionsAddr, userAddr, true) {
+		t.Errorf("default role not granted")
+	}
+	if role := permissions.AccountRole(statedb, permissionsAddr, userAddr); role != permissions.RoleDeploy {
+		t.Errorf("role mismatch: have %d, want %d", role, permissions.RoleDeploy)
+	}
+}
+
+func TestUnknownRole(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		permissionsAddr: {
+			Code:    common.FromHex(contract.PermissionsCode),
+			Storage: permissions.GenesisStorage(networkOrg, nil, nil, permissions.RoleNetworkAdmin+1),
+			Balance: new(big.Int),
+		},
+	}, 180000000)
+	defer backend.Close()
+
+	statedb, _, _ := backend.Blockchain().State()
+	if role := permissions.AccountRole(statedb, permissionsAddr, userAddr); role != permissions.RoleNone {
+		t.Errorf("unknown role granted: have %d, want %d", role, permissions.RoleNone)
+	}
+}
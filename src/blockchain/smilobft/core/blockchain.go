@@ -136,9 +136,10 @@ type BlockChain struct {
 	chainConfig *params.ChainConfig // Chain & network configuration
 	cacheConfig *CacheConfig        // Cache configuration for pruning
 
-	db     ethdb.Database // Low level persistent database to store final content in
-	triegc *prque.Prque   // Priority queue mapping block numbers to tries to gc
-	gcproc time.Duration  // Accumulates canonical block processing for trie dumping
+	db          ethdb.Database // Low level persistent database to store final content in
+	triegc      *prque.Prque   // Priority queue mapping block numbers to tries to gc
+	vaultTriegc *prque.Prque   // Priority queue mapping block numbers to vault tries to gc
+	gcproc      time.Duration  // Accumulates canonical block processing for trie dumping
 
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
@@ -207,6 +208,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		cacheConfig:     cacheConfig,
 		db:              db,
 		triegc:          prque.New(nil),
+		vaultTriegc:     prque.New(nil),
 		stateCache:      state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit),
 		quit:            make(chan struct{}),
 		shouldPreserve:  shouldPreserve,
@@ -413,6 +415,9 @@ func (bc *BlockChain) SetHead(head uint64) error {
 				if _, err := state.New(newHeadBlock.Root(), bc.stateCache); err != nil {
 					// Rewound state missing, rolled back to before pivot, reset to genesis
 					newHeadBlock = bc.genesisBlock
+				} else if _, err := state.New(GetVaultStateRoot(bc.db, newHeadBlock.Root()), bc.vaultStateCache); err != nil {
+					// Rewound vault state pruned, reset to genesis
+					newHeadBlock = bc.genesisBlock
 				}
 			}
 			rawdb.WriteHeadBlockHash(db, newHeadBlock.Hash())
@@ -434,7 +439,18 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	}
 
 	// Rewind the header chain, deleting all block bodies until then
+	var vaultRoots []common.Hash
 	delFn := func(db ethdb.KeyValueWriter, hash common.Hash, num uint64) {
+		// Smilo
+		// The vault root mappings are keyed by state root which may be shared
+		// with a surviving block, delete them once the chain is rewound.
+		if header := bc.GetHeader(hash, num); header != nil {
+			vaultRoots = append(vaultRoots, header.Root)
+		}
+		DeleteVaultBlockBloom(db, num)
+		DeleteVaultReceipts(db, hash, num)
+		// Smilo
+
 		// Ignore the error here since light client won't hit this path
 		frozen, _ := bc.db.Ancients()
 		if num+1 <= frozen {
@@ -457,6 +473,10 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	}
 	bc.hc.SetHead(head, updateFn, delFn)
 
+	// Smilo
+	bc.deleteVaultStateRoots(vaultRoots)
+	// Smilo
+
 	// Clear out any stale content from the caches
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
@@ -467,6 +487,31 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	return bc.loadLastState()
 }
 
+// deleteVaultStateRoots deletes the vault root mappings of the given state
+// roots that no remaining canonical block references. Blocks that don't change
+// the state share the root of their parent, so the canonical chain is searched
+// from the head down until all the roots are found or the genesis is reached.
+func (bc *BlockChain) deleteVaultStateRoots(roots []common.Hash) {
+	orphans := make(map[common.Hash]struct{}, len(roots))
+	for _, root := range roots {
+		orphans[root] = struct{}{}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+	for number := bc.hc.CurrentHeader().Number.Uint64(); ; number-- {
+		if header := bc.GetHeaderByNumber(number); header != nil {
+			delete(orphans, header.Root)
+		}
+		if len(orphans) == 0 || number == 0 {
+			break
+		}
+	}
+	for root := range orphans {
+		DeleteVaultStateRoot(bc.db, root)
+	}
+}
+
 // FastSyncCommitHead sets the current head block to the one defined by the hash
 // irrelevant what the chain contents were prior.
 func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
@@ -564,6 +609,8 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, *state.StateDB,
 	if vaultStateDbErr != nil {
 		return nil, nil, vaultStateDbErr
 	}
+	publicStateDb.SetSmiloPayCurves(bc.chainConfig.SmiloPay)
+	vaultStateDb.SetSmiloPayCurves(bc.chainConfig.SmiloPay)
 
 	return publicStateDb, vaultStateDb, nil
 }
@@ -617,8 +664,11 @@ func (bc *BlockChain) repair(head **types.Block) error {
 	for {
 		// Abort if we've rewound to a head block that does have associated state
 		if _, err := state.New((*head).Root(), bc.stateCache); err == nil {
-			log.Info("Rewound blockchain to past state", "number", (*head).Number(), "hash", (*head).Hash())
-			return nil
+			// Smilo, the vault state is pruned separately and must be present too
+			if _, err := state.New(GetVaultStateRoot(bc.db, (*head).Root()), bc.vaultStateCache); err == nil {
+				log.Info("Rewound blockchain to past state", "number", (*head).Number(), "hash", (*head).Hash())
+				return nil
+			}
 		}
 		// Otherwise rewind one block and recheck state availability there
 		block := bc.GetBlock((*head).ParentHash(), (*head).NumberU64()-1)
@@ -822,6 +872,16 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetVaultReceiptsByHash retrieves the vault receipts of the vault transactions
+// in a given block.
+func (bc *BlockChain) GetVaultReceiptsByHash(hash common.Hash) types.Receipts {
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil {
+		return nil
+	}
+	return ReadVaultReceipts(bc.db, hash, *number)
+}
+
 // GetBlocksFromHash returns the block corresponding to hash and up to n-1 ancestors.
 // [deprecated by eth/62]
 func (bc *BlockChain) GetBlocksFromHash(hash common.Hash, n int) (blocks []*types.Block) {
@@ -878,6 +938,7 @@ func (bc *BlockChain) Stop() {
 	//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
 	if !bc.cacheConfig.TrieDirtyDisabled {
 		triedb := bc.stateCache.TrieDB()
+		vaultTriedb := bc.vaultStateCache.TrieDB()
 
 		for _, offset := range []uint64{0, 1, TriesInMemory - 1} {
 			if number := bc.CurrentBlock().NumberU64(); number > offset {
@@ -887,14 +948,23 @@ func (bc *BlockChain) Stop() {
 				if err := triedb.Commit(recent.Root(), true); err != nil {
 					log.Error("Failed to commit recent state trie", "err", err)
 				}
+				if err := vaultTriedb.Commit(GetVaultStateRoot(bc.db, recent.Root()), true); err != nil {
+					log.Error("Failed to commit recent vault state trie", "err", err)
+				}
 			}
 		}
 		for !bc.triegc.Empty() {
 			triedb.Dereference(bc.triegc.PopItem().(common.Hash))
 		}
+		for !bc.vaultTriegc.Empty() {
+			vaultTriedb.Dereference(bc.vaultTriegc.PopItem().(common.Hash))
+		}
 		if size, _ := triedb.Size(); size != 0 {
 			log.Error("Dangling trie nodes after full cleanup")
 		}
+		if size, _ := vaultTriedb.Size(); size != 0 {
+			log.Error("Dangling vault trie nodes after full cleanup")
+		}
 	}
 	log.Info("Blockchain manager stopped")
 }
@@ -1376,28 +1446,42 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	}
 	triedb := bc.stateCache.TrieDB()
 
-	// Explicit commit for vault state
+	// Smilo
+	// The vault state of the block follows the same caching and garbage
+	// collection rules as the public state it is mapped to.
+	var (
+		vaultTriedb = bc.vaultStateCache.TrieDB()
+		vaultRoot   common.Hash
+	)
+	if parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil {
+		vaultRoot = GetVaultStateRoot(bc.db, parent.Root)
+	}
 	if vaultState != nil {
-		vaultRoot, err := vaultState.Commit(bc.chainConfig.IsEIP158(block.Number()))
-		if err != nil {
-			return NonStatTy, err
-		}
-		vaultTriedb := bc.vaultStateCache.TrieDB()
-		if err := vaultTriedb.Commit(vaultRoot, false); err != nil {
+		if vaultRoot, err = vaultState.Commit(bc.chainConfig.IsEIP158(block.Number())); err != nil {
 			return NonStatTy, err
 		}
 	}
+	if err := WriteVaultStateRoot(bc.db, root, vaultRoot); err != nil {
+		return NonStatTy, err
+	}
+	// Smilo
 
 	// If we're running an archive node, always flush
 	if bc.cacheConfig.TrieDirtyDisabled {
 		if err := triedb.Commit(root, false); err != nil {
 			return NonStatTy, err
 		}
+		if err := vaultTriedb.Commit(vaultRoot, false); err != nil {
+			return NonStatTy, err
+		}
 	} else {
 		// Full but not archive node, do proper garbage collection
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		bc.triegc.Push(root, -int64(block.NumberU64()))
 
+		vaultTriedb.Reference(vaultRoot, common.Hash{})
+		bc.vaultTriegc.Push(vaultRoot, -int64(block.NumberU64()))
+
 		if current := block.NumberU64(); current > TriesInMemory {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
 			var (
@@ -1407,6 +1491,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 			if nodes > limit || imgs > 4*1024*1024 {
 				triedb.Cap(limit - ethdb.IdealBatchSize)
 			}
+			if nodes, imgs := vaultTriedb.Size(); nodes > limit || imgs > 4*1024*1024 {
+				vaultTriedb.Cap(limit - ethdb.IdealBatchSize)
+			}
 			// Find the next state trie we need to commit
 			chosen := current - TriesInMemory
 
@@ -1425,6 +1512,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 					}
 					// Flush an entire trie and restart the counters
 					triedb.Commit(header.Root, true)
+					vaultTriedb.Commit(GetVaultStateRoot(bc.db, header.Root), true)
 					lastWrite = chosen
 					bc.gcproc = 0
 				}
@@ -1438,6 +1526,14 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 				}
 				triedb.Dereference(root.(common.Hash))
 			}
+			for !bc.vaultTriegc.Empty() {
+				root, number := bc.vaultTriegc.Pop()
+				if uint64(-number) > chosen {
+					bc.vaultTriegc.Push(root, number)
+					break
+				}
+				vaultTriedb.Dereference(root.(common.Hash))
+			}
 		}
 	}
 
@@ -1723,6 +1819,8 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if err != nil {
 			return it.index, events, coalescedLogs, err
 		}
+		thisstate.SetSmiloPayCurves(bc.chainConfig.SmiloPay)
+		vaultState.SetSmiloPayCurves(bc.chainConfig.SmiloPay)
 		// END Smilo
 
 		// If we have a followup block, run that against the current state to pre-cache
@@ -1732,7 +1830,11 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if !bc.cacheConfig.TrieCleanNoPrefetch {
 			if followup, err := it.peek(); followup != nil && err == nil {
 				go func(start time.Time) {
-					throwaway, _ := state.New(parent.Root, bc.stateCache)
+					throwaway, err := state.New(parent.Root, bc.stateCache)
+					if err != nil {
+						return
+					}
+					throwaway.SetSmiloPayCurves(bc.chainConfig.SmiloPay)
 					bc.prefetcher.Prefetch(followup, throwaway, vaultState, bc.vmConfig, &followupInterrupt)
 
 					blockPrefetchExecuteTimer.Update(time.Since(start))
@@ -1746,6 +1848,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		// Process block using the parent state as reference point.
 		substart := time.Now()
 		receipts, vaultReceipts, logs, usedGas, err := bc.processor.Process(block, thisstate, vaultState, bc.vmConfig)
+		if err == ErrVaultUnavailable {
+			// Nothing is known about the block, halt the import without
+			// marking it bad so it is retried once the vault is reachable
+			log.Error("Halting block import, vault unavailable", "number", block.Number(), "hash", block.Hash())
+			atomic.StoreUint32(&followupInterrupt, 1)
+			return it.index, events, coalescedLogs, err
+		}
 		if err != nil {
 			log.Error("error Process block using the parent state as reference point, running c.processor.Process")
 			bc.reportBlock(block, receipts, err)
@@ -1782,20 +1891,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		blockValidationTimer.Update(time.Since(substart) - (thisstate.AccountHashes + thisstate.StorageHashes - triehash))
 
 		substart = time.Now()
-		// Smilo
-		// Write vault state changes to database
-		if vaultStateRoot, err = vaultState.Commit(bc.Config().IsEIP158(block.Number())); err != nil {
-			return it.index, events, coalescedLogs, err
-		}
-		if err := WriteVaultStateRoot(bc.db, block.Root(), vaultStateRoot); err != nil {
-			return it.index, events, coalescedLogs, err
-		}
-		allReceipts := mergeReceipts(receipts, vaultReceipts)
-		// Smilo
 
 		// Write the block to the chain and get the status.
-
-		status, err := bc.WriteBlockWithState(block, allReceipts, thisstate, vaultState)
+		status, err := bc.WriteBlockWithState(block, receipts, thisstate, vaultState)
 		if err != nil {
 			atomic.StoreUint32(&followupInterrupt, 1)
 			return it.index, events, coalescedLogs, err
@@ -1812,6 +1910,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if err := WriteVaultBlockBloom(bc.db, block.NumberU64(), vaultReceipts); err != nil {
 			return it.index, events, coalescedLogs, err
 		}
+		if err := WriteVaultReceipts(bc.db, block.Hash(), block.NumberU64(), vaultReceipts); err != nil {
+			return it.index, events, coalescedLogs, err
+		}
 		switch status {
 		case CanonStatTy:
 			log.Debug("Inserted new block", "number", block.Number(), "hash", block.Hash(),
@@ -2365,27 +2466,6 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
-// Given a slice of public receipts and an overlapping (smaller) slice of
-// vault receipts, return a new slice where the default for each location is
-// the public receipt but we take the vault receipt in each place we have
-// one.
-func mergeReceipts(pub, priv types.Receipts) types.Receipts {
-	m := make(map[common.Hash]*types.Receipt)
-	for _, receipt := range pub {
-		m[receipt.TxHash] = receipt
-	}
-	for _, receipt := range priv {
-		m[receipt.TxHash] = receipt
-	}
-
-	ret := make(types.Receipts, 0, len(pub))
-	for _, pubReceipt := range pub {
-		ret = append(ret, m[pubReceipt.TxHash])
-	}
-
-	return ret
-}
-
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
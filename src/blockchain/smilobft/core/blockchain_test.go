@@ -1310,6 +1310,45 @@ func TestCanonicalBlockRetrieval(t *testing.T) {
 	pend.Wait()
 }
 
+// Tests that blocks including transactions of accounts the permissions contract
+// doesn't allow at the parent block are rejected.
+func TestUnpermittedTransactionBlock(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.Address{0x0f, 0x02}
+		gspec    = &Genesis{
+			Config: &params.ChainConfig{ChainID: big.NewInt(10), HomesteadBlock: new(big.Int)},
+			Alloc: GenesisAlloc{
+				address:  {Balance: big.NewInt(1000000000)},
+				contract: {Code: []byte{0x00}, Balance: new(big.Int)},
+			},
+		}
+		gendb   = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(gendb)
+	)
+	// Generate the block on a chain without permissions
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), gendb, 1, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{}, new(big.Int), 21000, new(big.Int), nil), types.HomesteadSigner{}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	// Import it on the same chain governed by the permissions contract
+	config := *gspec.Config
+	config.PermissionsContract = &contract
+
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+	blockchain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != ErrAccountNotPermitted {
+		t.Fatalf("block insert error mismatch: have %v, want %v", err, ErrAccountNotPermitted)
+	}
+}
+
 func TestEIP155Transition(t *testing.T) {
 	// Configure and generate a sample block chain
 	var (
@@ -1573,6 +1612,155 @@ func TestTrieForkGC(t *testing.T) {
 	}
 }
 
+// Tests that the vault tries are garbage collected together with the public
+// ones and that no vault trie is left in memory after dereferencing the recent
+// ones.
+func TestVaultTrieGC(t *testing.T) {
+	engine := ethash.NewFaker()
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := new(Genesis).MustCommit(db)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 2*TriesInMemory, func(i int, b *BlockGen) { b.SetCoinbase(common.Address{1}) })
+
+	diskdb := rawdb.NewMemoryDatabase()
+	new(Genesis).MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Write the blocks with a distinct vault state each
+	vaultRoots := make([]common.Hash, len(blocks))
+	for i, block := range blocks {
+		parent := chain.GetBlockByHash(block.ParentHash())
+		publicState, vaultState, err := chain.StateAt(parent.Root())
+		if err != nil {
+			t.Fatalf("block %d: failed to open parent state: %v", i, err)
+		}
+		receipts, _, _, _, err := chain.processor.Process(block, publicState, vaultState, vm.Config{})
+		if err != nil {
+			t.Fatalf("block %d: failed to process: %v", i, err)
+		}
+		vaultState.SetNonce(common.Address{1}, uint64(i+1))
+		vaultState.SetState(common.Address{1}, common.Hash{}, common.BigToHash(big.NewInt(int64(i+1))))
+		if _, err := chain.WriteBlockWithState(block, receipts, publicState, vaultState); err != nil {
+			t.Fatalf("block %d: failed to write: %v", i, err)
+		}
+		vaultRoots[i] = GetVaultStateRoot(diskdb, block.Root())
+		if vaultRoots[i] == (common.Hash{}) {
+			t.Fatalf("block %d: vault root not mapped", i)
+		}
+	}
+	// Old vault tries must have been released, recent ones still alive
+	if _, err := state.New(vaultRoots[len(blocks)-1], chain.vaultStateCache); err != nil {
+		t.Fatalf("head vault state missing: %v", err)
+	}
+	if _, err := state.New(vaultRoots[0], chain.vaultStateCache); err == nil {
+		t.Fatalf("stale vault state not garbage collected")
+	}
+	for i := 0; i < TriesInMemory; i++ {
+		chain.vaultStateCache.TrieDB().Dereference(vaultRoots[len(blocks)-1-i])
+	}
+	if len(chain.vaultStateCache.TrieDB().Nodes()) > 0 {
+		t.Fatalf("stale vault tries still alive after garbage collection")
+	}
+}
+
+// Tests that rewinding the chain drops the vault root mappings and blooms of
+// the removed blocks.
+func TestSetHeadVaultCleanup(t *testing.T) {
+	engine := ethash.NewFaker()
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := new(Genesis).MustCommit(db)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 8, func(i int, b *BlockGen) { b.SetCoinbase(common.Address{1}) })
+
+	diskdb := rawdb.NewMemoryDatabase()
+	new(Genesis).MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	for _, block := range blocks {
+		if has, _ := diskdb.Has(append(vaultRootPrefix, block.Root().Bytes()...)); !has {
+			t.Fatalf("block %d: vault root not mapped", block.NumberU64())
+		}
+	}
+	if err := chain.SetHead(4); err != nil {
+		t.Fatalf("failed to rewind: %v", err)
+	}
+	for _, block := range blocks {
+		hasRoot, _ := diskdb.Has(append(vaultRootPrefix, block.Root().Bytes()...))
+		hasBloom, _ := diskdb.Has(append(vaultBloomPrefix, encodeBlockNumber(block.NumberU64())...))
+		if removed := block.NumberU64() > 4; removed == hasRoot || removed == hasBloom {
+			t.Errorf("block %d: vault root mapped %v, bloom %v after rewind", block.NumberU64(), hasRoot, hasBloom)
+		}
+	}
+}
+
+// sharedRootEngine is an ethash faker only rewarding blocks with a coinbase, so
+// that the others share the state root of their parent.
+type sharedRootEngine struct {
+	consensus.Engine
+}
+
+func (e sharedRootEngine) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	if header.Coinbase != (common.Address{}) {
+		ethash.AccumulateRewards(chain.Config(), state, header, uncles)
+	}
+	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	return types.NewBlock(header, txs, uncles, receipts), nil
+}
+
+// Tests that rewinding the chain keeps the vault root mappings of removed blocks
+// sharing their state root with a surviving block.
+func TestSetHeadVaultSharedRoots(t *testing.T) {
+	engine := sharedRootEngine{ethash.NewFaker()}
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := new(Genesis).MustCommit(db)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 8, func(i int, b *BlockGen) {
+		// Blocks 4-6 share the root of block 3, block 8 the one of block 7
+		if i < 3 || i == 6 {
+			b.SetCoinbase(common.Address{1})
+		} else {
+			b.SetCoinbase(common.Address{})
+		}
+	})
+	if blocks[5].Root() != blocks[2].Root() || blocks[7].Root() != blocks[6].Root() {
+		t.Fatalf("blocks without coinbase don't share the parent root")
+	}
+	diskdb := rawdb.NewMemoryDatabase()
+	new(Genesis).MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if err := chain.SetHead(4); err != nil {
+		t.Fatalf("failed to rewind: %v", err)
+	}
+	for _, block := range blocks {
+		has, _ := diskdb.Has(append(vaultRootPrefix, block.Root().Bytes()...))
+		if orphaned := block.NumberU64() > 6; orphaned == has {
+			t.Errorf("block %d: vault root mapped %v after rewind", block.NumberU64(), has)
+		}
+	}
+}
+
 // Tests that doing large reorgs works even if the state associated with the
 // forking point is not available any more.
 func TestLargeReorgTrieGC(t *testing.T) {
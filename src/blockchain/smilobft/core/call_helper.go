@@ -54,10 +54,6 @@ func (cg *callHelper) MakeCall(vault bool, key *ecdsa.PrivateKey, to common.Addr
 		return err
 	}
 	defer func() { cg.nonces[from]++ }()
-	msg, err := tx.AsMessage(signer)
-	if err != nil {
-		return err
-	}
 
 	publicState, vaultState := cg.PublicState, cg.VaultState
 	if !vault {
@@ -65,6 +61,11 @@ func (cg *callHelper) MakeCall(vault bool, key *ecdsa.PrivateKey, to common.Addr
 	} else {
 		tx.SetVault()
 	}
+	// The message must be derived after flagging the transaction as vault
+	msg, err := tx.AsMessage(signer)
+	if err != nil {
+		return err
+	}
 
 	bc, _ := NewBlockChain(cg.db, nil, params.SmiloTestChainConfig, ethash.NewFaker(), vm.Config{}, nil)
 	context := NewEVMContext(msg, &cg.header, bc, &from)
@@ -35,4 +35,14 @@ var (
 
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
+
+	// ErrVaultUnavailable is returned if the payload of a vault transaction could
+	// not be retrieved. Processing must stop instead of treating the node as a
+	// non-participant, which would make its vault state diverge, and the block
+	// must not be marked bad as it is imported again once the vault is back.
+	ErrVaultUnavailable = errors.New("vault unavailable")
+
+	// ErrVaultStateValidation is the execution error of a state validation vault
+	// transaction whose affected contracts differ from the sender's simulation.
+	ErrVaultStateValidation = errors.New("vault state validation failed")
 )
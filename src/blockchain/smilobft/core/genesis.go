@@ -45,6 +45,10 @@ import (
 
 var errGenesisNoConfig = errors.New("genesis has no chain configuration")
 
+// registryMinStakeSlot is the storage slot the fullnode registry keeps the
+// stake required from new fullnodes in, see contracts/fullnodes.
+var registryMinStakeSlot = common.BigToHash(big.NewInt(1))
+
 // Genesis specifies the header fields, state of a genesis block. It also defines hard
 // fork switch-over blocks through the chain configuration.
 type Genesis struct {
@@ -140,6 +144,24 @@ func (e *GenesisMismatchError) Error() string {
 	return fmt.Sprintf("database contains incompatible genesis (have %x, new %x)", e.Stored, e.New)
 }
 
+// validateFullnodeRegistry checks that a fullnode registry governing the
+// fullnode set is allocated with a positive minimum stake. There is no default:
+// a registry without one would let anyone register as a fullnode for free.
+func (g *Genesis) validateFullnodeRegistry() error {
+	registry := g.Config.Sport.FullnodeRegistry()
+	if registry == (common.Address{}) {
+		return nil
+	}
+	account, ok := g.Alloc[registry]
+	if !ok {
+		return fmt.Errorf("fullnode registry %x not allocated in genesis", registry)
+	}
+	if minStake := account.Storage[registryMinStakeSlot].Big(); minStake.Sign() <= 0 {
+		return fmt.Errorf("fullnode registry %x has no minimum stake", registry)
+	}
+	return nil
+}
+
 // SetupGenesisBlock writes or updates the genesis block in db.
 // The block that will be used is:
 //
@@ -157,6 +179,14 @@ func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig
 	if genesis != nil && genesis.Config == nil {
 		return params.AllEthashProtocolChanges, common.Hash{}, errGenesisNoConfig
 	}
+	if genesis != nil {
+		if err := genesis.Config.Sport.Validate(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		if err := genesis.validateFullnodeRegistry(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+	}
 	// Just commit the new block if there is no stored genesis block.
 	stored := rawdb.ReadCanonicalHash(db, 0)
 	if (stored == common.Hash{}) {
@@ -282,6 +312,9 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 		db = rawdb.NewMemoryDatabase()
 	}
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	if g.Config != nil {
+		statedb.SetSmiloPayCurves(g.Config.SmiloPay)
+	}
 	for addr, account := range g.Alloc {
 		statedb.AddBalance(addr, account.Balance, big.NewInt(0))
 		statedb.SetCode(addr, account.Code)
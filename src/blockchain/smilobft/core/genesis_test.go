@@ -174,3 +174,33 @@ func TestSetupGenesis(t *testing.T) {
 		}
 	}
 }
+
+func TestSetupGenesisSportRewards(t *testing.T) {
+	rewards := params.DefaultSportRewards(big.NewInt(0), common.Address{})
+	rewards.CommunityShare = 25
+
+	genesis := &Genesis{Config: &params.ChainConfig{Sport: &params.SportConfig{Rewards: []*params.SportRewards{rewards}}}}
+	if _, _, err := SetupGenesisBlock(rawdb.NewMemoryDatabase(), genesis); err == nil {
+		t.Fatal("community share without community address accepted")
+	}
+	rewards.CommunityAddress = common.Address{0xc}
+	genesis.Config.CustomTransactionSizeLimit = 32
+	if _, _, err := SetupGenesisBlock(rawdb.NewMemoryDatabase(), genesis); err != nil {
+		t.Fatalf("valid reward schedule rejected: %v", err)
+	}
+}
+
+func TestSetupGenesisFullnodeRegistry(t *testing.T) {
+	registry := common.Address{0xf}
+	genesis := &Genesis{
+		Config: &params.ChainConfig{Sport: &params.SportConfig{FullnodeContract: &registry}, CustomTransactionSizeLimit: 32},
+		Alloc:  GenesisAlloc{registry: {Balance: new(big.Int), Storage: map[common.Hash]common.Hash{}}},
+	}
+	if _, _, err := SetupGenesisBlock(rawdb.NewMemoryDatabase(), genesis); err == nil {
+		t.Fatal("fullnode registry without minimum stake accepted")
+	}
+	genesis.Alloc[registry].Storage[registryMinStakeSlot] = common.BigToHash(big.NewInt(1))
+	if _, _, err := SetupGenesisBlock(rawdb.NewMemoryDatabase(), genesis); err != nil {
+		t.Fatalf("fullnode registry with minimum stake rejected: %v", err)
+	}
+}
@@ -48,6 +48,10 @@ const (
 	numberCacheLimit = 2048
 )
 
+// errNoHeaderChainState is returned if the state is requested from a chain of
+// headers only.
+var errNoHeaderChainState = errors.New("header chain has no state")
+
 // HeaderChain implements the basic block header chain logic that is shared by
 // core.BlockChain and light.LightChain. It is not usable in itself, only as
 // a part of either structure.
@@ -543,7 +547,8 @@ func (hc *HeaderChain) GetBlock(hash common.Hash, number uint64) *types.Block {
 	return nil
 }
 
-// State returns the HeaderChain StateDB instances (pub,priv) and or err if fails
+// State implements consensus.ChainReader, and returns an error as a header chain,
+// like the one of light clients, does not have state available for retrieval.
 func (hc *HeaderChain) State() (*state.StateDB, *state.StateDB, error) {
-	return hc.State()
+	return nil, nil, errNoHeaderChainState
 }
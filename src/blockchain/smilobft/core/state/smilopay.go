@@ -18,32 +18,112 @@ package state
 
 import (
 	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/params"
 )
 
-func CalculateSmiloPay(prevBlock, newBlock, prevSmiloPay, balance *big.Int) *big.Int {
-	//if balance.Cmp(big.NewInt(1e+16)) < 0 {
-	//	log.Debug("CalculateSmiloPay, Balance:", balance.Int64(), ", Min required: ", big.NewInt(1e+16).Int64(), ", returned: ",common.Big0)
-	//	return common.Big0
-	//}
+var (
+	weiPerSmilo = big.NewInt(1e18)
+
+	// sqrtScale is the square of the fixed point precision √balance is computed
+	// with, so that √(balance * sqrtScale) carries 18 decimals.
+	sqrtScale = new(big.Int).Mul(weiPerSmilo, weiPerSmilo)
+)
 
+// CalculateSmiloPay returns the SmiloPay available at newBlock to an account that
+// held prevSmiloPay at prevBlock. Curves are evaluated with integer arithmetic;
+// a nil curve selects the legacy floating point computation, which chains keep
+// using until their first curve is scheduled.
+func CalculateSmiloPay(curve *params.SmiloPayCurve, prevBlock, newBlock, prevSmiloPay, balance *big.Int) *big.Int {
 	//if block did not change, return prevSmiloPay
 	if prevBlock.Cmp(newBlock) >= 0 {
 		return prevSmiloPay
 	}
+	if curve == nil {
+		return legacyCalculateSmiloPay(prevBlock, newBlock, prevSmiloPay, balance)
+	}
+	maxSmiloPay := MaxSmiloPay(curve, balance)
 
-	maxSmiloPay, balanceSmilo := MaxSmiloPay(balance)
+	smiloPay := new(big.Int).Sub(newBlock, prevBlock)
+	smiloPay.Mul(smiloPay, smiloPaySpeed(curve, balance))
+	smiloPay.Add(smiloPay, prevSmiloPay)
 
-	blockGap := new(big.Int).Sub(newBlock, prevBlock)
+	if smiloPay.Cmp(maxSmiloPay) > 0 {
+		return maxSmiloPay
+	}
+	return smiloPay
+}
 
-	// smiloSpeed := (0.000001 + (√balance / 750000)) * 0.5 * 1e+18 (To avoid overflow, its coded with big.Float)
-	sqrt := new(big.Float).Sqrt(balanceSmilo)
-	sqrtDiv := new(big.Float).Quo(sqrt, big.NewFloat(750000))
-	sqrtAdd := new(big.Float).Add(sqrtDiv, big.NewFloat(0.000001))
-	smiloSpeedMul := new(big.Float).Mul(sqrtAdd, big.NewFloat(0.5))
-	smiloSpeedBig := new(big.Float).Mul(smiloSpeedMul, big.NewFloat(1e+18))
+// MaxSmiloPay returns the maximum SmiloPay an account holding balance can
+// regenerate to under the given curve.
+func MaxSmiloPay(curve *params.SmiloPayCurve, balance *big.Int) *big.Int {
+	if curve == nil {
+		maxSmiloPay, _ := legacyMaxSmiloPay(balance)
+		return maxSmiloPay
+	}
+	return evalCurve(curve.MaxBase, curve.MaxFactor, curve.MaxDivisor, balance)
+}
+
+// SmiloPayBlocksUntil returns the number of blocks an account holding balance
+// and smiloPay has to wait until amount of SmiloPay is available. It returns
+// false if amount exceeds the maximum SmiloPay the balance can ever hold.
+func SmiloPayBlocksUntil(curve *params.SmiloPayCurve, smiloPay, balance, amount *big.Int) (uint64, bool) {
+	if smiloPay.Cmp(amount) >= 0 {
+		return 0, true
+	}
+	if MaxSmiloPay(curve, balance).Cmp(amount) < 0 {
+		return 0, false
+	}
+	speed := smiloPaySpeed(curve, balance)
+	if speed.Sign() == 0 {
+		return 0, false
+	}
+	// Estimate the gap from the regeneration speed, then walk it forward so
+	// rounding in CalculateSmiloPay can never make the estimate fall short.
+	gap := new(big.Int).Sub(amount, smiloPay)
+	gap.Div(gap, speed)
+	if gap.Sign() == 0 {
+		gap.SetUint64(1)
+	}
+	for CalculateSmiloPay(curve, common.Big0, gap, smiloPay, balance).Cmp(amount) < 0 {
+		gap.Add(gap, common.Big1)
+	}
+	if !gap.IsUint64() {
+		return 0, false
+	}
+	return gap.Uint64(), true
+}
+
+// smiloPaySpeed returns the amount of SmiloPay regenerated per block for an
+// account holding balance under the given curve.
+func smiloPaySpeed(curve *params.SmiloPayCurve, balance *big.Int) *big.Int {
+	if curve == nil {
+		_, balanceSmilo := legacyMaxSmiloPay(balance)
+		return floatToBigInt(legacySmiloPaySpeed(balanceSmilo), big.NewInt(1))
+	}
+	return evalCurve(curve.SpeedBase, curve.SpeedFactor, curve.SpeedDivisor, balance)
+}
+
+// evalCurve returns base + √balance * factor / divisor, with the balance counted
+// in whole Smilo and its square root taken with 18 decimals of precision.
+func evalCurve(base, factor *big.Int, divisor uint64, balance *big.Int) *big.Int {
+	sqrt := new(big.Int).Div(balance, weiPerSmilo)
+	sqrt.Sqrt(sqrt.Mul(sqrt, sqrtScale))
+
+	value := sqrt.Mul(sqrt, factor)
+	value.Div(value, new(big.Int).Mul(new(big.Int).SetUint64(divisor), weiPerSmilo))
+	return value.Add(value, base)
+}
+
+func legacyCalculateSmiloPay(prevBlock, newBlock, prevSmiloPay, balance *big.Int) *big.Int {
+	maxSmiloPay, balanceSmilo := legacyMaxSmiloPay(balance)
+
+	blockGap := new(big.Int).Sub(newBlock, prevBlock)
 
 	blockGapFloat := new(big.Float).SetInt(blockGap)
-	smiloPayResult := new(big.Float).Mul(blockGapFloat, smiloSpeedBig)
+	smiloPayResult := new(big.Float).Mul(blockGapFloat, legacySmiloPaySpeed(balanceSmilo))
 
 	prevSmiloPayFloat := new(big.Float).SetInt(prevSmiloPay)
 	smiloPayFloat := new(big.Float).Add(prevSmiloPayFloat, smiloPayResult)
@@ -55,7 +135,16 @@ func CalculateSmiloPay(prevBlock, newBlock, prevSmiloPay, balance *big.Int) *big
 	return floatToBigInt(smiloPayFloat, big.NewInt(1))
 }
 
-func MaxSmiloPay(balance *big.Int) (maxSmiloPayReturn *big.Int, balanceSmilo *big.Float) {
+func legacySmiloPaySpeed(balanceSmilo *big.Float) *big.Float {
+	// smiloSpeed := (0.000001 + (√balance / 750000)) * 0.5 * 1e+18 (To avoid overflow, its coded with big.Float)
+	sqrt := new(big.Float).Sqrt(balanceSmilo)
+	sqrtDiv := new(big.Float).Quo(sqrt, big.NewFloat(750000))
+	sqrtAdd := new(big.Float).Add(sqrtDiv, big.NewFloat(0.000001))
+	smiloSpeedMul := new(big.Float).Mul(sqrtAdd, big.NewFloat(0.5))
+	return new(big.Float).Mul(smiloSpeedMul, big.NewFloat(1e+18))
+}
+
+func legacyMaxSmiloPay(balance *big.Int) (maxSmiloPayReturn *big.Int, balanceSmilo *big.Float) {
 	balanceDecimals := new(big.Int).Div(balance, big.NewInt(1e+18))
 	balanceSmilo = new(big.Float).SetInt(balanceDecimals)
 
@@ -22,8 +22,12 @@ import (
 
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/orinocopay/go-etherutils"
 	"github.com/stretchr/testify/require"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/params"
 )
 
 func TestSmiloPay(t *testing.T) {
@@ -44,7 +48,7 @@ func TestSmiloPay(t *testing.T) {
 	prevsmiloPay := big.NewInt(1000000000000000)
 	for i := 0; i < 10; i++ {
 		newbalance, _ := etherutils.StringToWei(fmt.Sprintf("%d0 ether", i))
-		smiloPay := CalculateSmiloPay(prevBlock, newBlock, prevsmiloPay, newbalance)
+		smiloPay := CalculateSmiloPay(nil, prevBlock, newBlock, prevsmiloPay, newbalance)
 		require.Equal(t, resultSmiloPay[i], smiloPay)
 	}
 }
@@ -65,7 +69,7 @@ func TestSmiloPayMax(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		newbalance, _ := etherutils.StringToWei(fmt.Sprintf("%d ether", i))
 
-		maxSmiloPay, _ := MaxSmiloPay(newbalance)
+		maxSmiloPay := MaxSmiloPay(nil, newbalance)
 		require.NotEmpty(t, maxSmiloPay)
 		require.Equal(t, resultSmiloPay[i], maxSmiloPay) // Result in WEI
 
@@ -74,7 +78,7 @@ func TestSmiloPayMax(t *testing.T) {
 
 func TestSmiloPayMaxHundredTen(t *testing.T) {
 	balance, _ := etherutils.StringToWei("110 ether")
-	maxSmiloPay, _ := MaxSmiloPay(balance)
+	maxSmiloPay := MaxSmiloPay(nil, balance)
 	require.NotEmpty(t, maxSmiloPay)
 	require.Equal(t, big.NewInt(6048808848170151), maxSmiloPay)
 }
@@ -84,7 +88,7 @@ func TestSmiloPaySpeedLarge(t *testing.T) {
 	newBlock := big.NewInt(110)
 	prevsmiloPay := big.NewInt(0)
 	balance, _ := etherutils.StringToWei("110 ether")
-	smiloPay := CalculateSmiloPay(prevBlock, newBlock, prevsmiloPay, balance)
+	smiloPay := CalculateSmiloPay(nil, prevBlock, newBlock, prevsmiloPay, balance)
 	require.Equal(t, big.NewInt(74920589878010), smiloPay)
 }
 
@@ -93,10 +97,97 @@ func TestSmiloPaySpeedVeryLarge(t *testing.T) {
 	newBlock := big.NewInt(110)
 	prevsmiloPay := big.NewInt(0)
 	balance, _ := etherutils.StringToWei("100000000 ether")
-	smiloPay := CalculateSmiloPay(prevBlock, newBlock, prevsmiloPay, balance)
+	smiloPay := CalculateSmiloPay(nil, prevBlock, newBlock, prevsmiloPay, balance)
 	require.Equal(t, big.NewInt(66671666666), new(big.Int).Div(smiloPay, big.NewInt(1e6)))
 }
 
+func TestSmiloPayBlocksUntil(t *testing.T) {
+	balance, _ := etherutils.StringToWei("110 ether")
+	smiloPay := big.NewInt(1000000000000000)
+
+	for _, curve := range []*params.SmiloPayCurve{nil, params.DefaultSmiloPayCurve(common.Big0)} {
+		maxSmiloPay := MaxSmiloPay(curve, balance)
+
+		blocks, ok := SmiloPayBlocksUntil(curve, smiloPay, balance, smiloPay)
+		require.True(t, ok)
+		require.Equal(t, uint64(0), blocks)
+
+		amount := big.NewInt(2000000000000000)
+		blocks, ok = SmiloPayBlocksUntil(curve, smiloPay, balance, amount)
+		require.True(t, ok)
+		require.True(t, CalculateSmiloPay(curve, big.NewInt(0), new(big.Int).SetUint64(blocks), smiloPay, balance).Cmp(amount) >= 0)
+		require.True(t, CalculateSmiloPay(curve, big.NewInt(0), new(big.Int).SetUint64(blocks-1), smiloPay, balance).Cmp(amount) < 0)
+
+		blocks, ok = SmiloPayBlocksUntil(curve, smiloPay, balance, maxSmiloPay)
+		require.True(t, ok)
+		require.Equal(t, 0, CalculateSmiloPay(curve, big.NewInt(0), new(big.Int).SetUint64(blocks), smiloPay, balance).Cmp(maxSmiloPay))
+
+		_, ok = SmiloPayBlocksUntil(curve, smiloPay, balance, new(big.Int).Add(maxSmiloPay, big.NewInt(1)))
+		require.False(t, ok)
+	}
+}
+
+func TestSmiloPayCurve(t *testing.T) {
+	curve := params.DefaultSmiloPayCurve(common.Big0)
+
+	// The default curve follows the legacy values, up to float rounding
+	resultSmiloPay := []*big.Int{
+		big.NewInt(1005000000000000),
+		big.NewInt(1026081851067780),
+		big.NewInt(1034814239699990),
+		big.NewInt(1041514837167010),
+		big.NewInt(1047163702135570),
+		big.NewInt(1052140452079100),
+		big.NewInt(1056639777949430),
+		big.NewInt(1060777335102270),
+		big.NewInt(1064628479399990),
+		big.NewInt(1068245553203360),
+	}
+	for i := 0; i < 10; i++ {
+		balance, _ := etherutils.StringToWei(fmt.Sprintf("%d0 ether", i))
+		smiloPay := CalculateSmiloPay(curve, big.NewInt(100), big.NewInt(110), big.NewInt(1000000000000000), balance)
+		require.Equal(t, resultSmiloPay[i], smiloPay)
+	}
+	balance, _ := etherutils.StringToWei("110 ether")
+	require.Equal(t, big.NewInt(6048808848170151), MaxSmiloPay(curve, balance))
+
+	// Tuned curves change both speed and cap
+	tuned := &params.SmiloPayCurve{
+		Block:        common.Big0,
+		SpeedBase:    big.NewInt(1000),
+		SpeedFactor:  big.NewInt(100),
+		SpeedDivisor: 10,
+		MaxBase:      big.NewInt(1e6),
+		MaxFactor:    big.NewInt(1e6),
+		MaxDivisor:   1,
+	}
+	balance, _ = etherutils.StringToWei("4 ether")
+	require.Equal(t, big.NewInt(3e6), MaxSmiloPay(tuned, balance))
+	require.Equal(t, big.NewInt(1020*5), CalculateSmiloPay(tuned, big.NewInt(0), big.NewInt(5), common.Big0, balance))
+	require.Equal(t, big.NewInt(3e6), CalculateSmiloPay(tuned, big.NewInt(0), big.NewInt(1e6), common.Big0, balance))
+}
+
+func TestSmiloPayCurveSchedule(t *testing.T) {
+	addr := common.Address{1}
+	balance, _ := etherutils.StringToWei("100 ether")
+
+	statedb, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.SetBalance(addr, balance, common.Big0)
+	legacy := statedb.GetSmiloPay(addr, big.NewInt(9))
+
+	statedb.SetSmiloPayCurves(params.SmiloPayCurves{{
+		Block:        big.NewInt(10),
+		SpeedBase:    big.NewInt(1),
+		SpeedFactor:  common.Big0,
+		SpeedDivisor: 1,
+		MaxBase:      big.NewInt(1e18),
+		MaxFactor:    common.Big0,
+		MaxDivisor:   1,
+	}})
+	require.Equal(t, legacy, statedb.Copy().GetSmiloPay(addr, big.NewInt(9)))
+	require.Equal(t, big.NewInt(11), statedb.Copy().GetSmiloPay(addr, big.NewInt(11)))
+}
+
 func TestSmiloPayCalculations(t *testing.T) {
 
 	smallTxPrice, _ := etherutils.StringToWei("0.000021 ether")
@@ -295,7 +386,7 @@ func TestSmiloPayCalculations(t *testing.T) {
 			newbalance, _ := etherutils.StringToWei(fmt.Sprintf("%d ether", test.balance))
 			t.Log("Balance Didux : ", test.balance)
 
-			maxSmiloPay, _ := MaxSmiloPay(newbalance)
+			maxSmiloPay := MaxSmiloPay(nil, newbalance)
 			require.NotEmpty(t, maxSmiloPay)
 
 			maxSmiloPayStr := etherutils.WeiToString(maxSmiloPay, true)
@@ -317,7 +408,7 @@ func TestSmiloPayCalculations(t *testing.T) {
 			prevBlock := big.NewInt(100)
 			newBlock := big.NewInt(101)
 			prevsmiloPay := big.NewInt(0)
-			smiloPay := CalculateSmiloPay(prevBlock, newBlock, prevsmiloPay, newbalance)
+			smiloPay := CalculateSmiloPay(nil, prevBlock, newBlock, prevsmiloPay, newbalance)
 
 			recoverySpeed := etherutils.WeiToString(smiloPay, true)
 			t.Log("RecoverySpeed: ", recoverySpeed)
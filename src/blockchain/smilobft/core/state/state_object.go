@@ -396,7 +396,7 @@ func (s *stateObject) SetSmiloPay(amount *big.Int) {
 func (s *stateObject) UpdateSmiloPay(blockNumber *big.Int) {
 	prevsmiloPay := s.data.SmiloPay
 	prevblock := s.data.BlockNumber
-	smiloPay := CalculateSmiloPay(prevblock, blockNumber, prevsmiloPay, s.data.Balance)
+	smiloPay := CalculateSmiloPay(s.db.smiloPayCurves.At(blockNumber), prevblock, blockNumber, prevsmiloPay, s.data.Balance)
 	s.db.journal.append(blockChange{
 		account:      &s.address,
 		prevSmiloPay: prevsmiloPay,
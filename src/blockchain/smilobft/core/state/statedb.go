@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 
 	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/params"
 
 	"go-didux/src/blockchain/smilobft/trie"
 
@@ -98,6 +99,9 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// SmiloPay curve schedule of the chain the state belongs to
+	smiloPayCurves params.SmiloPayCurves
+
 	// Measurements gathered during execution for debugging purposes
 	AccountReads   time.Duration
 	AccountHashes  time.Duration
@@ -233,11 +237,17 @@ func (self *StateDB) GetBalance(addr common.Address) *big.Int {
 	return common.Big0
 }
 
+// SetSmiloPayCurves sets the SmiloPay curve schedule SmiloPay regenerates with.
+// Without a schedule the legacy curve is used.
+func (self *StateDB) SetSmiloPayCurves(curves params.SmiloPayCurves) {
+	self.smiloPayCurves = curves
+}
+
 func (self *StateDB) GetSmiloPay(addr common.Address, blockNumber *big.Int) *big.Int {
 	stateObject := self.getStateObject(addr)
 	ret := common.Big0
 	if stateObject != nil {
-		ret = CalculateSmiloPay(stateObject.BlockNumber(), blockNumber, stateObject.SmiloPay(), stateObject.Balance())
+		ret = CalculateSmiloPay(self.smiloPayCurves.At(blockNumber), stateObject.BlockNumber(), blockNumber, stateObject.SmiloPay(), stateObject.Balance())
 	}
 	//fmt.Println("Available SmiloPay: ", ret.Int64())
 	return ret
@@ -622,6 +632,7 @@ func (self *StateDB) Copy() *StateDB {
 		logSize:           self.logSize,
 		preimages:         make(map[common.Hash][]byte, len(self.preimages)),
 		journal:           newJournal(),
+		smiloPayCurves:    self.smiloPayCurves,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.journal.dirties {
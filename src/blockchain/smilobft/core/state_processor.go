@@ -28,6 +28,7 @@ import (
 
 	"go-didux/src/blockchain/smilobft/consensus"
 	"go-didux/src/blockchain/smilobft/consensus/misc"
+	"go-didux/src/blockchain/smilobft/contracts/permissions"
 )
 
 // StateProcessor is a basic Processor, which takes care of transitioning
@@ -66,16 +67,25 @@ func (p *StateProcessor) Process(block *types.Block, statedb, vaultState *state.
 
 		vaultReceipts types.Receipts
 	)
+	// Blocks without transactions are still rewarded, regenerate with the chain curves
+	statedb.SetSmiloPayCurves(p.config.SmiloPay)
+	vaultState.SetSmiloPayCurves(p.config.SmiloPay)
+
 	// Mutate the the block and state according to any hard-fork specs
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb, block.Number())
 	}
+	// Open the state the senders are permitted in once for the whole block
+	permState, err := PermissionsState(p.config, p.bc, statedb.Database(), header)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
 		vaultState.Prepare(tx.Hash(), block.Hash(), i)
 
-		receipt, vaultReceipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, vaultState, header, tx, usedGas, cfg)
+		receipt, vaultReceipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, vaultState, permState, header, tx, usedGas, cfg)
 		if err != nil {
 			return nil, nil, nil, 0, err
 		}
@@ -90,7 +100,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb, vaultState *state.
 		}
 	}
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
-	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), receipts)
+	if _, err := p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), receipts); err != nil {
+		return nil, nil, nil, 0, err
+	}
 
 	return receipts, vaultReceipts, allLogs, *usedGas, nil
 }
@@ -98,8 +110,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb, vaultState *state.
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
 // for the transaction, gas used and an error if the transaction failed,
-// indicating the block was invalid.
-func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb, vaultState *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, *types.Receipt, uint64, error) {
+// indicating the block was invalid. The sender is checked against the
+// permissions in permState, as returned by PermissionsState for the block, if
+// nil it is opened for this transaction alone.
+func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb, vaultState, permState *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, *types.Receipt, uint64, error) {
 	//if Didux is enabled and transaction is Vault, set the VaultStateDB = StateDB
 	if !config.IsSmilo || !tx.IsVault() {
 		vaultState = statedb
@@ -113,6 +127,17 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	// Ensure the sender was permitted to send the transaction at the parent block
+	if contract := config.PermissionsAddress(); contract != (common.Address{}) {
+		if permState == nil {
+			if permState, err = PermissionsState(config, bc, statedb.Database(), header); err != nil {
+				return nil, nil, 0, err
+			}
+		}
+		if !permissions.AccountPermitted(permState, contract, msg.From(), msg.To() == nil) {
+			return nil, nil, 0, ErrAccountNotPermitted
+		}
+	}
 	// Create a new context to be used in the EVM environment
 	context := NewEVMContext(msg, header, bc, author)
 	// Create a new environment which holds all relevant information
@@ -171,7 +196,24 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 
 		vaultReceipt.Logs = vaultState.GetLogs(tx.Hash())
 		vaultReceipt.Bloom = types.CreateBloom(types.Receipts{vaultReceipt})
+		vaultReceipt.BlockHash = receipt.BlockHash
+		vaultReceipt.BlockNumber = header.Number
+		vaultReceipt.TransactionIndex = receipt.TransactionIndex
 	}
 
 	return receipt, vaultReceipt, gas, err
 }
+
+// PermissionsState returns the state the senders of the transactions of the block
+// of header are permitted in, that of the parent block, or nil if the chain has
+// no permissions contract.
+func PermissionsState(config *params.ChainConfig, bc ChainContext, db state.Database, header *types.Header) (*state.StateDB, error) {
+	if config.PermissionsAddress() == (common.Address{}) {
+		return nil, nil
+	}
+	parent := bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	return state.New(parent.Root, db)
+}
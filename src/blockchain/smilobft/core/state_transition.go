@@ -214,7 +214,10 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	isGas := st.evm.ChainConfig().IsGas
 	isGasRefunded := st.evm.ChainConfig().IsGasRefunded
 
-	var data []byte
+	var (
+		data    []byte
+		privacy *vault.PrivacyMetadata
+	)
 	isVault := false
 	publicState := st.state
 	if msg, ok := msg.(VaultMessage); ok && isSmilo && msg.IsVault() {
@@ -224,16 +227,24 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			return nil, 0, false, nil
 		} else {
 			isVault = true
-			data, err = vault.VaultInstance.Get(st.data)
-			// Increment the public account nonce if:
-			// 1. Tx is vault and *not* a participant of the group and either call or create
-			// 2. Tx is vault we are part of the group and is a call
-			if err != nil || !contractCreation {
-				publicState.SetNonce(sender.Address(), publicState.GetNonce(sender.Address())+1)
+			data, privacy, err = vault.GetPayload(vault.VaultInstance, st.data)
+			switch {
+			case err == vault.ErrNotInUse:
+				// The node runs without a vault on purpose, it is party to no
+				// vault transaction
+				data, privacy = nil, nil
+			case err == vault.ErrInvalidPrivacyHeader || err == vault.ErrPrivacyParticipants:
+				log.Warn("Ignoring vault payload with invalid privacy metadata", "st.data", cmn.Bytes2Hex(st.data), "err", err)
+				data, privacy = nil, nil
+			case err != nil:
+				log.Error("&*&*&*&*& state_transition TransitionDb, Could not retrieve Vault payload. ", "st.data", cmn.Bytes2Hex(st.data), "err", err)
+				return nil, 0, false, ErrVaultUnavailable
 			}
-
-			if err != nil {
-				return nil, 0, false, nil
+			st.evm.SetTxPrivacy(privacy.Hash())
+			// Increment the public account nonce if the tx is a call, contract
+			// creations increment it in evm.Create
+			if !contractCreation {
+				publicState.SetNonce(sender.Address(), publicState.GetNonce(sender.Address())+1)
 			}
 		}
 	} else {
@@ -256,6 +267,7 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		// error.
 		vmerr      error
 		gasNotUsed uint64
+		snapshot   = evm.VaultState().Snapshot()
 	)
 	if contractCreation {
 		log.Debug("############### state_transition, will execute evm.Create, ", "isVault", isVault)
@@ -287,6 +299,15 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		log.Debug("############### state_transition, will execute evm.Call, ", "isVault", isVault)
 		ret, gasNotUsed, vmerr = evm.Call(sender, to, data, st.gas, st.value, isVault)
 	}
+	// State validation transactions fail when the affected contracts end up
+	// different from what the sender simulated
+	if vmerr == nil && privacy != nil && privacy.Flag.Has(vault.PrivacyFlagStateValidation) {
+		if root := evm.AffectedContractsRoot(); root != privacy.StateRoot {
+			log.Debug("Vault state validation failed", "expected", privacy.StateRoot, "got", root)
+			evm.VaultState().RevertToSnapshot(snapshot)
+			vmerr = ErrVaultStateValidation
+		}
+	}
 	if vmerr != nil {
 		log.Info("VM returned with error", "err", vmerr)
 		// The only possible consensus-error would be if there wasn't
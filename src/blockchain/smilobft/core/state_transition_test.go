@@ -0,0 +1,68 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/core/vm"
+	"go-didux/src/blockchain/smilobft/params"
+)
+
+// Tests that messages pay gas with the SmiloPay of the curve the chain config
+// schedules at the block, whoever opened the state they run on.
+func TestApplyMessageSmiloPayCurve(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(5), GasLimit: 4700000, Difficulty: new(big.Int)}
+	msg := callmsg{
+		addr:     common.Address{2},
+		to:       &common.Address{3},
+		value:    new(big.Int),
+		gas:      21000,
+		gasPrice: big.NewInt(1),
+	}
+	apply := func(config *params.ChainConfig) error {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.SetBalance(msg.From(), big.NewInt(1e18), common.Big0)
+
+		evm := vm.NewEVM(NewEVMContext(msg, header, nil, &common.Address{}), statedb, statedb, config, vm.Config{})
+		_, _, _, err := ApplyMessage(evm, msg, new(GasPool).AddGas(header.GasLimit))
+		return err
+	}
+	config := *params.SmiloTestChainConfig
+	if err := apply(&config); err != nil {
+		t.Fatalf("message rejected under the legacy curve: %v", err)
+	}
+	// A curve capping SmiloPay below the gas cost must reject the message
+	config.SmiloPay = params.SmiloPayCurves{{
+		Block:        common.Big0,
+		SpeedBase:    big.NewInt(1),
+		SpeedFactor:  common.Big0,
+		SpeedDivisor: 1,
+		MaxBase:      big.NewInt(1),
+		MaxFactor:    common.Big0,
+		MaxDivisor:   1,
+	}}
+	if err := apply(&config); err != ErrInsufficientSmiloPay {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInsufficientSmiloPay)
+	}
+}
@@ -25,12 +25,15 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"go-didux/src/blockchain/smilobft/consensus/ethash"
 	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/core/vm"
 	"go-didux/src/blockchain/smilobft/params"
 	"go-didux/src/blockchain/smilobft/vault"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func verifyGasPoolCalculation(t *testing.T, pm vault.BlackboxVault) {
@@ -87,13 +90,24 @@ func TestStateTransitionVault(t *testing.T) {
 			description: "non party node processing",
 			blackboxVault: &FakeBlackboxVault{
 				responses: map[string][]interface{}{
-					"Receive": {
+					"Get": {
 						[]byte{},
 						nil,
 					},
 				},
 			},
 		},
+		{
+			description: "node without vault processing",
+			blackboxVault: &FakeBlackboxVault{
+				responses: map[string][]interface{}{
+					"Get": {
+						nil,
+						vault.ErrNotInUse,
+					},
+				},
+			},
+		},
 		{
 			description: "party node processing",
 			blackboxVault: &FakeBlackboxVault{
@@ -112,17 +126,68 @@ func TestStateTransitionVault(t *testing.T) {
 	}
 }
 
+func TestStateTransitionVaultUnavailable(t *testing.T) {
+	saved := vault.VaultInstance
+	defer func() {
+		vault.VaultInstance = saved
+	}()
+	vault.VaultInstance = &FakeBlackboxVault{
+		responses: map[string][]interface{}{
+			"Get": {
+				nil,
+				fmt.Errorf("connection refused"),
+			},
+		},
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	privateState, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	publicState, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	msg := vaultMessage{
+		callmsg: callmsg{
+			addr:     common.Address{2},
+			to:       &common.Address{},
+			value:    new(big.Int),
+			gas:      100000,
+			gasPrice: big.NewInt(0),
+			data:     make([]byte, 64),
+		},
+	}
+	ctx := NewEVMContext(msg, &dualStateTestHeader, nil, &common.Address{})
+	evm := vm.NewEVM(ctx, publicState, privateState, params.SmiloTestChainConfig, vm.Config{})
+	publicState.SetBalance(msg.From(), big.NewInt(100000000), big.NewInt(1))
+
+	_, _, _, err := NewStateTransition(evm, msg, new(GasPool).AddGas(200000)).TransitionDb()
+	require.Equal(t, ErrVaultUnavailable, err, "unreachable vault must stop processing")
+	require.Equal(t, uint64(0), publicState.GetNonce(msg.From()), "nonce must not be changed")
+}
+
+func TestStateTransitionLocalVault(t *testing.T) {
+	localVault, err := vault.New(&vault.Config{Driver: vault.LocalDriver})
+	require.NoError(t, err)
+
+	digest, err := localVault.PostRaw(common.Hex2Bytes("600a6000526001601ff300"), "", nil)
+	require.NoError(t, err)
+	require.Len(t, digest, 64)
+
+	payload, err := localVault.Get(digest)
+	require.NoError(t, err)
+	require.Equal(t, common.Hex2Bytes("600a6000526001601ff300"), payload)
+
+	verifyGasPoolCalculation(t, localVault)
+}
+
 type vaultMessage struct {
 	callmsg
 }
 
-func (pm vaultMessage) IsPrivate() bool { return true }
+func (pm vaultMessage) IsVault() bool { return true }
 
 type FakeBlackboxVault struct {
 	responses map[string][]interface{}
 }
 
-func (spm *FakeBlackboxVault) Post(data []byte, from string, to []string) ([]byte, error) {
+func (spm *FakeBlackboxVault) PostRaw(data []byte, from string, to []string) ([]byte, error) {
 	return nil, fmt.Errorf("to be implemented")
 }
 
@@ -131,7 +196,7 @@ func (spm *FakeBlackboxVault) PostRawTransaction(data []byte, to []string) ([]by
 }
 
 func (spm *FakeBlackboxVault) Get(data []byte) ([]byte, error) {
-	res := spm.responses["Receive"]
+	res := spm.responses["Get"]
 	if err, ok := res[1].(error); ok {
 		return nil, err
 	}
@@ -140,3 +205,46 @@ func (spm *FakeBlackboxVault) Get(data []byte) ([]byte, error) {
 	}
 	return nil, nil
 }
+
+// Tests that a block whose vault payloads can't be fetched is not marked bad,
+// and is imported once the vault is reachable again.
+func TestInsertChainVaultUnavailable(t *testing.T) {
+	saved := vault.VaultInstance
+	defer func() {
+		vault.VaultInstance = saved
+	}()
+	reachable := &FakeBlackboxVault{responses: map[string][]interface{}{"Get": {[]byte{}, nil}}}
+	vault.VaultInstance = reachable
+
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		engine  = ethash.NewFaker()
+		config  = params.SmiloTestChainConfig
+		gspec   = &Genesis{Config: config, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}}}
+		db      = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(config, genesis, engine, db, 1, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{1}, new(big.Int), 100000, new(big.Int), make([]byte, 64)), types.HomesteadSigner{}, key)
+		tx.SetVault()
+		b.AddTx(tx)
+	})
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, config, engine, vm.Config{}, nil)
+	require.NoError(t, err)
+	defer chain.Stop()
+
+	vault.VaultInstance = &FakeBlackboxVault{responses: map[string][]interface{}{"Get": {nil, fmt.Errorf("connection refused")}}}
+	_, err = chain.InsertChain(blocks)
+	require.Equal(t, ErrVaultUnavailable, err)
+	require.False(t, chain.HasBadBlock(blocks[0].Hash()), "block must not be marked bad")
+	require.Equal(t, uint64(0), chain.CurrentBlock().NumberU64())
+
+	vault.VaultInstance = reachable
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), chain.CurrentBlock().NumberU64())
+}
@@ -27,6 +27,7 @@ import (
 
 	"go-didux/src/blockchain/smilobft/core/types"
 
+	"go-didux/src/blockchain/smilobft/contracts/permissions"
 	"go-didux/src/blockchain/smilobft/core/state"
 	"go-didux/src/blockchain/smilobft/params"
 
@@ -92,6 +93,10 @@ var (
 	// ErrEtherValueUnsupported is returned if a transaction specifies an Ether Value
 	// for a vault Smilo transaction.
 	ErrEtherValueUnsupported = errors.New("ether value is not supported for private transactions")
+
+	// ErrAccountNotPermitted is returned if the permissions contract of the chain
+	// doesn't allow the sender to send the transaction.
+	ErrAccountNotPermitted = errors.New("account not permitted")
 )
 
 var (
@@ -568,6 +573,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if !isGas || !local && pool.gasPrice.Cmp(tx.GasPrice()) > 0 && !isVault {
 		return ErrUnderpriced
 	}
+	// Ensure the sender is permitted to transact, or deploy contracts, at the head
+	if contract := pool.chainconfig.PermissionsAddress(); contract != (common.Address{}) {
+		if !permissions.AccountPermitted(pool.currentState, contract, from, tx.To() == nil) {
+			return ErrAccountNotPermitted
+		}
+	}
 	// Ensure the transaction adheres to nonce ordering
 	if pool.currentState.GetNonce(from) > tx.Nonce() {
 		return ErrNonceTooLow
@@ -1185,12 +1196,38 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	// Drop the transactions of accounts whose permissions were revoked
+	pool.dropUnpermitted()
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
 	pool.addTxsLocked(reinject, false)
 }
 
+// dropUnpermitted removes every pooled transaction the permissions contract of
+// the chain doesn't allow at the current state.
+func (pool *TxPool) dropUnpermitted() {
+	contract := pool.chainconfig.PermissionsAddress()
+	if contract == (common.Address{}) {
+		return
+	}
+	var drops []common.Hash
+	for _, lists := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		for addr, list := range lists {
+			for _, tx := range list.Flatten() {
+				if !permissions.AccountPermitted(pool.currentState, contract, addr, tx.To() == nil) {
+					drops = append(drops, tx.Hash())
+				}
+			}
+		}
+	}
+	for _, hash := range drops {
+		log.Debug("Removed unpermitted transaction", "hash", hash)
+		pool.removeTx(hash, true)
+	}
+}
+
 // promoteExecutables moves transactions that have become processable from the
 // future queue to the set of pending transactions. During this process, all
 // invalidated transactions (low nonce, low balance) are deleted.
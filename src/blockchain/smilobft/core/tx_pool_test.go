@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 
+	"go-didux/src/blockchain/smilobft/contracts/permissions"
 	"go-didux/src/blockchain/smilobft/core/state"
 	"go-didux/src/blockchain/smilobft/params"
 )
@@ -301,6 +302,45 @@ func TestInvalidTransactions(t *testing.T) {
 
 }
 
+// Tests that the permissions contract of the chain is enforced on the senders.
+func TestPermissionedTransactions(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, statedb, 1000000, new(event.Feed)}
+
+	config := *params.TestChainConfig
+	config.PermissionsContract = &common.Address{0x0f, 0x02}
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(0xffffffffffffff), big.NewInt(1))
+
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != ErrAccountNotPermitted {
+		t.Fatalf("expected %v, got %v", ErrAccountNotPermitted, err)
+	}
+	// Grant every account the right to transact, but not to deploy contracts
+	for key, value := range permissions.GenesisStorage(common.Hash{1}, nil, nil, permissions.RoleTransact) {
+		pool.currentState.SetState(*config.PermissionsContract, key, value)
+	}
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("permitted transaction rejected: %v", err)
+	}
+	create, _ := types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err := pool.AddRemote(create); err != ErrAccountNotPermitted {
+		t.Fatalf("expected %v, got %v", ErrAccountNotPermitted, err)
+	}
+	// Revoke the right to transact, the pooled transaction must be dropped on reset
+	pool.currentState.SetState(*config.PermissionsContract, common.Hash{}, common.Hash{})
+	<-pool.requestReset(nil, nil)
+
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Fatalf("unpermitted transactions kept: pending %d, queued %d", pending, queued)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 //Test for transactions that are invalid on Smilo XSM, considering ErrOversizedData errs
 func TestInvalidTransactionsCustomTransactionSizeLimit(t *testing.T) {
 	pool, key := setupTxPool()
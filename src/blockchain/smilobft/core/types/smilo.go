@@ -37,15 +37,35 @@ type SportExtra struct {
 	Fullnodes     []common.Address
 	Seal          []byte
 	CommittedSeal [][]byte
+
+	// NextFullnodes is the fullnode set taking over after an epoch checkpoint
+	// once fullnode set changes are applied at checkpoints only. It is left out
+	// of the encoding when nil, keeping the hashes of other headers unchanged.
+	NextFullnodes []common.Address
+
+	// Misbehaviours are the RLP encoded evidence of fullnodes signing conflicting
+	// consensus messages, evicting the offenders from the fullnode set. They are
+	// left out of the encoding when empty, taking an empty NextFullnodes list as
+	// placeholder if needed.
+	Misbehaviours [][]byte
 }
 
 // EncodeRLP serializes ist into the Ethereum RLP format.
 func (ist *SportExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		ist.Fullnodes,
 		ist.Seal,
 		ist.CommittedSeal,
-	})
+	}
+	switch {
+	case len(ist.Misbehaviours) > 0 && ist.NextFullnodes == nil:
+		fields = append(fields, []common.Address{}, ist.Misbehaviours)
+	case len(ist.Misbehaviours) > 0:
+		fields = append(fields, ist.NextFullnodes, ist.Misbehaviours)
+	case ist.NextFullnodes != nil:
+		fields = append(fields, ist.NextFullnodes)
+	}
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the sport fields from a RLP stream.
@@ -54,11 +74,35 @@ func (ist *SportExtra) DecodeRLP(s *rlp.Stream) error {
 		Fullnodes     []common.Address
 		Seal          []byte
 		CommittedSeal [][]byte
+		Rest          []rlp.RawValue `rlp:"tail"`
 	}
 	if err := s.Decode(&sportExtra); err != nil {
 		return err
 	}
 	ist.Fullnodes, ist.Seal, ist.CommittedSeal = sportExtra.Fullnodes, sportExtra.Seal, sportExtra.CommittedSeal
+	ist.NextFullnodes, ist.Misbehaviours = nil, nil
+	// Unknown trailing fields would be lost on re-encoding, letting different
+	// extra-data decode to the same fields
+	if len(sportExtra.Rest) > 2 {
+		return ErrInvalidSportHeaderExtra
+	}
+	if len(sportExtra.Rest) > 0 {
+		if err := rlp.DecodeBytes(sportExtra.Rest[0], &ist.NextFullnodes); err != nil {
+			return err
+		}
+	}
+	if len(sportExtra.Rest) > 1 {
+		if err := rlp.DecodeBytes(sportExtra.Rest[1], &ist.Misbehaviours); err != nil {
+			return err
+		}
+		// Only an empty list of misbehaviours is left out of the encoding
+		if len(ist.Misbehaviours) == 0 {
+			return ErrInvalidSportHeaderExtra
+		}
+		if len(ist.NextFullnodes) == 0 {
+			ist.NextFullnodes = nil
+		}
+	}
 	return nil
 }
 
@@ -98,3 +98,78 @@ func TestExtractToSport(t *testing.T) {
 		}
 	}
 }
+
+func TestSportExtraNextFullnodes(t *testing.T) {
+	extra := &types.SportExtra{
+		Fullnodes:     []common.Address{common.HexToAddress("0x44add0ec310f115a0e603b2d7db9f067778eaf8a")},
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	}
+	// Extra-data without next fullnodes keeps the legacy encoding
+	legacy, err := rlp.EncodeToBytes([]interface{}{extra.Fullnodes, extra.Seal, extra.CommittedSeal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc, _ := rlp.EncodeToBytes(extra); !bytes.Equal(enc, legacy) {
+		t.Errorf("encoding mismatch: have %x, want %x", enc, legacy)
+	}
+	// Next fullnodes survive a round trip and the header filtering
+	extra.NextFullnodes = []common.Address{common.HexToAddress("0x294fc7e8f22b3bcdcf955dd7ff3ba2ed833f8212")}
+	enc, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &types.Header{Extra: append(bytes.Repeat([]byte{0x00}, types.SportExtraVanity), enc...)}
+	for _, h := range []*types.Header{header, types.SportFilteredHeader(header, false)} {
+		decoded, err := types.ExtractSportExtra(h)
+		if err != nil {
+			t.Fatalf("failed to decode extra-data: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, extra) {
+			t.Errorf("extra-data mismatch: have %v, want %v", decoded, extra)
+		}
+	}
+	// Fields past the misbehaviours, empty misbehaviours and bytes past the
+	// list are rejected
+	extended, err := rlp.EncodeToBytes([]interface{}{extra.Fullnodes, extra.Seal, extra.CommittedSeal, extra.NextFullnodes, [][]byte{{0x01}}, [][]byte{{0x01}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := rlp.EncodeToBytes([]interface{}{extra.Fullnodes, extra.Seal, extra.CommittedSeal, extra.NextFullnodes, [][]byte{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range [][]byte{extended, empty, append(enc, 0x80)} {
+		header := &types.Header{Extra: append(bytes.Repeat([]byte{0x00}, types.SportExtraVanity), payload...)}
+		if _, err := types.ExtractSportExtra(header); err == nil {
+			t.Errorf("malleable extra-data %x accepted", payload)
+		}
+	}
+}
+
+func TestSportExtraMisbehaviours(t *testing.T) {
+	next := []common.Address{common.HexToAddress("0x294fc7e8f22b3bcdcf955dd7ff3ba2ed833f8212")}
+	for _, nextFullnodes := range [][]common.Address{nil, next} {
+		extra := &types.SportExtra{
+			Fullnodes:     []common.Address{common.HexToAddress("0x44add0ec310f115a0e603b2d7db9f067778eaf8a")},
+			Seal:          []byte{},
+			CommittedSeal: [][]byte{},
+			NextFullnodes: nextFullnodes,
+			Misbehaviours: [][]byte{{0x01, 0x02}, {0x03}},
+		}
+		enc, err := rlp.EncodeToBytes(extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Extra: append(bytes.Repeat([]byte{0x00}, types.SportExtraVanity), enc...)}
+		for _, h := range []*types.Header{header, types.SportFilteredHeader(header, false)} {
+			decoded, err := types.ExtractSportExtra(h)
+			if err != nil {
+				t.Fatalf("failed to decode extra-data: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, extra) {
+				t.Errorf("extra-data mismatch: have %v, want %v", decoded, extra)
+			}
+		}
+	}
+}
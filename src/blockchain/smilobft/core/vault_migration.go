@@ -0,0 +1,159 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/ethdb"
+	"go-didux/src/blockchain/smilobft/ethdb/memorydb"
+	"go-didux/src/blockchain/smilobft/trie"
+)
+
+var (
+	// errNoVaultStateRoot is returned when exporting the vault state of a block
+	// whose vault state root was never recorded.
+	errNoVaultStateRoot = errors.New("no vault state root recorded for block")
+
+	// errVaultStateBlock is returned when importing a vault state on a node that
+	// does not have the block it was exported at.
+	errVaultStateBlock = errors.New("vault state block not in local chain")
+)
+
+// VaultStateExport is the vault state of a block, serialized to move it to
+// another node: the vault state root the block state root maps to, and every
+// trie node and contract code reachable from it.
+//
+// Vault state roots are not part of the chain, so importing only proves that
+// the state is complete and hashes to the root it was exported with, and that
+// the block is in the local chain. That the state is the one the vault
+// transactions lead to is taken on trust from the exporting node: only import
+// states exported by nodes trusted by the participant. The vault state holds
+// the contracts of every privacy group of the exporting node, which may only
+// hand it to nodes allowed to see all of them, e.g. a node of the same
+// participant rebuilding its lost vault, or a node added to all these groups.
+type VaultStateExport struct {
+	Number    uint64          `json:"number"`
+	BlockHash common.Hash     `json:"blockHash"`
+	BlockRoot common.Hash     `json:"blockRoot"` // Public state root the vault root is recorded under
+	VaultRoot common.Hash     `json:"vaultRoot"`
+	Nodes     []hexutil.Bytes `json:"nodes"` // Trie nodes and contract codes of the vault state
+}
+
+// ExportVaultState serializes the vault state of a block out of the vault state
+// database.
+func ExportVaultState(db ethdb.Database, cache state.Database, header *types.Header) (*VaultStateExport, error) {
+	root := GetVaultStateRoot(db, header.Root)
+	if root == (common.Hash{}) {
+		return nil, errNoVaultStateRoot
+	}
+	vaultState, err := state.New(root, cache)
+	if err != nil {
+		return nil, err
+	}
+	export := &VaultStateExport{
+		Number:    header.Number.Uint64(),
+		BlockHash: header.Hash(),
+		BlockRoot: header.Root,
+		VaultRoot: root,
+		Nodes:     []hexutil.Bytes{},
+	}
+	it := state.NewNodeIterator(vaultState)
+	for it.Next() {
+		if it.Hash == (common.Hash{}) {
+			continue // Embedded node, part of its parent
+		}
+		blob, err := cache.TrieDB().Node(it.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("vault state node %x: %v", it.Hash, err)
+		}
+		export.Nodes = append(export.Nodes, blob)
+	}
+	if it.Error != nil {
+		return nil, it.Error
+	}
+	return export, nil
+}
+
+// ExportVaultState serializes the vault state of a block of the chain.
+func (bc *BlockChain) ExportVaultState(header *types.Header) (*VaultStateExport, error) {
+	return ExportVaultState(bc.db, bc.vaultStateCache, header)
+}
+
+// ImportVaultState installs an exported vault state in the database, checking
+// that it is complete and hashes to the vault state root it was exported with,
+// and records that root for the block it was exported at. See VaultStateExport
+// for what the import does not prove.
+func ImportVaultState(db ethdb.Database, export *VaultStateExport) error {
+	if rawdb.ReadCanonicalHash(db, export.Number) != export.BlockHash {
+		return errVaultStateBlock
+	}
+	header := rawdb.ReadHeader(db, export.BlockHash, export.Number)
+	if header == nil {
+		return errVaultStateBlock
+	}
+	if header.Root != export.BlockRoot {
+		return fmt.Errorf("block state root mismatch: have %x, want %x", export.BlockRoot, header.Root)
+	}
+	nodes := make(map[common.Hash][]byte, len(export.Nodes))
+	for _, blob := range export.Nodes {
+		nodes[crypto.Keccak256Hash(blob)] = blob
+	}
+	// Feed the nodes to a state sync from the vault root, which only accepts the
+	// nodes it asks for by hash and fails on missing ones
+	bloom := trie.NewSyncBloom(1, memorydb.New())
+	defer bloom.Close()
+
+	sched := state.NewStateSync(export.VaultRoot, db, bloom)
+	for {
+		missing := sched.Missing(0)
+		if len(missing) == 0 {
+			break
+		}
+		results := make([]trie.SyncResult, len(missing))
+		for i, hash := range missing {
+			blob, ok := nodes[hash]
+			if !ok {
+				return fmt.Errorf("vault state incomplete, missing node %x", hash)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: blob}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			return fmt.Errorf("invalid vault state node %x: %v", results[index].Hash, err)
+		}
+	}
+	batch := db.NewBatch()
+	if _, err := sched.Commit(batch); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if root := GetVaultStateRoot(db, export.BlockRoot); root != (common.Hash{}) && root != export.VaultRoot {
+		log.Warn("Replacing vault state root", "number", export.Number, "old", root, "new", export.VaultRoot)
+	}
+	return WriteVaultStateRoot(db, export.BlockRoot, export.VaultRoot)
+}
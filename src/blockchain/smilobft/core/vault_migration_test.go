@@ -0,0 +1,153 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
+	"go-didux/src/blockchain/smilobft/ethdb"
+)
+
+// writeVaultChain writes a canonical chain of headers up to number into db,
+// block 1 holding a vault transaction for each digest.
+func writeVaultChain(db ethdb.Database, number uint64, digests [][]byte) *types.Header {
+	var header *types.Header
+	for n := uint64(1); n <= number; n++ {
+		header = &types.Header{Number: new(big.Int).SetUint64(n), Root: common.Hash{byte(n)}}
+		body := new(types.Body)
+		if n == 1 {
+			for i, digest := range digests {
+				tx := types.NewTransaction(uint64(i), common.Address{}, common.Big0, 0, common.Big0, digest)
+				tx.SetVault()
+				body.Transactions = append(body.Transactions, tx)
+			}
+		}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteBody(db, header.Hash(), n, body)
+		rawdb.WriteCanonicalHash(db, header.Hash(), n)
+	}
+	return header
+}
+
+// newVaultStateBlock creates a vault state with a contract in db and records
+// its root for the head of a chain with vault transactions for the digests,
+// returning the block header.
+func newVaultStateBlock(t *testing.T, db ethdb.Database, digests [][]byte) *types.Header {
+	cache := state.NewDatabase(db)
+	vaultState, _ := state.New(common.Hash{}, cache)
+	for i := byte(1); i <= 16; i++ {
+		addr := common.Address{i}
+		vaultState.SetNonce(addr, uint64(i))
+		vaultState.SetCode(addr, []byte{0x60, i, 0x60, 0x00, 0x55})
+		vaultState.SetState(addr, common.Hash{i}, common.Hash{0xff, i})
+	}
+	root, err := vaultState.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit vault state: %v", err)
+	}
+	if err := cache.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to flush vault state: %v", err)
+	}
+	header := writeVaultChain(db, 5, digests)
+	if err := WriteVaultStateRoot(db, header.Root, root); err != nil {
+		t.Fatalf("failed to write vault state root: %v", err)
+	}
+	return header
+}
+
+func TestVaultStateExportImport(t *testing.T) {
+	digests := [][]byte{{0x01}, {0x02}}
+	srcdb := rawdb.NewMemoryDatabase()
+	header := newVaultStateBlock(t, srcdb, digests)
+
+	export, err := ExportVaultState(srcdb, state.NewDatabase(srcdb), header)
+	if err != nil {
+		t.Fatalf("failed to export vault state: %v", err)
+	}
+	if export.VaultRoot != GetVaultStateRoot(srcdb, header.Root) {
+		t.Fatalf("vault root mismatch: have %x, want %x", export.VaultRoot, GetVaultStateRoot(srcdb, header.Root))
+	}
+	// The importing node may have lost its vault or not be party to the same
+	// vault transactions, only the chain is needed
+	dstdb := rawdb.NewMemoryDatabase()
+	writeVaultChain(dstdb, 5, digests)
+	if err := ImportVaultState(dstdb, export); err != nil {
+		t.Fatalf("failed to import vault state: %v", err)
+	}
+	if root := GetVaultStateRoot(dstdb, header.Root); root != export.VaultRoot {
+		t.Fatalf("imported vault root mismatch: have %x, want %x", root, export.VaultRoot)
+	}
+	vaultState, err := state.New(export.VaultRoot, state.NewDatabase(dstdb))
+	if err != nil {
+		t.Fatalf("failed to open imported vault state: %v", err)
+	}
+	for i := byte(1); i <= 16; i++ {
+		addr := common.Address{i}
+		if nonce := vaultState.GetNonce(addr); nonce != uint64(i) {
+			t.Errorf("account %x: nonce mismatch: have %d, want %d", addr, nonce, i)
+		}
+		if code := vaultState.GetCode(addr); len(code) != 5 || code[1] != i {
+			t.Errorf("account %x: code mismatch: have %x", addr, code)
+		}
+		if value := vaultState.GetState(addr, common.Hash{i}); value != (common.Hash{0xff, i}) {
+			t.Errorf("account %x: storage mismatch: have %x", addr, value)
+		}
+	}
+}
+
+func TestVaultStateImportInvalid(t *testing.T) {
+	digests := [][]byte{{0x01}, {0x02}}
+	srcdb := rawdb.NewMemoryDatabase()
+	header := newVaultStateBlock(t, srcdb, digests)
+
+	export, err := ExportVaultState(srcdb, state.NewDatabase(srcdb), header)
+	if err != nil {
+		t.Fatalf("failed to export vault state: %v", err)
+	}
+	// A block the importing node doesn't have must be rejected
+	if err := ImportVaultState(rawdb.NewMemoryDatabase(), export); err != errVaultStateBlock {
+		t.Errorf("unknown block: error mismatch: have %v, want %v", err, errVaultStateBlock)
+	}
+	dstdb := rawdb.NewMemoryDatabase()
+	writeVaultChain(dstdb, 5, digests)
+
+	// A state missing a node must be rejected without recording the root
+	incomplete := *export
+	incomplete.Nodes = export.Nodes[:len(export.Nodes)-1]
+	if err := ImportVaultState(dstdb, &incomplete); err == nil {
+		t.Errorf("incomplete vault state imported")
+	}
+	if root := GetVaultStateRoot(dstdb, header.Root); root != (common.Hash{}) {
+		t.Errorf("vault root recorded for incomplete state: %x", root)
+	}
+	// A block root not matching the local block must be rejected
+	mismatch := *export
+	mismatch.BlockRoot = common.Hash{0x02}
+	if err := ImportVaultState(dstdb, &mismatch); err == nil {
+		t.Errorf("vault state with mismatching block root imported")
+	}
+	// Exporting a block without a recorded vault state must fail
+	if _, err := ExportVaultState(dstdb, state.NewDatabase(dstdb), &types.Header{Number: big.NewInt(6)}); err != errNoVaultStateRoot {
+		t.Errorf("no vault root: error mismatch: have %v, want %v", err, errNoVaultStateRoot)
+	}
+}
@@ -2,20 +2,15 @@ package core
 
 import (
 	"fmt"
-	"io/ioutil"
 	"math/big"
-	"os"
-	osExec "os/exec"
-	"path"
-	"path/filepath"
-	"runtime"
 	"testing"
-	"time"
-
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/core/vm"
+	"go-didux/src/blockchain/smilobft/vault"
 )
 
 // callmsg is the message type used for call transactions in the vault state test
@@ -55,8 +50,14 @@ func ExampleMakeCallHelper() {
 	// Initialise custom code for public contract
 	helper.PublicState.SetCode(pubContractAddr, common.Hex2Bytes("601460005500"))
 
+	// Store the vault payload in an in-memory vault
+	saved := vault.VaultInstance
+	defer func() { vault.VaultInstance = saved }()
+	vault.VaultInstance, _ = vault.New(&vault.Config{Driver: vault.LocalDriver})
+	digest, _ := vault.VaultInstance.PostRaw([]byte{1}, "", nil)
+
 	// Make a call to the vault contract
-	err := helper.MakeCall(true, key, vaultContractAddr, nil)
+	err := helper.MakeCall(true, key, vaultContractAddr, digest)
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -73,112 +74,6 @@ func ExampleMakeCallHelper() {
 	fmt.Println("Public:", helper.PublicState.GetState(pubContractAddr, common.Hash{}).Big())
 }
 
-func runBlackbox() (*osExec.Cmd, error) {
-
-	tempdir, err := ioutil.TempDir("", "blackbox")
-	if err != nil {
-		return nil, err
-	}
-	// create config.json file
-	here, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	blackboxCMD := filepath.Join(here, "../../../../build/third-party", "blackbox-v0-1")
-	blackboxDBFile := filepath.Join(tempdir, "blackbox.db")
-	blackboxPeersDBFile := filepath.Join(tempdir, "blackbox-peers.db")
-
-	if err = os.MkdirAll(path.Join(tempdir, "sdata"), 0755); err != nil {
-		return nil, err
-	}
-	tmIPCFile := filepath.Join(tempdir, "sdata", "tm.ipc")
-	keyData, err := ioutil.ReadFile(filepath.Join(here, "blackbox-test-keys", "tm1.key"))
-	if err != nil {
-		return nil, err
-	}
-	publicKeyData, err := ioutil.ReadFile(filepath.Join(here, "blackbox-test-keys", "tm1.pub"))
-	if err != nil {
-		return nil, err
-	}
-	blackboxConfigFile := filepath.Join(tempdir, "config.json")
-	if err := ioutil.WriteFile(blackboxConfigFile, []byte(fmt.Sprintf(`
-{
-    "useWhiteList": false,   
-    "server": {
-        "port": 9000,
-        "hostName": "http://localhost",
-        "sslConfig": {
-            "tls": "OFF",
-            "generateKeyStoreIfNotExisted": true,
-            "serverKeyStore": "./sdata/c1/server1-keystore",
-            "serverKeyStorePassword": "smilo",
-            "serverTrustStore": "./sdata/c1/server-truststore",
-            "serverTrustStorePassword": "smilo",
-            "serverTrustMode": "TOFU",
-            "knownClientsFile": "./sdata/c1/knownClients",
-            "clientKeyStore": "./c1/client1-keystore",
-            "clientKeyStorePassword": "smilo",
-            "clientTrustStore": "./c1/client-truststore",
-            "clientTrustStorePassword": "smilo",
-            "clientTrustMode": "TOFU",
-            "knownServersFile": "./sdata/c1/knownServers"
-        }
-    },
-    "peer": [
-        {
-            "url": "http://localhost:9000"
-        }
-    ],
-    "keys": {
-        "passwords": [],
-        "keyData": [
-            {
-                "config": %s,
-                "publicKey": "%s"
-            }
-        ]
-    },
-    "alwaysSendTo": [],
-    "socket": "%s",
-    "dbfile": "%s",
-    "peersdbfile": "%s",
-}
-`, string(keyData), string(publicKeyData), tmIPCFile, blackboxDBFile, blackboxPeersDBFile)), os.FileMode(0644)); err != nil {
-		return nil, err
-	}
-
-	cmdStatusChan := make(chan error)
-	cmd := osExec.Command(blackboxCMD, "-configfile", blackboxConfigFile, "-dbfile", blackboxDBFile)
-	// run blackbox
-	go func() {
-		err := cmd.Start()
-		cmdStatusChan <- err
-	}()
-	// wait 30s for blackbox to come up
-	var started bool
-	go func() {
-
-		for i := 0; i < 10; i++ {
-			time.Sleep(3 * time.Second)
-			if err := checkFunc(tmIPCFile); err != nil && err == doneErr {
-				cmdStatusChan <- err
-			} else {
-				fmt.Println("Waiting for blackbox to start", "err", err)
-			}
-		}
-		if !started {
-			panic("Blackbox never managed to start!")
-		}
-	}()
-
-	if err := <-cmdStatusChan; err != nil {
-		return nil, err
-	}
-	// wait until blackbox is up
-	return cmd, nil
-}
-
 // 600a600055600060006001a1
 // [1] PUSH1 0x0a (store value)
 // [3] PUSH1 0x00 (store addr)
@@ -190,11 +85,6 @@ func runBlackbox() (*osExec.Cmd, error) {
 //
 // Store then log
 func TestVaultTransaction(t *testing.T) {
-	//TODO: Add blackbox OSX/WIN compiled libs, detect os and run appropriate files
-	if runtime.GOOS != "linux" {
-		t.Skip()
-	}
-
 	var (
 		key, _      = crypto.GenerateKey()
 		helper      = MakeCallHelper()
@@ -202,17 +92,8 @@ func TestVaultTransaction(t *testing.T) {
 		publicState = helper.PublicState
 	)
 
-	blackboxCmd, err := runBlackbox()
-	if err != nil {
-		if strings.Contains(err.Error(), "executable file not found") {
-			if blackboxCmd, err = runBlackbox(); err != nil {
-				t.Fatal(err)
-			}
-		} else {
-			t.Fatal(err)
-		}
-	}
-	defer blackboxCmd.Process.Kill()
+	localVault := useLocalVault(t)
+	input := postPayload(t, localVault, []byte{1}, nil)
 
 	vaultContractAddr := common.Address{1}
 	pubContractAddr := common.Address{2}
@@ -226,7 +107,7 @@ func TestVaultTransaction(t *testing.T) {
 	}
 
 	// Vault transaction 1
-	err = helper.MakeCall(true, key, vaultContractAddr, nil)
+	err := helper.MakeCall(true, key, vaultContractAddr, input)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -258,7 +139,7 @@ func TestVaultTransaction(t *testing.T) {
 	}
 
 	// Vault transaction 2
-	err = helper.MakeCall(true, key, vaultContractAddr, nil)
+	err = helper.MakeCall(true, key, vaultContractAddr, input)
 	stateEntry = vaultState.GetState(vaultContractAddr, common.Hash{}).Big()
 	if stateEntry.Cmp(big.NewInt(10)) != 0 {
 		t.Error("expected state to have 10, got", stateEntry)
@@ -271,3 +152,122 @@ func TestVaultTransaction(t *testing.T) {
 		t.Error("didn't expect public contract address to exist on vault state")
 	}
 }
+
+// useLocalVault replaces the vault instance with an in-memory local vault for
+// the duration of the test.
+func useLocalVault(t *testing.T) vault.BlackboxVault {
+	saved := vault.VaultInstance
+	t.Cleanup(func() { vault.VaultInstance = saved })
+
+	localVault, err := vault.New(&vault.Config{Driver: vault.LocalDriver})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault.VaultInstance = localVault
+	return localVault
+}
+
+// postPayload stores data with the given privacy metadata, sent to its
+// participants, and returns the digest to use as vault transaction input.
+func postPayload(t *testing.T, v vault.BlackboxVault, data []byte, meta *vault.PrivacyMetadata) []byte {
+	var (
+		from string
+		to   []string
+	)
+	if meta != nil {
+		from, to = meta.Participants[0], meta.Participants[1:]
+	}
+	digest, err := vault.PostPayload(v, data, meta, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest
+}
+
+func TestVaultPartyProtection(t *testing.T) {
+	var (
+		key, _       = crypto.GenerateKey()
+		helper       = MakeCallHelper()
+		vaultState   = helper.VaultState
+		localVault   = useLocalVault(t)
+		contractAddr = common.Address{1}
+	)
+	owners, err := vault.NewPrivacyMetadata(vault.PrivacyFlagPartyProtection, "A", []string{"B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	others, _ := vault.NewPrivacyMetadata(vault.PrivacyFlagPartyProtection, "A", []string{"C"})
+
+	vaultState.SetCode(contractAddr, common.Hex2Bytes("600a600055"))
+	vaultState.SetState(contractAddr, common.Hash{}, common.Hash{9})
+	vaultState.SetState(vm.PrivacyRegistryAddress, common.BytesToHash(contractAddr.Bytes()), owners.Hash())
+
+	tests := []struct {
+		meta *vault.PrivacyMetadata
+		want common.Hash
+	}{
+		{nil, common.Hash{9}},                      // standard private transaction
+		{others, common.Hash{9}},                   // different participants
+		{owners, common.BigToHash(big.NewInt(10))}, // same participants
+	}
+	for i, tt := range tests {
+		input := postPayload(t, localVault, []byte{byte(i + 1)}, tt.meta)
+		if err := helper.MakeCall(true, key, contractAddr, input); err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if got := vaultState.GetState(contractAddr, common.Hash{}); got != tt.want {
+			t.Errorf("test %d: state mismatch: have %v, want %v", i, got, tt.want)
+		}
+	}
+	// Participants claimed by the sender but not recipients of the payload
+	// must not unlock the contract
+	vaultState.SetState(contractAddr, common.Hash{}, common.Hash{9})
+	enc, _ := rlp.EncodeToBytes(owners)
+	input, err := localVault.(vault.MetadataVault).PostRawMetadata([]byte{4}, enc, "A", []string{"C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := helper.MakeCall(true, key, contractAddr, input); err != nil {
+		t.Fatalf("forged participants: %v", err)
+	}
+	if got := vaultState.GetState(contractAddr, common.Hash{}); got != (common.Hash{9}) {
+		t.Errorf("forged participants: state mismatch: have %v, want %v", got, common.Hash{9})
+	}
+}
+
+func TestVaultStateValidation(t *testing.T) {
+	var (
+		key, _       = crypto.GenerateKey()
+		helper       = MakeCallHelper()
+		vaultState   = helper.VaultState
+		localVault   = useLocalVault(t)
+		contractAddr = common.Address{1}
+	)
+	meta, err := vault.NewPrivacyMetadata(vault.PrivacyFlagStateValidation, "A", []string{"B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultState.SetCode(contractAddr, common.Hex2Bytes("600a600055"))
+	vaultState.SetState(contractAddr, common.Hash{}, common.Hash{9})
+	vaultState.SetState(vm.PrivacyRegistryAddress, common.BytesToHash(contractAddr.Bytes()), meta.Hash())
+
+	// A wrong expected root must leave the contract untouched
+	meta.StateRoot = common.Hash{1}
+	if err := helper.MakeCall(true, key, contractAddr, postPayload(t, localVault, []byte{1}, meta)); err != nil {
+		t.Fatal(err)
+	}
+	if got := vaultState.GetState(contractAddr, common.Hash{}); got != (common.Hash{9}) {
+		t.Fatalf("state changed on validation failure: have %x", got)
+	}
+
+	// The root of the expected post state must be accepted
+	expected := vaultState.Copy()
+	expected.SetState(contractAddr, common.Hash{}, common.BigToHash(big.NewInt(10)))
+	meta.StateRoot = crypto.Keccak256Hash(contractAddr.Bytes(), expected.StorageTrie(contractAddr).Hash().Bytes(), expected.GetCodeHash(contractAddr).Bytes())
+	if err := helper.MakeCall(true, key, contractAddr, postPayload(t, localVault, []byte{2}, meta)); err != nil {
+		t.Fatal(err)
+	}
+	if got := vaultState.GetState(contractAddr, common.Hash{}).Big(); got.Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("state mismatch after validation: have %v, want 10", got)
+	}
+}
@@ -2,20 +2,38 @@ package core
 
 import (
 	"encoding/binary"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/ethdb"
 )
 
 var (
-	vaultRootPrefix = []byte("P")
-	//vaultblockReceiptsPrefix = []byte("Pr") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
-	//vaultReceiptPrefix       = []byte("Prs")
-	vaultBloomPrefix = []byte("Pb")
+	vaultRootPrefix          = []byte("P")
+	vaultBlockReceiptsPrefix = []byte("Pr") // vaultBlockReceiptsPrefix + num (uint64 big endian) + hash -> block vault receipts
+	vaultBloomPrefix         = []byte("Pb")
 )
 
+// vaultReceiptRLP is the storage encoding of a vault receipt. Unlike public
+// receipts the transaction metadata is kept, vault receipts only exist for the
+// vault transactions of a block and can't be derived from the block body.
+type vaultReceiptRLP struct {
+	TxHash          common.Hash
+	TxIndex         uint
+	ContractAddress common.Address
+	GasUsed         uint64
+	Receipt         *types.ReceiptForStorage
+}
+
+// vaultBlockReceiptsKey = vaultBlockReceiptsPrefix + num (uint64 big endian) + hash
+func vaultBlockReceiptsKey(number uint64, hash common.Hash) []byte {
+	return append(append(vaultBlockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -32,6 +50,13 @@ func WriteVaultStateRoot(db ethdb.Database, blockRoot, root common.Hash) error {
 	return db.Put(append(vaultRootPrefix, blockRoot[:]...), root[:])
 }
 
+// DeleteVaultStateRoot removes the vault state root mapped to the given block root.
+func DeleteVaultStateRoot(db ethdb.KeyValueWriter, blockRoot common.Hash) {
+	if err := db.Delete(append(vaultRootPrefix, blockRoot[:]...)); err != nil {
+		log.Crit("Failed to delete vault state root", "err", err)
+	}
+}
+
 // WriteVaultBlockBloom creates a bloom filter for the given receipts and saves it to the database
 // with the number given as identifier (i.e. block number).
 func WriteVaultBlockBloom(db ethdb.Database, number uint64, receipts types.Receipts) error {
@@ -47,3 +72,88 @@ func GetVaultBlockBloom(db ethdb.Database, number uint64) (bloom types.Bloom) {
 	}
 	return bloom
 }
+
+// DeleteVaultBlockBloom removes the vault bloom associated with the given number.
+func DeleteVaultBlockBloom(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Delete(append(vaultBloomPrefix, encodeBlockNumber(number)...)); err != nil {
+		log.Crit("Failed to delete vault block bloom", "err", err)
+	}
+}
+
+// WriteVaultReceipts stores the vault receipts of a block. They live in the key
+// value store only and are never moved to the ancient store.
+func WriteVaultReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	stored := make([]*vaultReceiptRLP, len(receipts))
+	for i, receipt := range receipts {
+		stored[i] = &vaultReceiptRLP{
+			TxHash:          receipt.TxHash,
+			TxIndex:         receipt.TransactionIndex,
+			ContractAddress: receipt.ContractAddress,
+			GasUsed:         receipt.GasUsed,
+			Receipt:         (*types.ReceiptForStorage)(receipt),
+		}
+	}
+	bytes, err := rlp.EncodeToBytes(stored)
+	if err != nil {
+		return err
+	}
+	return db.Put(vaultBlockReceiptsKey(number, hash), bytes)
+}
+
+// ReadVaultReceipts retrieves the vault receipts of a block, with the block and
+// transaction fields of the receipts and their logs filled in.
+func ReadVaultReceipts(db ethdb.KeyValueReader, hash common.Hash, number uint64) types.Receipts {
+	data, _ := db.Get(vaultBlockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var stored []*vaultReceiptRLP
+	if err := rlp.DecodeBytes(data, &stored); err != nil {
+		log.Error("Invalid vault receipts RLP", "hash", hash, "err", err)
+		return nil
+	}
+	var (
+		receipts = make(types.Receipts, len(stored))
+		logIndex uint
+	)
+	for i, entry := range stored {
+		receipt := (*types.Receipt)(entry.Receipt)
+		receipt.TxHash = entry.TxHash
+		receipt.ContractAddress = entry.ContractAddress
+		receipt.GasUsed = entry.GasUsed
+		receipt.BlockHash = hash
+		receipt.BlockNumber = new(big.Int).SetUint64(number)
+		receipt.TransactionIndex = entry.TxIndex
+		for _, l := range receipt.Logs {
+			l.BlockNumber = number
+			l.BlockHash = hash
+			l.TxHash = entry.TxHash
+			l.TxIndex = entry.TxIndex
+			l.Index = logIndex
+			logIndex++
+		}
+		receipts[i] = receipt
+	}
+	return receipts
+}
+
+// DeleteVaultReceipts removes the vault receipts of a block.
+func DeleteVaultReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(vaultBlockReceiptsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete vault receipts", "err", err)
+	}
+}
+
+// GetVaultReceipt returns the vault receipt of the given transaction out of the
+// vault receipts of its block, nil if the transaction is not a vault one.
+func GetVaultReceipt(receipts types.Receipts, txHash common.Hash) *types.Receipt {
+	for _, receipt := range receipts {
+		if receipt.TxHash == txHash {
+			return receipt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/core/rawdb"
+	"go-didux/src/blockchain/smilobft/core/types"
+)
+
+func TestVaultReceiptsStorage(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	created := types.NewReceipt(nil, false, 21000)
+	created.TxHash = common.Hash{1}
+	created.TransactionIndex = 2
+	created.ContractAddress = common.Address{0xaa}
+	created.GasUsed = 21000
+	created.Logs = []*types.Log{{Address: common.Address{0xaa}, Topics: []common.Hash{{0x01}}, Data: []byte{1}}}
+
+	called := types.NewReceipt(nil, true, 42000)
+	called.TxHash = common.Hash{2}
+	called.TransactionIndex = 5
+	called.GasUsed = 21000
+	called.Logs = []*types.Log{{Address: common.Address{0xbb}}, {Address: common.Address{0xbb}}}
+
+	hash, number := common.Hash{0xff}, uint64(7)
+	if err := WriteVaultReceipts(db, hash, number, types.Receipts{created, called}); err != nil {
+		t.Fatalf("failed to write vault receipts: %v", err)
+	}
+	receipts := ReadVaultReceipts(db, hash, number)
+	if len(receipts) != 2 {
+		t.Fatalf("vault receipt count mismatch: have %d, want 2", len(receipts))
+	}
+	receipt := GetVaultReceipt(receipts, common.Hash{1})
+	if receipt == nil {
+		t.Fatalf("vault receipt not found")
+	}
+	if receipt.ContractAddress != created.ContractAddress || receipt.GasUsed != 21000 || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("vault receipt mismatch: %+v", receipt)
+	}
+	if receipt.BlockHash != hash || receipt.BlockNumber.Cmp(big.NewInt(7)) != 0 || receipt.TransactionIndex != 2 {
+		t.Errorf("vault receipt block fields not derived: %+v", receipt)
+	}
+	if l := receipt.Logs[0]; l.TxHash != created.TxHash || l.BlockHash != hash || l.BlockNumber != number || l.TxIndex != 2 || l.Index != 0 {
+		t.Errorf("vault log fields not derived: %+v", l)
+	}
+	if l := receipts[1].Logs[1]; l.Index != 2 || l.TxIndex != 5 {
+		t.Errorf("vault log index mismatch: have %d/%d, want 2/5", l.Index, l.TxIndex)
+	}
+	if GetVaultReceipt(receipts, common.Hash{3}) != nil {
+		t.Errorf("found vault receipt of unknown transaction")
+	}
+
+	DeleteVaultReceipts(db, hash, number)
+	if receipts := ReadVaultReceipts(db, hash, number); receipts != nil {
+		t.Fatalf("vault receipts still present after deletion: %v", receipts)
+	}
+}
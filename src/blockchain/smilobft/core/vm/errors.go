@@ -31,4 +31,5 @@ var (
 	ErrReadOnlyValueTransfer      = errors.New("vm in read-only mode. Value transfer prohibited")
 	ErrReadOnlyMutateOpcode       = errors.New("vm in read-only mode. Mutating opcode prohibited")
 	ErrIsVaultDiffThenIsVaultOnDB = errors.New("isVault method input is different than isVault on the DB")
+	ErrPrivacyEnforcement         = errors.New("vault contract privacy does not match the transaction")
 )
@@ -158,6 +158,12 @@ type EVM struct {
 	// Smilo read only state. Inside Vault State towards Public State read.
 	smiloReadOnly bool
 	readOnlyDepth uint
+
+	// Privacy enforcement of the vault transaction being executed, see
+	// SetTxPrivacy.
+	privacyEnforced  bool
+	txPrivacy        common.Hash
+	affectedContract map[common.Address]struct{}
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -191,6 +197,12 @@ func NewEVM(ctx Context, statedb, vaultState StateDB, chainConfig *params.ChainC
 		panic("No supported ewasm interpreter yet.")
 	}
 
+	// SmiloPay regenerates with the curve schedule of the chain being executed
+	for _, db := range []StateDB{statedb, vaultState} {
+		if db, ok := db.(interface{ SetSmiloPayCurves(params.SmiloPayCurves) }); ok {
+			db.SetSmiloPayCurves(chainConfig.SmiloPay)
+		}
+	}
 	evm.Push(vaultState)
 
 	// vmConfig.EVMInterpreter will be used by EVM-C, it won't be checked here
@@ -233,6 +245,11 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		log.Debug("&*&*&*&*&*& evm.Call, ErrIsVaultDiffThenIsVaultOnDB, ", "from", caller.Address().Hex(), "to", addr.Hex(), "gas", gas, "value", value, "input", cmn.Bytes2Hex(input), "evm.smiloReadOnly", evm.smiloReadOnly, "isVault", isVault, "isVaultOnDB", isVaultOnDB)
 		isVault = isVaultOnDB
 	}
+	if isVaultOnDB {
+		if err := evm.checkPrivacy(addr); err != nil {
+			return nil, gas, err
+		}
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -337,6 +354,11 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		log.Debug("&*&*&*&*&*& evm.CallCode, ErrIsVaultDiffThenIsVaultOnDB, ", "from", caller.Address().Hex(), "to", addr.Hex(), "gas", gas, "value", value, "input", cmn.Bytes2Hex(input), "evm.smiloReadOnly", evm.smiloReadOnly, "isVault", isVault, "isVaultOnDB", isVaultOnDB)
 		isVault = isVaultOnDB
 	}
+	if isVaultOnDB {
+		if err := evm.checkPrivacy(addr); err != nil {
+			return nil, gas, err
+		}
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -383,6 +405,11 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		log.Debug("&*&*&*&*&*& evm.DelegateCall, ErrIsVaultDiffThenIsVaultOnDB, ", "from", caller.Address().Hex(), "to", addr.Hex(), "gas", gas, "input", cmn.Bytes2Hex(input), "evm.smiloReadOnly", evm.smiloReadOnly, "isVault", isVault, "isVaultOnDB", isVaultOnDB)
 		isVault = isVaultOnDB
 	}
+	if isVaultOnDB {
+		if err := evm.checkPrivacy(addr); err != nil {
+			return nil, gas, err
+		}
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -426,6 +453,11 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		log.Debug("&*&*&*&*&*& evm.StaticCall, ErrIsVaultDiffThenIsVaultOnDB, ", "from", caller.Address().Hex(), "to", addr.Hex(), "gas", gas, "input", cmn.Bytes2Hex(input), "evm.smiloReadOnly", evm.smiloReadOnly, "isVault", isVault, "isVaultOnDB", isVaultOnDB)
 		isVault = isVaultOnDB
 	}
+	if isVaultOnDB {
+		if err := evm.checkPrivacy(addr); err != nil {
+			return nil, gas, err
+		}
+	}
 
 	var (
 		to       = AccountRef(addr)
@@ -559,6 +591,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		createDataGas := uint64(len(ret)) * params.CreateDataGas
 		if contract.UseGas(createDataGas) {
 			evm.StateDB.SetCode(address, ret)
+			evm.recordPrivacy(address)
 		} else {
 			err = ErrCodeStoreOutOfGas
 		}
@@ -0,0 +1,106 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PrivacyRegistryAddress is the vault state account holding the privacy hash of
+// every contract created by a party protection or state validation
+// transaction, keyed by contract address.
+var PrivacyRegistryAddress = common.BytesToAddress(crypto.Keccak256([]byte("smilo-vault-privacy")))
+
+// SetTxPrivacy enables privacy enforcement for the vault transaction executed
+// by the EVM. Only contracts whose recorded privacy hash equals hash may be
+// called, and contracts created by the transaction are recorded with it.
+func (evm *EVM) SetTxPrivacy(hash common.Hash) {
+	evm.privacyEnforced = true
+	evm.txPrivacy = hash
+	evm.affectedContract = make(map[common.Address]struct{})
+}
+
+// ContractPrivacy returns the privacy hash recorded for a vault contract, the
+// zero hash for standard private contracts.
+func ContractPrivacy(db StateDB, addr common.Address) common.Hash {
+	return db.GetState(PrivacyRegistryAddress, common.BytesToHash(addr.Bytes()))
+}
+
+// checkPrivacy rejects a call into a vault contract created with a different
+// privacy flag or participant set than the running transaction.
+func (evm *EVM) checkPrivacy(addr common.Address) error {
+	if !evm.privacyEnforced || evm.vaultState == evm.publicState {
+		return nil
+	}
+	if recorded := ContractPrivacy(evm.vaultState, addr); recorded != evm.txPrivacy {
+		log.Debug("Vault contract privacy mismatch", "contract", addr, "recorded", recorded, "tx", evm.txPrivacy)
+		return ErrPrivacyEnforcement
+	}
+	evm.affectedContract[addr] = struct{}{}
+	return nil
+}
+
+// recordPrivacy stores the privacy hash of the running transaction for a
+// contract it created in the vault state.
+func (evm *EVM) recordPrivacy(addr common.Address) {
+	if !evm.privacyEnforced || evm.vaultState == evm.publicState || evm.StateDB != evm.vaultState {
+		return
+	}
+	evm.affectedContract[addr] = struct{}{}
+	if evm.txPrivacy == (common.Hash{}) {
+		return
+	}
+	// The registry account needs a nonce, empty accounts are deleted
+	if evm.vaultState.GetNonce(PrivacyRegistryAddress) == 0 {
+		evm.vaultState.SetNonce(PrivacyRegistryAddress, 1)
+	}
+	evm.vaultState.SetState(PrivacyRegistryAddress, common.BytesToHash(addr.Bytes()), evm.txPrivacy)
+}
+
+// AffectedContracts returns the sorted vault contracts called or created by the
+// transaction.
+func (evm *EVM) AffectedContracts() []common.Address {
+	addrs := make([]common.Address, 0, len(evm.affectedContract))
+	for addr := range evm.affectedContract {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs
+}
+
+// AffectedContractsRoot hashes the storage root and code hash of every affected
+// contract. State validation transactions carry the root computed by the
+// sender and fail on nodes that end up with a different one.
+func (evm *EVM) AffectedContractsRoot() common.Hash {
+	var buf []byte
+	for _, addr := range evm.AffectedContracts() {
+		var root common.Hash
+		if tr := evm.vaultState.StorageTrie(addr); tr != nil {
+			root = tr.Hash()
+		}
+		codeHash := evm.vaultState.GetCodeHash(addr)
+		buf = append(buf, addr.Bytes()...)
+		buf = append(buf, root.Bytes()...)
+		buf = append(buf, codeHash.Bytes()...)
+	}
+	return crypto.Keccak256Hash(buf)
+}
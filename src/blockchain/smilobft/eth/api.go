@@ -42,6 +42,7 @@ import (
 	"go-didux/src/blockchain/smilobft/internal/ethapi"
 	"go-didux/src/blockchain/smilobft/params"
 	"go-didux/src/blockchain/smilobft/trie"
+	"go-didux/src/blockchain/smilobft/vault"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum full node-related
@@ -220,6 +221,12 @@ func (api *PrivateAdminAPI) ExportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// VaultStatus reports the connectivity, latency and error counters of the
+// private transaction manager.
+func (api *PrivateAdminAPI) VaultStatus() *vault.Health {
+	return vault.Status()
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
@@ -339,6 +346,25 @@ func NewPrivateDebugAPI(config *params.ChainConfig, eth *Smilo) *PrivateDebugAPI
 	return &PrivateDebugAPI{config: config, eth: eth}
 }
 
+// DumpVaultState serializes the vault state at a given block together with the
+// vault state root it is recorded under, for installing it on another node. The
+// dump holds the vault contracts of every privacy group of the node.
+func (api *PrivateDebugAPI) DumpVaultState(blockNr rpc.BlockNumber) (*core.VaultStateExport, error) {
+	var block *types.Block
+	switch blockNr {
+	case rpc.PendingBlockNumber:
+		return nil, errors.New("vault state of the pending block can't be exported")
+	case rpc.LatestBlockNumber:
+		block = api.eth.blockchain.CurrentBlock()
+	default:
+		block = api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	return api.eth.BlockChain().ExportVaultState(block.Header())
+}
+
 // Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
 func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	if preimage := rawdb.ReadPreimage(api.eth.ChainDb(), hash); preimage != nil {
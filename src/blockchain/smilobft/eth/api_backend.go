@@ -123,14 +123,23 @@ func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (type
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
 
+func (b *EthAPIBackend) GetVaultReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.eth.blockchain.GetVaultReceiptsByHash(hash), nil
+}
+
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
 	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
 	if receipts == nil {
 		return nil, nil
 	}
+	vaultReceipts := b.eth.blockchain.GetVaultReceiptsByHash(hash)
+
 	logs := make([][]*types.Log, len(receipts))
 	for i, receipt := range receipts {
 		logs[i] = receipt.Logs
+		if vaultReceipt := core.GetVaultReceipt(vaultReceipts, receipt.TxHash); vaultReceipt != nil {
+			logs[i] = append(logs[i], vaultReceipt.Logs...)
+		}
 	}
 	return logs, nil
 }
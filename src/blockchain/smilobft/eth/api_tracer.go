@@ -42,6 +42,7 @@ import (
 	"go-didux/src/blockchain/smilobft/core/vm"
 	"go-didux/src/blockchain/smilobft/internal/ethapi"
 	"go-didux/src/blockchain/smilobft/trie"
+	"go-didux/src/blockchain/smilobft/vault"
 
 	"go-didux/src/blockchain/smilobft/eth/tracers"
 )
@@ -499,7 +500,8 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 				msg, _ := txs[task.index].AsMessage(signer)
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 
-				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, task.vaultStateDb, config)
+				vaultStateDb := api.vaultStateOf(txs[task.index], task.statedb, task.vaultStateDb)
+				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, vaultStateDb, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{Error: err.Error()}
 					continue
@@ -522,7 +524,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		msg, _ := tx.AsMessage(signer)
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 
-		vmenv := vm.NewEVM(vmctx, statedb, vaultStateDb, api.eth.blockchain.Config(), vm.Config{})
+		vmenv := vm.NewEVM(vmctx, statedb, api.vaultStateOf(tx, statedb, vaultStateDb), api.eth.blockchain.Config(), vm.Config{})
 		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
 			failed = err
 			break
@@ -749,6 +751,30 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	return api.traceTx(ctx, msg, vmctx, statedb, vaultStateDb, config)
 }
 
+// TraceVaultTransaction traces a vault transaction against both the public and
+// the vault state. The payload is fetched from the local vault, so the call only
+// succeeds on nodes that are a party to the transaction.
+func (api *PrivateDebugAPI) TraceVaultTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, _, _, _ := rawdb.ReadTransaction(api.eth.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %x not found", hash)
+	}
+	if !tx.IsVault() {
+		return nil, fmt.Errorf("transaction %x is not a vault transaction", hash)
+	}
+	if vault.VaultInstance == nil {
+		return nil, errors.New("vault is not configured")
+	}
+	data, err := vault.VaultInstance.Get(tx.Data())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vault payload: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("not a participant of vault transaction %x", hash)
+	}
+	return api.TraceTransaction(ctx, hash, config)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
@@ -833,16 +859,26 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 		msg, _ := tx.AsMessage(signer)
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 		if idx == txIndex {
-			return msg, context, statedb, vaultStateDb, nil
+			return msg, context, statedb, api.vaultStateOf(tx, statedb, vaultStateDb), nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
-		vmenv := vm.NewEVM(context, statedb, vaultStateDb, api.eth.blockchain.Config(), vm.Config{})
+		vmenv := vm.NewEVM(context, statedb, api.vaultStateOf(tx, statedb, vaultStateDb), api.eth.blockchain.Config(), vm.Config{})
 		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
 			return nil, vm.Context{}, nil, nil, fmt.Errorf("transaction %x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+		vaultStateDb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
 	}
 	return nil, vm.Context{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %x", txIndex, blockHash)
 }
+
+// vaultStateOf returns the state the vault frames of tx execute against. As in
+// core.ApplyTransaction, public transactions only ever touch the public state.
+func (api *PrivateDebugAPI) vaultStateOf(tx *types.Transaction, statedb, vaultStateDb *state.StateDB) *state.StateDB {
+	if !api.eth.blockchain.Config().IsSmilo || !tx.IsVault() {
+		return statedb
+	}
+	return vaultStateDb
+}
@@ -58,6 +58,7 @@ import (
 	"go-didux/src/blockchain/smilobft/node"
 	"go-didux/src/blockchain/smilobft/p2p"
 	"go-didux/src/blockchain/smilobft/params"
+	"go-didux/src/blockchain/smilobft/vault"
 )
 
 type LesServer interface {
@@ -87,6 +88,7 @@ type Smilo struct {
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
+	vaultDb ethdb.Database // Payload database of the in-process vault, if used
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
@@ -164,6 +166,19 @@ func New(ctx *node.ServiceContext, config *Config) (*Smilo, error) {
 		}
 	}
 
+	// Select the private transaction manager, the in-process one keeps its
+	// payloads next to the chain data
+	var vaultDb ethdb.Database
+	if config.Vault.Driver == vault.LocalDriver && config.Vault.Database == nil {
+		if vaultDb, err = ctx.OpenDatabase("vault", 16, 16, "eth/db/vault/"); err != nil {
+			return nil, err
+		}
+		config.Vault.Database = vaultDb
+	}
+	if err := vault.Setup(&config.Vault); err != nil {
+		return nil, err
+	}
+
 	if !core.GetIsSmiloEIP155Activated(chainDb) && chainConfig.ChainID != nil {
 		//Upon starting the node, write the flag to disallow changing ChainID/EIP155 block after HF
 		core.WriteSmiloEIP155Activation(chainDb)
@@ -172,6 +187,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Smilo, error) {
 	eth := &Smilo{
 		config:         config,
 		chainDb:        chainDb,
+		vaultDb:        vaultDb,
 		chainConfig:    chainConfig,
 		eventMux:       ctx.EventMux,
 		accountManager: ctx.AccountManager,
@@ -296,6 +312,9 @@ func CreateConsensusEngine(ctx *node.ServiceContext, config *Config, chainConfig
 		if chainConfig.Sport.MinFunds != 0 {
 			config.Sport.MinFunds = chainConfig.Sport.MinFunds
 		}
+		if config.Sport.WAL == "" && !config.Sport.NoWAL {
+			config.Sport.WAL = ctx.ResolvePath("sportwal")
+		}
 
 		return smiloBackend.New(&config.Sport, ctx.NodeKey(), db)
 	}
@@ -582,6 +601,7 @@ func (s *Smilo) Protocols() []p2p.Protocol {
 // Smilo protocol implementation.
 func (s *Smilo) Start(srvr *p2p.Server) error {
 	s.startEthEntryUpdate(srvr.LocalNode())
+	s.startPermissionUpdate(srvr)
 
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers(params.BloomBitsBlocks)
@@ -620,6 +640,9 @@ func (s *Smilo) Stop() error {
 	s.eventMux.Stop()
 
 	s.chainDb.Close()
+	if s.vaultDb != nil {
+		s.vaultDb.Close()
+	}
 	close(s.shutdownChan)
 	return nil
 }
@@ -35,6 +35,7 @@ import (
 	"go-didux/src/blockchain/smilobft/eth/downloader"
 	"go-didux/src/blockchain/smilobft/eth/gasprice"
 	"go-didux/src/blockchain/smilobft/params"
+	"go-didux/src/blockchain/smilobft/vault"
 )
 
 // DefaultConfig contains default settings for use on the Smilo main net.
@@ -147,6 +148,9 @@ type Config struct {
 	// Sport options
 	Sport sport.Config
 
+	// Private transaction manager options
+	Vault vault.Config
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
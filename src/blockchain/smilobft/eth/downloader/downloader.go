@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 
 	"go-didux/src/blockchain/smilobft"
+	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/core/rawdb"
 	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/ethdb"
@@ -1567,6 +1568,10 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 			// of the blocks delivered from the downloader, and the indexing will be off.
 			log.Debug("Downloaded item processing failed on sidechain import", "index", index, "err", err)
 		}
+		if err == core.ErrVaultUnavailable {
+			// The blocks weren't found invalid, keep the peer and retry later
+			return err
+		}
 		return errInvalidChain
 	}
 	return nil
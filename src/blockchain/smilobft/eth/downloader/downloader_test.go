@@ -33,6 +33,7 @@ import (
 	"strings"
 
 	"go-didux/src/blockchain/smilobft"
+	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/ethdb"
 	"go-didux/src/blockchain/smilobft/trie"
@@ -1120,6 +1121,7 @@ func testBlockHeaderAttackerDropping(t *testing.T, protocol int) {
 		{errInvalidBody, false},             // A bad peer was detected, but not the sync origin
 		{errInvalidReceipt, false},          // A bad peer was detected, but not the sync origin
 		{errCancelContentProcessing, false}, // Synchronisation was canceled, origin may be innocent, don't drop
+		{core.ErrVaultUnavailable, false},   // The local vault is down, the chain may be valid, don't drop
 	}
 	// Run the tests and check disconnection status
 	tester := newTester()
@@ -17,6 +17,7 @@ import (
 	"go-didux/src/blockchain/smilobft/core"
 	"go-didux/src/blockchain/smilobft/eth/downloader"
 	"go-didux/src/blockchain/smilobft/eth/gasprice"
+	"go-didux/src/blockchain/smilobft/vault"
 )
 
 // MarshalTOML marshals as TOML.
@@ -48,6 +49,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
 		Sport                   sport.Config
+		Vault                   vault.Config
 		DocRoot                 string `toml:"-"`
 		EWASMInterpreter        string
 		EVMInterpreter          string
@@ -82,6 +84,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.Sport = c.Sport
+	enc.Vault = c.Vault
 	enc.DocRoot = c.DocRoot
 	enc.EWASMInterpreter = c.EWASMInterpreter
 	enc.EVMInterpreter = c.EVMInterpreter
@@ -120,6 +123,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
 		Sport                   *sport.Config
+		Vault                   *vault.Config
 		DocRoot                 *string `toml:"-"`
 		EWASMInterpreter        *string
 		EVMInterpreter          *string
@@ -209,6 +213,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.Sport != nil {
 		c.Sport = *dec.Sport
 	}
+	if dec.Vault != nil {
+		c.Vault = *dec.Vault
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
@@ -0,0 +1,84 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"go-didux/src/blockchain/smilobft/contracts/permissions"
+	"go-didux/src/blockchain/smilobft/core"
+	"go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/p2p"
+	"go-didux/src/blockchain/smilobft/p2p/enode"
+)
+
+// startPermissionUpdate admits peers according to the permissions contract of the
+// chain, if any, and drops those losing their permission at every new block.
+func (eth *Smilo) startPermissionUpdate(srvr *p2p.Server) {
+	contract := eth.chainConfig.PermissionsAddress()
+	if contract == (common.Address{}) {
+		return
+	}
+	reader := &permissionReader{chain: eth.blockchain, contract: contract}
+	srvr.SetPermissionFilter(reader.nodePermitted)
+
+	var newHead = make(chan core.ChainHeadEvent, 10)
+	sub := eth.blockchain.SubscribeChainHeadEvent(newHead)
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-newHead:
+				srvr.DropUnpermissioned()
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+}
+
+// permissionReader reads the permissions contract at the head of the chain. The
+// head state is opened once per head and shared by all the peer checks.
+type permissionReader struct {
+	chain    *core.BlockChain
+	contract common.Address
+
+	root  common.Hash    // State root of the head the state was opened at
+	state *state.StateDB // Head state, not safe for concurrent reads
+	lock  sync.Mutex
+}
+
+// nodePermitted returns whether the permissions contract allows the node to
+// connect at the head of the chain.
+func (r *permissionReader) nodePermitted(id enode.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if root := r.chain.CurrentBlock().Root(); r.state == nil || root != r.root {
+		statedb, err := state.New(root, r.chain.StateCache())
+		if err != nil {
+			log.Warn("Failed to read node permissions", "id", id, "err", err)
+			return false
+		}
+		r.root, r.state = root, statedb
+	}
+	return permissions.NodePermitted(r.state, r.contract, id)
+}
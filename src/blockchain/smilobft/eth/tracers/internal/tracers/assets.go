@@ -2,11 +2,11 @@
 // sources:
 // 4byte_tracer.js (2.933kB)
 // bigram_tracer.js (1.712kB)
-// call_tracer.js (8.643kB)
+// call_tracer.js (8.851kB)
 // evmdis_tracer.js (4.194kB)
 // noop_tracer.js (1.271kB)
 // opcount_tracer.js (1.372kB)
-// prestate_tracer.js (4.234kB)
+// prestate_tracer.js (4.303kB)
 // trigram_tracer.js (1.788kB)
 // unigram_tracer.js (1.51kB)
 
@@ -117,7 +117,7 @@ func bigram_tracerJs() (*asset, error) {
 	return a, nil
 }
 
-var _call_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x59\x5f\x6f\x1b\xb7\xb2\x7f\x96\x3e\xc5\x24\x0f\xb5\x84\x28\x92\x93\xf4\xf6\x02\x76\xd5\x0b\x5d\x47\x49\x0d\xb8\x71\x60\x2b\x0d\x82\x20\x0f\xd4\xee\xac\xc4\x9a\x4b\x6e\x49\xae\xe4\x3d\xa9\xbf\xfb\xc1\x0c\xb9\xab\xd5\x1f\x3b\x6e\x0f\xce\x41\xcf\x8b\xa0\x5d\xce\x0c\x87\x33\xbf\xf9\xc7\x1d\x8d\xe0\xcc\x14\x95\x95\x8b\xa5\x87\x97\xc7\x2f\xfe\x17\x66\x4b\x84\x85\x79\x8e\x7e\x89\x16\xcb\x1c\x26\xa5\x5f\x1a\xeb\xba\xa3\x11\xcc\x96\xd2\x41\x26\x15\x82\x74\x50\x08\xeb\xc1\x64\xe0\x77\xe8\x95\x9c\x5b\x61\xab\x61\x77\x34\x0a\x3c\x07\x97\x49\x42\x66\x11\xc1\x99\xcc\xaf\x85\xc5\x13\xa8\x4c\x09\x89\xd0\x60\x31\x95\xce\x5b\x39\x2f\x3d\x82\xf4\x20\x74\x3a\x32\x16\x72\x93\xca\xac\x22\x91\xd2\x43\xa9\x53\xb4\xbc\xb5\x47\x9b\xbb\x5a\x8f\xb7\xef\x3e\xc0\x05\x3a\x87\x16\xde\xa2\x46\x2b\x14\xbc\x2f\xe7\x4a\x26\x70\x21\x13\xd4\x0e\x41\x38\x28\xe8\x8d\x5b\x62\x0a\x73\x16\x47\x8c\x6f\x48\x95\xeb\xa8\x0a\xbc\x31\xa5\x4e\x85\x97\x46\x0f\x00\x25\x69\x0e\x2b\xb4\x4e\x1a\x0d\xaf\xea\xad\xa2\xc0\x01\x18\x4b\x42\x7a\xc2\xd3\x01\x2c\x98\x82\xf8\xfa\x20\x74\x05\x4a\xf8\x0d\xeb\x23\x0c\xb2\x39\x77\x0a\x52\xf3\x36\x4b\x53\x20\xf8\xa5\xf0\x74\xea\xb5\x54\x0a\xe6\x08\xa5\xc3\xac\x54\x03\x92\x36\x2f\x3d\x7c\x3c\x9f\xfd\x7c\xf9\x61\x06\x93\x77\x9f\xe0\xe3\xe4\xea\x6a\xf2\x6e\xf6\xe9\x14\xd6\xd2\x2f\x4d\xe9\x01\x57\x18\x44\xc9\xbc\x50\x12\x53\x58\x0b\x6b\x85\xf6\x15\x98\x8c\x24\xfc\x32\xbd\x3a\xfb\x79\xf2\x6e\x36\xf9\xff\xf3\x8b\xf3\xd9\x27\x30\x16\xde\x9c\xcf\xde\x4d\xaf\xaf\xe1\xcd\xe5\x15\x4c\xe0\xfd\xe4\x6a\x76\x7e\xf6\xe1\x62\x72\x05\xef\x3f\x5c\xbd\xbf\xbc\x9e\x0e\xe1\x1a\x49\x2b\x24\xfe\x6f\xdb\x3c\x63\xef\x59\x84\x14\xbd\x90\xca\xd5\x96\xf8\x64\x4a\x70\x4b\x53\xaa\x14\x96\x62\x85\x60\x31\x41\xb9\xc2\x14\x04\x24\xa6\xa8\x1e\xed\x54\x92\x25\x94\xd1\x0b\x3e\xf3\xbd\x80\x84\xf3\x0c\xb4\xf1\x03\x70\x88\xf0\xe3\xd2\xfb\xe2\x64\x34\x5a\xaf\xd7\xc3\x85\x2e\x87\xc6\x2e\x46\x2a\x88\x73\xa3\x9f\x86\x5d\x92\x99\x08\xa5\x66\x56\x24\x68\xc9\x39\x02\xb2\x92\xcc\xaf\xcc\x5a\x83\xb7\x42\x3b\x91\x90\xab\xe9\x7f\xc2\x60\x14\x1e\xf0\x96\x9e\xbc\x23\xd0\x82\xc5\xc2\x58\xfa\xaf\x54\x8d\x33\xa9\x3d\x5a\x2d\x14\xcb\x76\x90\x8b\x14\x61\x5e\x81\x68\x0b\x1c\xb4\x0f\x43\x30\x0a\xee\x06\xa9\x33\x63\x73\x86\xe5\xb0\xfb\xb5\xdb\x89\x1a\x3a\x2f\x92\x1b\x52\x90\xe4\x27\xa5\xb5\xa8\x3d\x99\xb2\xb4\x4e\xae\x90\x49\x20\xd0\x44\x7b\x4e\x7f\xfd\x05\xf0\x16\x93\x32\x48\xea\x34\x42\x4e\xe0\xf3\xd7\xbb\x2f\x83\x2e\x8b\x4e\xd1\x25\xa8\x53\x4c\xf9\x7c\x37\x0e\xd6\x4b\xb6\x28\xac\xf1\x68\x85\xf0\x5b\xe9\x7c\x8b\x26\xb3\x26\x07\xa1\xc1\x94\x84\xf8\xb6\x75\xa4\xf6\x86\x05\x0a\xfa\xaf\xd1\xb2\x46\xc3\x6e\xa7\x61\x3e\x81\x4c\x28\x87\x71\x5f\xe7\xb1\xa0\xd3\x48\xbd\x32\x37\x24\xd9\x58\x82\xb0\xad\xc0\x14\x89\x49\x63\x30\xd0\x39\x9a\x63\xa0\x1b\x76\x3b\xc4\x77\x02\x59\xa9\x79\xdb\x9e\x32\x8b\x01\xa4\xf3\x3e\x7c\xed\x76\x48\xec\x99\x28\x7c\x69\x91\xed\x89\xd6\x1a\xeb\x40\xe6\x39\xa6\x52\x78\x54\x55\xb7\xd3\x59\x09\x1b\x16\x60\x0c\xca\x2c\x86\x0b\xf4\x53\x7a\xec\xf5\x4f\xbb\x9d\x8e\xcc\xa0\x17\x56\x9f\x8c\xc7\x9c\x7d\x32\xa9\x31\x0d\xe2\x3b\x7e\x29\xdd\x30\x13\xa5\xf2\xcd\xbe\xc4\xd4\xb1\xe8\x4b\xab\xe9\xef\x5d\xd0\xe2\x23\x82\xd1\xaa\x82\x84\xb2\x8c\x98\x53\x78\xba\xca\x79\xcc\xe3\xe1\xdc\x00\x32\xe1\xc8\x84\x32\x83\x35\x42\x61\xf1\x79\xb2\x44\xf2\x9d\x4e\x30\x6a\xe9\x2a\xc7\x4e\x1d\x03\xed\x36\x34\xc5\xd0\x9b\x77\x65\x3e\x47\xdb\xeb\xc3\x77\x70\x7c\x9b\x1d\xf7\x61\x3c\xe6\x3f\xb5\xee\x91\x27\xea\x4b\x52\x4c\x11\x0f\xca\xfc\xd7\xde\x4a\xbd\x08\x67\x8d\xba\x9e\x67\x20\x40\xe3\x1a\x12\xa3\x19\xd4\xe4\x95\x39\x4a\xbd\x80\xc4\xa2\xf0\x98\x0e\x40\xa4\x29\x78\x13\x90\xd7\xe0\x6c\x7b\x4b\xf8\xee\x3b\xe8\xd1\x66\x63\x38\x3a\xbb\x9a\x4e\x66\xd3\x23\xf8\xe3\x0f\x08\x6f\x9e\x86\x37\x2f\x9f\xf6\x5b\x9a\x49\x7d\x99\x65\x51\x39\x16\x38\x2c\x10\x6f\x7a\x2f\xfa\xc3\x95\x50\x25\x5e\x66\x41\xcd\x48\x3b\xd5\x29\x8c\x23\xcf\xb3\x5d\x9e\x97\x5b\x3c\xc4\x34\x1a\xc1\xc4\x39\xcc\xe7\x0a\xf7\x03\x32\x46\x2c\x07\xaf\xf3\x94\xb1\x08\x7d\x89\xc9\x0b\x85\x84\xaa\x7a\xd7\x68\x7e\xd6\xb8\xe3\xab\x02\x4f\x00\x00\x4c\x31\xe0\x17\x14\x0b\xfc\xc2\x9b\x9f\xf1\x96\x7d\x54\x9b\x90\x50\x35\x49\x53\x8b\xce\xf5\xfa\xfd\x40\x2e\x75\x51\xfa\x93\x2d\xf2\x1c\x73\x63\xab\xa1\xa3\x84\xd4\xe3\xa3\x0d\xc2\x49\x6b\x9e\x85\x70\xe7\x9a\x78\x22\x52\xdf\x0a\xd7\xdb\x2c\x9d\x19\xe7\x4f\xea\x25\x7a\xa8\xd7\xd8\x16\xc4\x76\x74\x7c\x7b\xb4\x6f\xad\xe3\xfe\x06\x09\x2f\x7e\xe8\x13\xcb\xdd\x69\x83\xef\x26\x4d\x0c\x8b\xd2\x2d\x7b\x0c\xa7\xcd\xea\x26\x15\x8c\xc1\xdb\x12\x0f\xc2\x9f\x21\xb5\x0f\x27\x87\x2a\xa3\x5c\xe2\x6d\x99\x30\xac\x16\x82\x33\x0d\x47\xba\xa0\xcc\xeb\xca\x39\xdb\xdc\x1b\xb3\x8f\xae\x08\xae\xeb\xe9\xc5\x9b\xd7\xd3\xeb\xd9\xd5\x87\xb3\xd9\x51\x0b\x4e\x0a\x33\x4f\x4a\x6d\x9f\x41\xa1\x5e\xf8\x25\xeb\x4f\xe2\xb6\x57\x3f\x13\xcf\xf3\x17\x5f\xc2\x1b\x18\x1f\x08\xf9\xce\xc3\x1c\xf0\xf9\x0b\xcb\xbe\xdb\x37\xdf\x36\x69\x30\xe6\xd7\x00\x22\x53\xdc\xb5\x13\xc7\x81\x58\xcc\xd1\x2f\x4d\xca\xc9\x31\x11\x21\xbf\xd6\x56\x4c\x8d\xc6\x3f\x1f\x91\x93\x8b\x8b\x56\x3c\xf2\xf3\xd9\xe5\xeb\x76\x8c\x1e\xbd\x9e\x5e\x4c\xdf\x4e\x66\xd3\x5d\xda\xeb\xd9\x64\x76\x7e\xc6\x6f\xeb\xf0\x1d\x8d\xe0\xfa\x46\x16\x9c\x65\x39\x77\x99\xbc\xe0\x76\xb1\xd1\xd7\x0d\xc0\x2f\x0d\x35\x62\x36\x16\x91\x4c\xe8\xa4\x4e\xee\xae\x76\x9a\x37\xe4\x32\x53\xc7\xca\x7e\x2a\x68\x03\xb5\xdf\xb8\x51\xba\xf7\x16\xe3\xa6\x69\xcf\x9b\x5a\xaf\x8d\x41\x83\x47\x38\x01\x72\x92\xe9\x3d\xfe\x90\xf0\x7f\x70\x0c\x27\xf0\x22\x66\x92\x07\x52\xd5\x4b\x78\x46\xe2\xff\x42\xc2\x7a\x75\x80\xf3\xef\x99\xb6\xbc\x61\xe2\x9a\xdc\x9b\xff\x7c\x3a\x33\xa5\xbf\xcc\xb2\x13\xd8\x35\xe2\xf7\x7b\x46\x6c\xe8\x2f\x50\xef\xd3\xff\xcf\x1e\xfd\x26\xf5\x11\xaa\x4c\x01\x4f\xf6\x20\x12\x12\xcf\x93\x9d\x38\x88\xc6\xe5\x16\x87\xa5\xc1\xf8\x9e\x64\xfb\x72\x1b\xc3\xf7\x65\x8b\x7f\x29\xd9\x1e\x6c\xd5\xa8\x21\xdb\x6e\xc6\x06\x60\xd1\x5b\x89\x2b\x1a\xb7\x8e\x1c\x8b\xa4\xa6\xd5\xac\x85\x4e\x70\x08\x1f\x31\x48\xd4\x88\x9c\x5c\x62\x93\x4b\x3d\x0a\xf7\x7d\xd4\xa8\xc6\x71\x85\x21\x26\xb8\x17\xb5\x08\xb9\xa8\x68\x5c\xc9\x4a\x7d\x53\xc1\x42\x38\x48\x2b\x2d\x72\x99\xb8\x20\x8f\x1b\x5c\x8b\x0b\x61\x59\xac\xc5\xdf\x4b\x74\x34\xfb\x10\x90\x45\xe2\x4b\xa1\x54\x05\x0b\x49\x03\x0c\x71\xf7\x5e\xbe\x3a\x3e\x06\xe7\x65\x81\x3a\x1d\xc0\x0f\xaf\x46\x3f\x7c\x0f\xb6\x54\xd8\x1f\x76\x5b\x69\xbc\x39\x6a\xf4\x06\x2d\x44\xf4\xbc\xc6\xc2\x2f\x7b\x7d\xf8\xe9\x9e\x7a\x70\x4f\x72\x3f\x48\x0b\xcf\xe1\xc5\x97\x21\xe9\x35\xde\xc2\x6d\xf0\x24\xa0\x72\x18\xa5\xd1\xd0\x77\xf9\xfa\xb2\x77\x23\xac\x50\x62\x8e\xfd\x13\x1e\x02\xd9\x56\x6b\x11\xa7\x00\x72\x0a\x14\x4a\x48\x0d\x22\x49\x4c\xa9\x3d\x19\xbe\x6e\xe8\x55\x45\xf9\xfd\xc8\xd7\xf2\x78\x5e\x12\x49\x82\xce\xd5\xe9\x9e\xbd\x46\xea\x88\x9c\xb8\x41\x6a\x27\x53\x6c\x79\x85\xb2\x83\xe1\xd4\x1c\x29\x68\x9c\xac\x05\xe6\xc6\xd1\x26\x73\x84\xb5\xa5\xe1\xc3\x49\x9d\xf0\xf4\x9d\x22\x59\xdb\x81\xd1\x20\x40\x19\x1e\xf9\x39\xc6\x41\xd8\x85\x1b\x86\x7c\x4f\xdb\x52\xce\xd1\x66\x3d\xdc\x06\x72\x1b\xaa\xdc\xe6\xef\xb4\x03\x1a\xf0\x56\x3a\xcf\x5d\x25\x69\x29\x1d\x04\x24\x4b\xbd\x18\x40\x61\x0a\xce\xd3\xdf\x2a\x67\x31\x59\x5f\x4d\x7f\x9d\x5e\x35\xc5\xff\xf1\x4e\xac\xfb\xfe\xa7\xcd\x58\x04\x96\x66\x0e\x8f\xe9\xd3\x03\x8d\xfc\x01\x40\x8d\xef\x01\x14\xc9\xdf\xd4\xc6\xf7\xad\xe3\x28\xe1\xfc\xc6\x31\x0b\x0c\x33\x4d\x5b\x01\x57\x2a\xef\x76\x72\xf7\x6e\x72\x30\x45\x5d\x21\x48\x29\x4e\x3b\x94\xd8\x77\xbb\xed\xad\x85\x4d\xd3\xbd\xc1\xe7\x79\xcb\xc6\x6b\x6e\xb9\x02\x51\x2b\x35\xf0\x7a\xdd\xbb\x89\x50\x0d\x58\x77\x53\x7a\x82\x03\xd5\xef\x4d\xf2\x5b\x08\xf7\xc1\xb1\xd7\x63\xfa\x9b\xcb\xc5\xb9\xf6\xbd\x7a\xf1\x5c\xc3\x73\xa8\x1f\x28\xa9\xc3\xf3\xad\x28\x3a\x90\x1d\x3b\x29\x2a\xf4\x08\x1b\x11\xa7\xb0\xf3\x8a\x04\x05\x73\xb0\xd1\x2c\xfa\xfd\xe2\x7c\x1c\xa5\x91\xc1\x9e\x58\xf4\x43\xfc\xbd\x14\xca\xf5\x8e\x9b\x66\x21\x9c\xc0\x1b\x2e\x6f\xe3\xa6\xc0\xd5\x15\x90\x78\xb6\xda\x8f\x28\x30\xb0\x45\x6b\xd4\x6c\xe9\x3c\x54\xad\x14\x1f\x94\x10\x45\xc4\xb4\xd1\xf8\x32\x02\xf3\x50\xff\xd9\x69\x13\xc0\xd3\xa6\x21\xc8\x84\x54\xa5\xc5\xa7\xa7\x70\x20\xed\xb8\xd2\x66\x22\x61\x5f\x3a\x04\x9e\x58\x1d\x38\x93\xe3\xd2\xac\x83\x02\x87\x92\xd7\x3e\x38\x1a\x1c\xec\x94\x0f\xbe\x7a\x11\x0e\x4a\x27\x16\xd8\x02\x47\x63\xf0\xda\x51\x07\xc7\xe8\xbf\x0c\x9d\x67\xcd\xe3\x37\x50\x14\x76\xf9\x26\x34\x1e\xc2\xc6\x41\x2f\xef\x75\x39\x35\x11\xf7\x3a\xad\x87\x5a\xd5\xd0\x8a\x34\xc8\xf9\x33\x7e\xff\xf7\x38\x3e\x78\x3e\xfe\x3e\x36\xd0\x76\x69\xc3\x19\xb7\x89\xc3\x49\x37\xed\xcd\xb7\x51\xd0\xac\xde\x07\x80\xfb\x3a\x27\x82\xaa\xfe\x0d\x13\xbf\x81\x2b\x37\x3b\xf4\x54\x58\x5c\x49\x53\x52\x1d\xc3\xff\xa6\xc9\xb0\xe9\xfc\xee\xba\x9d\xbb\x78\x45\xc6\xee\x6b\xdf\x91\xad\x97\xf1\x8a\x37\x34\x4d\xad\x2a\x62\xb8\xc4\xc6\x9b\xb3\x2c\x5c\xbe\x76\x98\xff\x81\xbb\xb2\x18\xef\xde\x14\xd4\x15\xc4\x22\xa5\x2c\x8a\xb4\x6a\xea\xe2\x20\xf4\x23\xb0\x14\x3a\x8d\x33\x89\x48\x53\x49\xf2\x18\x8b\xa4\xa1\x58\x08\xa9\xbb\x07\xcd\xf8\xcd\x62\x7c\x08\x19\x7b\x2d\x6e\xbb\x9e\xc6\x59\x92\x06\x3f\xd6\xb8\xfb\x88\xba\xb9\x13\x4b\xbb\xd7\x7e\xf1\xe6\xd0\x68\x57\xe6\xdc\x10\x83\x58\x09\xa9\x04\x0d\x61\xdc\x68\xe9\x14\x12\x85\x42\x87\xcb\x7e\xcc\xbc\x59\xa1\x75\xdd\x47\x80\xfc\xaf\x60\x7c\x27\x39\xd6\x8f\xd1\x1c\x8f\x8f\xd9\xc7\x46\x6c\x38\xfe\x1b\x25\xbc\x8f\xf0\x6a\x99\x37\x44\x96\xf4\xfc\x1d\x08\xb5\xef\x3e\x2e\xa4\xb8\x75\x22\x9a\x9f\xe0\xb8\xd5\x9e\xff\x5d\x82\x6c\x1f\x62\x17\x4d\x9b\x16\x0f\xef\x8d\x19\x80\x42\xc1\xc3\x52\xfd\x95\xa6\x6e\x4b\x1f\x9a\xdd\xea\xe8\x0d\x8d\xdd\x5e\xf8\xf2\xf5\xd6\x12\xeb\x8b\x90\xd0\xe1\xcf\x11\x35\x48\x8f\x56\xd0\x58\x44\xe8\x8a\x1f\x16\x48\x4b\xc7\xe2\xd8\x2f\x92\x82\x2e\x0a\x8e\xb7\xfc\x54\x9f\xa5\x5e\x0c\xbb\x9d\xf0\xbe\x15\xef\x89\xbf\xdd\xc4\x7b\x28\x86\xcc\x19\xaf\x06\x9a\x9b\x81\xc4\xdf\x72\xd3\xc8\xd3\xf3\xce\xf5\x00\xad\xd1\xab\x30\x5a\xef\x5c\x06\x30\x63\xbc\x10\xd8\xbd\x73\xa4\x35\x7e\xb7\x05\x70\x26\x5d\x08\x17\xc4\xec\x84\x84\xbf\xdd\x8f\x88\x9a\x81\x82\xe1\xe4\x30\x03\x2d\x1d\x60\xda\xb9\xa0\x20\x62\x7e\x15\x56\x43\x61\x3f\x69\xaf\x86\x57\xf1\xa0\x32\x6f\xd9\x46\xe6\x6c\x9b\xbb\xd3\xc3\x49\xee\xb8\xc6\xe3\xe1\x64\x46\x36\x6f\x00\x7b\x0f\x6b\x7b\xe4\xd8\x27\x79\x28\x55\xb2\xf4\x3a\xb3\xdd\xc3\xca\xd2\x5b\xad\x87\xbf\x7d\xbc\xc8\x86\xb8\xad\xe2\x16\xcd\x21\x21\x31\xcf\x44\xba\x60\xd9\x5a\x40\x40\x75\xd0\x95\x11\x2d\xff\x81\x51\x62\x3b\x7e\xea\x25\xb0\x18\xbe\x43\x70\x43\x4a\xe1\x63\xe6\x5c\xfc\x4b\x47\xd3\xe4\x26\x2e\x52\x74\xd2\x62\x0a\x99\x44\x95\x82\x49\xd1\xf2\xac\xfa\x9b\x33\x3a\x7c\x71\x42\x2b\x49\x62\xf8\xb2\x16\x3e\x72\xf3\xf7\x3e\x2d\x13\xf4\x15\x64\x28\xf8\xd3\x91\x37\x50\x08\xe7\x20\x47\x41\xd3\x69\x56\x2a\x55\x81\xb1\x29\x92\xf0\x66\x5c\xa3\x90\x34\x50\x3a\xb4\x0e\xd6\x4b\x13\xcb\x24\x77\x69\x05\x35\x9d\xd2\x0f\xe2\x8d\x8c\x74\x85\x12\x15\x48\x4f\x25\x39\x1e\xaa\x1d\xa5\xcd\xf7\x1a\xfe\xe8\x63\xa8\xea\xee\x87\x68\x3d\xd8\x6d\xc7\x28\xbf\xa6\xa7\xed\xe8\x8c\x73\xcd\x76\x5c\x6e\xee\xaa\xb6\x83\xb0\x2e\x1b\xdb\x91\xd6\x2e\x42\xdb\xe1\xc4\x2b\xfc\xb4\x1d\x48\xad\x7e\x99\x17\x18\x1c\x0d\x03\x3f\xed\x84\x16\x6b\x19\x63\x2b\x7c\x9d\x6c\xc8\xf9\x69\x10\x01\x43\x5e\xec\x91\x71\x6e\xb0\xa2\x4c\x1c\x6c\xd4\x2a\x2b\xe1\xc5\xe7\x1b\xac\xbe\x1c\xae\x22\x11\x8e\x2d\xba\xa6\x6c\xd4\x90\x0e\x6b\x0f\x04\x72\xa3\x85\x1c\x1f\x9f\x82\xfc\xb1\xcd\x50\x57\x3e\x90\xcf\x9e\xd5\x7b\xb6\xd7\x3f\xcb\x2f\x75\x74\x36\x88\xdf\x59\xef\x6f\x69\x14\x63\x24\xd0\x50\x50\x74\xef\xba\xff\x0c\x00\x00\xff\xff\x00\x24\x55\x1f\xc3\x21\x00\x00")
+var _call_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd5\x59\x5b\x73\x13\x3b\x12\x7e\x8e\x7f\x85\xc8\x03\xb1\x0b\xe3\x04\x38\xcb\x56\x25\x27\x6c\x79\x83\x03\xa9\xca\x21\x54\x2e\x50\x14\xc5\x83\x3c\x23\xdb\x3a\x19\x8f\xe6\x8c\x34\x31\x5e\x4e\xfe\xfb\x7e\xdd\x92\xc6\xe3\x4b\x42\x60\x6b\xb7\xce\xe6\x25\x1e\xa9\xbb\xd5\x6a\x7d\x7d\x93\x76\x77\xc5\x91\x29\xe6\xa5\x1e\x4f\x9c\x78\xbe\xf7\xec\xef\xe2\x72\xa2\xc4\xd8\x3c\x55\x6e\xa2\x4a\x55\x4d\x45\xbf\x72\x13\x53\xda\xd6\xee\x2e\xa6\xb4\x15\x23\x9d\x29\x81\xff\x85\x2c\x9d\x30\x23\xe1\x56\xe8\x33\x3d\x2c\x65\x39\xef\x81\xc1\xf3\x6c\x9c\x26\x09\xa3\x52\x29\x61\xcd\xc8\xcd\x64\xa9\xf6\xc5\xdc\x54\x22\x91\xb9\x28\x55\xaa\xad\x2b\xf5\xb0\x72\x58\xc8\x09\x99\xa7\xbb\xa6\x14\x53\x93\xea\xd1\x9c\x44\x62\xac\xca\x53\x55\xf2\xd2\x4e\x95\x53\x1b\xf5\x78\xf3\xee\x4a\x9c\x2a\x6b\x31\xf7\x46\xe5\xaa\x94\x99\x78\x5f\x0d\x33\x9d\x88\x53\x9d\xa8\xdc\x2a\x21\xa1\x38\x8d\xd8\x89\x4a\xc5\x90\xc5\x11\xe3\x31\xa9\x72\x11\x54\x11\xc7\x06\xf2\xa5\xd3\x26\xef\x0a\xa5\x49\x73\x71\xa3\x4a\x8b\x6f\xf1\x22\x2e\x15\x04\x76\x85\x29\x49\x48\x5b\x3a\xda\x40\x29\x4c\x41\x7c\x1d\x68\x3d\x17\x99\x74\x0b\xd6\x07\x18\x64\xb1\xef\x54\xe8\x9c\x97\x99\x98\x02\x7b\x9c\x40\x3a\x76\x3d\xd3\x59\x26\x86\x4a\x54\x56\x8d\xaa\xac\x4b\xd2\x40\x2c\x3e\x9e\x5c\xbe\x3d\xbb\xba\x14\xfd\x77\x9f\xc4\xc7\xfe\xf9\x79\xff\xdd\xe5\xa7\x03\x10\xe3\xdc\x30\xab\x6e\x94\x17\xa5\xa7\x45\xa6\x21\x19\x5b\x2c\x65\xee\xe6\xd8\x09\x49\xf8\x6d\x70\x7e\xf4\x16\x2c\xfd\x7f\x9e\x9c\x9e\x5c\x7e\xc2\x7e\xc4\xf1\xc9\xe5\xbb\xc1\xc5\x85\x38\x3e\x3b\x17\x7d\xf1\xbe\x7f\x7e\x79\x72\x74\x75\xda\x3f\x17\xef\xaf\xce\xdf\x9f\x5d\x0c\x7a\xe2\x42\x91\x56\x8a\xf8\xbf\x6f\xf3\x11\x9f\x1e\xec\x9a\x2a\x27\x75\x66\xa3\x25\x3e\xe1\xc0\x2d\x74\xcc\x52\x31\x91\x37\x0a\x07\x9f\x28\x7d\x03\x0d\xa5\x48\x80\xc9\x07\x1f\x2a\xc9\x92\x99\xc9\xc7\xbc\xe7\x3b\x01\x29\x4e\x46\x22\x37\xae\x2b\x2c\x94\xff\x75\xe2\x5c\xb1\xbf\xbb\x3b\x9b\xcd\x7a\xe3\xbc\xea\x99\x72\xbc\x9b\x79\x71\x76\xf7\x55\xaf\x45\x32\x13\x99\x65\x97\xa5\x4c\xb0\x30\x0e\x47\x0a\xd8\x1c\xe6\xcf\xcc\x0c\xf6\x84\x05\xad\x4c\xe8\xa8\xe9\x77\xc2\x60\xc4\x21\xa9\xaf\xf4\xe5\x2c\x81\x16\xfb\x29\x4c\x49\xbf\xb3\x2c\xe2\x4c\xe7\x40\x44\x8e\x1d\x90\x6c\x2b\xa6\x32\x55\x40\x21\x64\x37\x04\x76\x9b\x9b\x21\x18\xf9\xe3\x06\x2f\x0c\x39\x65\x58\xf6\x5a\xdf\x5a\x5b\x41\x43\xeb\x64\x72\x4d\x0a\x92\xfc\xa4\x2a\x4b\x95\x3b\x32\x65\x05\xd4\xc1\xa8\x44\x22\x3c\x4d\xb0\xe7\xe0\xc3\x6f\xd0\x13\x04\x5e\xd2\x56\x2d\x64\x5f\x7c\xfe\x76\xfb\xa5\xdb\x62\xd1\xa9\xb2\xb0\x46\x8a\xd3\xa0\x1d\x5d\x5b\x31\x9b\xb0\x45\xc5\x4c\xed\x40\xec\xef\x95\x75\x0d\x9a\x51\x69\xa6\xd0\x55\x00\x70\x64\x8a\x86\x75\xb0\x63\xc3\x02\x25\xfd\xc6\xf1\xb1\x46\x58\xb6\x66\xde\x17\x23\x99\xc1\x93\xfc\xba\xd6\xa9\x82\x76\xa3\xf3\x1b\x73\x4d\x92\x01\x1e\x40\x18\x0e\x62\x8a\xc4\xa4\xc1\x19\x68\x1f\xf5\x36\x14\x10\xb5\x45\x7c\x90\x54\xe5\xbc\x6c\x3b\x33\xe3\xae\x48\x87\x1d\x01\x43\x91\xd8\x23\x59\xb8\x0a\x10\x24\x7b\xaa\xb2\x44\x40\x83\x3f\x4c\x11\x69\xe0\xa2\xd9\x1c\x34\x37\xb2\xf4\x13\xe2\x50\x80\xb9\x37\x56\x6e\x40\x9f\xed\xce\x01\x66\xf5\x48\xb4\xfd\xec\xa3\xc3\x43\x8e\x3e\x23\x9d\xab\xd4\x8b\xdf\x72\x88\x8b\xbd\x91\xac\x32\x57\xaf\x4b\x4c\x5b\xa5\xc2\x9a\x39\xfd\xbc\xf5\x5a\x7c\x54\xc2\xe4\xd9\x1c\x26\x20\x55\x86\xe4\x9e\x76\x0e\xcd\xa7\x61\x73\xb6\x0b\x5b\x58\x32\x21\x16\x9c\x29\x51\x94\xea\x69\x32\x51\x74\x76\x79\xa2\x82\x96\xe0\xe0\x43\x3d\x14\xb4\x5a\xcf\x14\x3d\x67\xde\x55\xd3\xa1\x82\xae\xe2\xb1\xd8\xfb\x3a\xda\xeb\x08\x68\x49\x3f\xa2\xee\x81\x27\xe8\x4b\x52\x4c\x11\x36\xca\xfc\x17\x88\x3b\xf9\xd8\xef\x35\xe8\x0a\x6f\x91\x22\x57\x33\xf8\x62\xce\xa0\xa6\x53\x19\x2a\x90\x89\xa4\x54\x30\x5b\x0a\xa0\xa6\x80\x87\xf1\xc8\xab\x71\xb6\xbc\xa4\x78\xfc\x58\xb4\x69\xb1\x43\xb1\x73\x74\x3e\xe8\x5f\x0e\x76\xc4\x9f\x7f\x0a\x3f\xb2\xed\x47\x9e\x6f\x77\x1a\x9a\xe9\xfc\x6c\x34\x0a\xca\xb1\xc0\x5e\xa1\xd4\x75\xfb\x59\xa7\x77\x23\xb3\x4a\x9d\x8d\xbc\x9a\x81\x76\x00\x47\x3b\x0c\x3c\x4f\x56\x79\x9e\x2f\xf1\x10\x13\x36\xd6\x47\x28\x99\x0e\x33\xb5\xee\x90\xc1\x63\xd9\x79\xad\xa3\x88\x45\xe8\x4b\x0c\x02\xa7\x22\x54\xc5\x55\x83\xf9\x59\xe3\x2d\x37\x2f\x90\xbc\xf0\x67\x8a\x2e\x0f\x90\x2f\xf0\x80\x33\x6f\xd5\x57\x3e\xa3\x68\x42\x42\x55\x3f\x4d\x4b\x44\xb3\x76\xa7\xe3\xc9\x75\x5e\x54\x6e\x7f\x89\x7c\xaa\x10\x2e\xe7\x3d\x4b\x01\xa9\xcd\x5b\xeb\xfa\x9d\x46\x9e\xb1\xb4\x27\x39\xf1\x04\xa4\xbe\x91\x90\x57\x4f\x1d\x19\x0b\x81\x61\x8a\x3e\xe2\x1c\xdb\x82\xd8\x76\xf6\xbe\xee\xac\x5b\x6b\xaf\xb3\x40\xc2\xb3\x97\x1d\x62\xb9\x3d\xa8\xf1\x5d\x87\x89\x5e\x51\xd9\x49\x9b\xe1\xb4\x98\x5d\x84\x82\x43\xb8\x7f\xa5\x36\xc2\x9f\x21\xb5\x0e\x27\xab\xb2\x11\xc5\x12\xf0\x25\x0c\xab\xb1\xe4\x48\xc3\x9e\x2e\x29\xf2\xda\x6a\xc8\x36\x77\xc6\xac\xa3\x2b\x80\xeb\x62\x70\x7a\xfc\x7a\x70\x71\x79\x7e\x75\x74\xb9\xd3\x80\x53\xa6\x46\x8e\x94\x5a\xde\x43\xa6\xf2\xb1\x9b\xb0\xfe\x24\x6e\x79\xf6\x33\xf1\x3c\x7d\xf6\xc5\x8f\x40\xfa\xba\xcb\x6f\xdd\xcf\x21\x3e\x7f\x61\xd9\xb7\xad\xef\x90\x7a\x63\x7e\xf3\x20\x32\xc5\x6d\x33\x70\x6c\xf0\xc5\x29\x62\xb0\x49\x39\x38\x26\xd2\xc7\xd7\x68\xc5\xd4\xe4\xea\xc7\x3d\xb2\x7f\x7a\xda\xf0\x47\xfe\x3e\x3a\x7b\xdd\xf4\xd1\x9d\xd7\x83\xd3\xc1\x1b\x78\xe9\x2a\xed\xc5\x65\x1f\x75\x01\x8f\x46\xf7\x85\xaa\x17\xd7\xba\xe0\x28\xcb\xb1\x0b\xae\xc3\xe5\x62\xad\x2f\x22\x1c\x76\x40\x85\x58\x19\x92\xc8\x48\xe6\x49\x0c\xee\x36\x1e\x1a\xb6\x80\x23\x33\xd1\x57\xd6\x43\x41\x13\xa8\x9d\xfa\x18\xb5\x7d\x8f\xcc\xe7\x17\x4d\xdb\xce\x44\xbd\x16\x06\xf5\x27\xc2\x01\x90\x83\x4c\xfb\xe1\x9b\x14\xff\x10\x7b\x62\x5f\x3c\x0b\x91\xe4\x9e\x50\xf5\x1c\xbe\x05\xf1\x3f\x11\xb0\x5e\x6c\xe0\xfc\x6b\x86\x2d\x67\x98\x38\x92\xc3\xd6\xff\xf3\x70\x86\xf4\x09\x59\xfb\x62\xd5\x88\xbf\xac\x19\xb1\xa6\x3f\x55\xf9\x3a\xfd\xdf\xd6\xe8\x17\xa1\x8f\x50\x05\x28\x3c\x5a\x83\x88\x0f\x3c\x8f\x56\xfc\x20\x18\x97\x4b\x1c\x96\x06\x7b\x6f\x0e\xb6\xcf\x97\x31\xbc\x88\x16\xb4\x62\x3a\xec\x69\xfb\x81\x0b\x8a\x06\x88\x83\x54\x8c\x86\x20\x7b\x57\x88\xf9\x8f\x22\xf4\xc6\xfa\x8e\xaa\xb8\xe5\x0a\xae\x0b\xd4\x41\x7b\x94\x66\xe8\x4c\x76\x2c\x8b\xa4\x4a\xd7\xcc\xe0\xcf\xaa\x87\x52\xc7\x4b\xcc\x95\xe2\x88\x14\x2a\x63\x2a\x6c\xb8\x58\xa4\xea\x36\xf4\x38\x8c\x4b\xc9\x05\x2c\xb0\x3b\x95\x73\xea\x71\x50\xc9\x5d\xcf\x91\x09\xd0\x15\xcd\x73\x39\xd5\x89\xf5\xf2\xb8\x2a\x2e\xd5\x58\x96\x2c\xb6\x54\x7f\x54\xc8\x1c\xd4\x34\x00\xfd\x58\xa0\x82\x30\xf0\x69\xea\x7a\x88\xbb\xfd\xfc\xc5\xde\x1e\xdc\x42\x17\xd8\x49\x57\xbc\x7c\xb1\xfb\xf2\x17\x51\x56\x99\xea\xf4\x5a\x8d\xd8\x5f\x6f\x35\x18\x9b\x26\x02\xe4\x5e\xab\xc2\x4d\x50\x5a\xbd\xba\x23\x89\xdc\x91\x11\x36\xd2\x8a\xa7\x02\x91\x9f\xf4\x3a\x5c\x02\xbb\x3f\x49\xa1\x50\x07\x07\x69\xd4\x29\x9e\xbd\x3e\x6b\x5f\x4b\x34\x3c\x72\xa8\x3a\xfb\xdc\x39\xb2\xad\x66\x32\xb4\x0e\x74\x28\xa2\xc8\x24\x0c\x29\x93\x04\x5d\xab\x23\xc3\xc7\x2e\x00\x76\x40\x52\xd8\x71\x51\x1e\x37\x59\xa0\x83\x1b\xc7\x1c\xc1\xa7\x46\xea\xc8\x29\x71\xe3\x7c\xad\x4e\x55\xe3\x54\x28\xa4\x18\x8e\xe7\x81\x82\x7a\xd0\x28\x70\x0a\x67\xcc\xf8\xb4\x66\x25\x75\x2c\x56\xe3\xe8\xa9\x51\x4d\x15\x59\x1b\x6d\x39\xf4\xc2\x3e\xf9\x9e\x80\x03\x03\xc2\xfe\xd8\xf6\x7c\x92\xa0\x65\x29\x50\xe5\x66\xd6\x5b\x06\x72\x13\xaa\xdc\x1b\xac\xd4\x10\x39\xd0\x84\x56\x99\x4b\x51\xd2\x12\x39\xd0\x23\x19\x23\x5d\x51\xc0\x2f\x29\xb8\x7f\x2f\x07\x86\x08\x7f\x3e\xf8\x30\x38\xaf\x2b\x86\x87\x1f\x62\x6c\x16\xb6\xeb\x5e\x0a\x4a\xa0\x51\x01\x16\xb7\x37\x54\xff\x1b\x00\x75\x78\x07\xa0\x48\xfe\x22\xa1\xbe\x6f\x6c\x27\x43\x73\xb0\x38\x18\x88\xe2\xd1\xa6\x02\x16\xd1\xc1\xae\x04\xfc\xd5\xe0\x60\x8a\x98\x56\x48\x29\x8e\x2a\x94\x0d\x56\x4b\xf4\xa5\x89\x45\xa5\xbe\xc0\xe7\x49\xc3\xc6\x33\xae\xd3\x3c\x51\x23\x34\xf0\x7c\x2c\xf8\xa4\x4f\x21\xac\x3b\x62\x31\xc1\x81\x92\xfe\x22\xb6\x01\x11\x57\x96\x4f\x3d\xc4\xcc\xa1\x1e\x9f\xe4\xae\x1d\x27\x4f\x72\x98\x26\x7e\x50\x26\xc0\x67\xd3\x8b\x36\x84\x54\xb4\x99\x48\x82\x4a\x2c\x44\x1c\x88\x95\x21\x12\xe4\xcd\xc1\x46\x83\xee\xeb\x19\x7d\x2f\x48\x23\x83\x3d\x02\x45\x0f\x61\x07\xc0\xc4\x78\xb4\x87\xdf\x01\xdc\x8a\xfe\x0e\xeb\xac\x18\xd3\x26\xf1\x2c\xd5\x2c\x41\xa0\x67\x0b\xd6\x88\x6c\x08\xff\x9c\xea\x52\x75\xaf\x84\x28\x62\x2d\x63\xdc\xc3\x12\xb5\xbd\x23\x99\x04\x2f\x8c\xa1\xa8\xc6\x47\x00\xfb\xa6\x42\x78\xab\x49\x20\xb6\xeb\xca\x64\x24\x75\x86\x8e\x7b\xfb\x40\x6c\x08\x65\xb6\x2a\x47\x32\x61\x7c\xd0\x05\x11\xa9\x61\x11\x68\xa6\x6a\x62\x66\xad\x5a\x8d\xd5\x80\xb8\x0e\xb8\x1a\x5b\x2b\x29\x89\xef\x80\x40\x51\x59\x39\x56\x0d\xc0\xd5\x87\x18\x0f\x7f\x63\x3f\xff\xd3\x70\x7c\x52\x7f\x7e\x07\x99\x7e\x95\xef\xc2\xed\x3e\xbc\x6d\x44\xce\x5a\xb9\x15\x89\xb8\xe8\x6a\x7c\x44\x55\x7d\x4d\x54\x43\xe9\x47\xce\xfd\xbf\x73\xf0\x11\x80\x3f\xe4\xbc\xab\xb4\x7e\x8f\xcb\xc4\x7e\xa7\xcb\x75\xd6\xfd\x28\xa8\x67\xef\x02\xc0\x5d\x25\x1c\x41\x35\xff\x5d\x25\x6e\x01\x57\x2e\xa0\xe8\x0b\x6d\xd1\x8d\x36\x15\xe5\x46\xf5\xff\xd4\xa2\xd6\xd5\x24\xe8\x6f\xc3\x5d\x1d\x1f\x5f\xf3\xb2\x6e\x36\x09\x77\xcd\xbe\x10\x6b\x64\x26\xc3\x69\x3b\x5c\xe1\x8d\xfc\x2d\xf0\x16\xf3\xdf\x73\x69\x17\xfc\xdd\x99\x82\x2a\x8d\x90\xf8\xb2\x52\xc9\x74\x5e\xe7\xda\xae\xaf\x71\x50\xdc\xe4\x69\x68\x8e\x90\x67\x34\xc9\x63\x2c\x92\x86\x72\x8c\x0a\xa9\xb5\xd1\x8c\xdf\x4d\xf0\x9b\x90\xb1\x56\x36\x37\x73\x74\x68\x6a\xa9\x03\x65\x8d\x5b\x0f\xc8\xc5\x2b\xbe\xb4\x7a\xff\x18\xae\x30\xd1\x3d\x57\x53\x2e\xb2\x85\xbc\xc1\x02\x92\xba\x41\x2e\xde\x10\xdf\x92\x4c\xc1\xc0\xfc\xea\x80\xc3\x33\xf4\xe8\xd0\x7a\x00\xc8\x7f\x06\xe3\x2b\xc1\x31\x7e\x06\x73\x3c\xdc\x67\x1f\xea\xb1\x7e\xfb\xc7\x99\x74\x2e\xc0\xab\x61\x5e\xef\x59\xda\xf1\x83\x14\x8a\xde\xd6\xc3\x5c\x8a\xcb\x31\xa2\x79\x25\xf6\x1a\x25\xff\x5f\xc5\xc9\xd6\x21\x76\x5a\x97\x7e\x61\xf3\xce\x98\x2e\xb6\x29\xb9\x01\x8b\xcf\x45\xb1\xd4\xbd\xaf\x1f\x8c\xde\xeb\x8b\xc5\x35\xf7\xe5\x7b\x36\x88\x0a\x37\x32\xbe\x6b\x18\x2a\xcc\x68\x04\x78\xba\xf7\x15\x84\xae\xf0\xc2\x41\x5a\x5a\x16\xc7\xe7\xa2\xc9\xe9\x82\xe0\xf0\xdc\x40\xf9\x19\xe8\x81\xbb\xfb\xf1\x86\xbf\x27\xee\xeb\xc2\xdf\x7d\x32\xb4\xbe\x20\xf1\xb5\x78\xbc\xa2\x00\x1d\x17\xa2\xdc\xc6\xaf\xdc\x53\xd0\x1c\x0d\xf9\x1e\x7f\xe5\x56\x82\x19\xc3\xcd\xc4\xea\xe5\x27\xcd\xf1\xd8\x12\xc0\x99\x14\x18\xf5\x62\x56\x5c\x02\x1c\x6b\x1e\x11\x19\xc8\x19\xf6\x37\x33\xd0\xd4\x06\xa6\x95\x9b\x12\x22\xe6\x21\x3f\xeb\x13\xfb\x7e\x73\xd6\x0f\x85\x8d\xea\x69\xc3\x36\xf8\xa0\xd1\xdb\x83\xcd\x41\x6e\x2f\xe2\x71\x73\x30\x23\x9b\xd7\x80\xbd\x83\xb5\xd9\xc6\xac\x93\xdc\x17\x2a\x59\x7a\x8c\x6c\x77\xb0\xb2\xf4\x46\xe9\x81\x3d\x3d\x58\x64\x4d\xdc\x54\xd1\x1f\x2e\xc8\x96\x79\x56\xeb\xdd\x48\xbe\x24\x72\xd3\x9a\x21\x2c\x05\x3a\x7f\x10\x51\x80\x77\x02\xbf\x35\x76\x00\xfd\x2f\x15\x24\x36\xdd\x2d\x4e\xd1\xdb\x1c\xbf\x9f\x70\xfd\x4a\xde\x66\x86\x5c\x2b\x54\x96\x1a\xda\x85\x1b\xc1\xf9\x74\x49\x2f\x60\x5a\x65\xf0\x39\x7a\xf0\xa6\x76\xf9\x77\x4b\x37\x7a\xf4\x52\xa6\x4a\x4d\x12\xfd\x8b\xa0\x7f\x9c\xe7\x77\xca\x1c\x85\x9f\x9b\x8b\x11\x16\xa1\x27\x2f\x84\xc7\x42\xa2\xed\x9a\x22\x41\x60\x05\x7a\xc5\x9c\x0b\x53\x42\x9e\x4a\x17\x1d\x23\x79\xb0\xa1\xa7\xc6\x92\x9e\xfa\x4c\xc8\xaa\x5c\xd4\x15\x54\xa3\x6a\xd7\x0d\x97\x42\xda\x16\x99\x9c\x63\x80\x32\x78\xd8\x54\xd3\xa9\xeb\x77\x26\x7e\xac\x32\x94\xa4\xd7\x3d\x3a\xf6\x96\xcb\x2e\xcd\xc3\xf4\xb5\xec\xcc\xa1\xb5\x5a\x76\xe3\xc5\x1d\xdb\xb2\xcf\xc6\x2c\xb3\xec\x98\xcd\x9c\xb5\xec\x7d\x3c\xc3\x5f\xcb\x7e\xd7\x28\xaf\x79\x82\xc1\x51\x33\xf0\xd7\x8a\x27\xb2\x96\xc1\x15\xb7\x6e\x7c\x6d\x53\xeb\x89\xaf\x6e\x4c\x9e\xb6\x1e\xe7\xaf\x6e\x00\x12\x9d\x6e\x9b\x8c\x76\xad\xe6\x14\xd0\xbd\xed\x1a\xd9\xc9\x0f\x7c\xc6\xf4\x97\xcd\xc9\x28\xc0\xb4\x41\x57\x67\x9f\x08\x75\x3f\x77\x4f\x3c\xa8\xb5\xd0\x87\x7b\x07\x42\xff\xda\x64\x88\x09\x54\xe8\x27\x4f\xe2\x9a\xcd\xf9\xcf\xfa\x4b\x74\xf2\xda\x13\x56\xe6\x3b\x4b\x1a\x05\xdf\xf1\x34\xe4\x2c\xad\xdb\xd6\xbf\x01\xaa\x66\x58\xaa\x93\x22\x00\x00")
 
 func call_tracerJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -133,7 +133,7 @@ func call_tracerJs() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "call_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0xef, 0x68, 0xda, 0xd8, 0x9, 0xf5, 0xd5, 0x71, 0xa8, 0x8a, 0xfb, 0x30, 0xe8, 0xf0, 0x72, 0x14, 0x36, 0x6b, 0x62, 0x5a, 0x4e, 0xff, 0x16, 0xdc, 0xd3, 0x2c, 0x68, 0x7b, 0x79, 0x9f, 0xd3}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa1, 0xfd, 0xea, 0x3f, 0x7, 0x6, 0x44, 0xfb, 0x52, 0xc5, 0x88, 0xbc, 0x8a, 0x5c, 0x9f, 0x3a, 0xa1, 0x6, 0x43, 0x52, 0xdd, 0x2e, 0x8b, 0x2f, 0xb1, 0xfd, 0x2d, 0x42, 0xdb, 0xc9, 0xb5, 0xcc}}
 	return a, nil
 }
 
@@ -197,7 +197,7 @@ func opcount_tracerJs() (*asset, error) {
 	return a, nil
 }
 
-var _prestate_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x57\xdd\x6f\xdb\x38\x12\x7f\x96\xfe\x8a\x41\x5f\x6c\xa3\xae\xdc\x64\x81\x3d\xc0\xb9\x1c\xa0\xba\x6e\x1b\x20\x9b\x04\xb6\x7b\xb9\xdc\x62\x1f\x28\x72\x24\x73\x4d\x93\x02\x49\xd9\xf1\x15\xf9\xdf\x0f\x43\x7d\xf8\xa3\x49\xd3\xdd\x37\x9b\x1c\xfe\xe6\xfb\x37\xa3\xd1\x08\x26\xa6\xdc\x59\x59\x2c\x3d\x9c\xbf\x3f\xfb\x07\x2c\x96\x08\x85\x79\x87\x7e\x89\x16\xab\x35\xa4\x95\x5f\x1a\xeb\xe2\xd1\x08\x16\x4b\xe9\x20\x97\x0a\x41\x3a\x28\x99\xf5\x60\x72\xf0\x27\xf2\x4a\x66\x96\xd9\x5d\x12\x8f\x46\xf5\x9b\x67\xaf\x09\x21\xb7\x88\xe0\x4c\xee\xb7\xcc\xe2\x18\x76\xa6\x02\xce\x34\x58\x14\xd2\x79\x2b\xb3\xca\x23\x48\x0f\x4c\x8b\x91\xb1\xb0\x36\x42\xe6\x3b\x82\x94\x1e\x2a\x2d\xd0\x06\xd5\x1e\xed\xda\xb5\x76\x7c\xbe\xf9\x0a\xd7\xe8\x1c\x5a\xf8\x8c\x1a\x2d\x53\x70\x57\x65\x4a\x72\xb8\x96\x1c\xb5\x43\x60\x0e\x4a\x3a\x71\x4b\x14\x90\x05\x38\x7a\xf8\x89\x4c\x99\x37\xa6\xc0\x27\x53\x69\xc1\xbc\x34\x7a\x08\x28\xc9\x72\xd8\xa0\x75\xd2\x68\xf8\xa5\x55\xd5\x00\x0e\xc1\x58\x02\xe9\x33\x4f\x0e\x58\x30\x25\xbd\x1b\x00\xd3\x3b\x50\xcc\xef\x9f\xfe\x44\x40\xf6\x7e\x0b\x90\x3a\xa8\x59\x9a\x12\xc1\x2f\x99\x27\xaf\xb7\x52\x29\xc8\x10\x2a\x87\x79\xa5\x86\x84\x96\x55\x1e\xee\xaf\x16\x5f\x6e\xbf\x2e\x20\xbd\x79\x80\xfb\x74\x36\x4b\x6f\x16\x0f\x17\xb0\x95\x7e\x69\x2a\x0f\xb8\xc1\x1a\x4a\xae\x4b\x25\x51\xc0\x96\x59\xcb\xb4\xdf\x81\xc9\x09\xe1\xb7\xe9\x6c\xf2\x25\xbd\x59\xa4\x1f\xae\xae\xaf\x16\x0f\x60\x2c\x7c\xba\x5a\xdc\x4c\xe7\x73\xf8\x74\x3b\x83\x14\xee\xd2\xd9\xe2\x6a\xf2\xf5\x3a\x9d\xc1\xdd\xd7\xd9\xdd\xed\x7c\x9a\xc0\x1c\xc9\x2a\xa4\xf7\xaf\xc7\x3c\x0f\xd9\xb3\x08\x02\x3d\x93\xca\xb5\x91\x78\x30\x15\xb8\xa5\xa9\x94\x80\x25\xdb\x20\x58\xe4\x28\x37\x28\x80\x01\x37\xe5\xee\xa7\x93\x4a\x58\x4c\x19\x5d\x04\x9f\x5f\x2c\x48\xb8\xca\x41\x1b\x3f\x04\x87\x08\xff\x5c\x7a\x5f\x8e\x47\xa3\xed\x76\x9b\x14\xba\x4a\x8c\x2d\x46\xaa\x86\x73\xa3\x7f\x25\x31\x61\x96\x16\x9d\x67\x1e\x17\x96\x71\xb4\x60\x2a\x5f\x56\xde\x81\xab\xf2\x5c\x72\x89\xda\x83\xd4\xb9\xb1\xeb\x50\x29\xe0\x0d\x70\x8b\xcc\x23\x30\x50\x86\x33\x05\xf8\x88\xbc\x0a\x77\x75\xa4\x43\xb9\x5a\xa6\x1d\xe3\xe1\x34\xb7\x66\x4d\xbe\x56\xce\xd3\x0f\xe7\x70\x9d\x29\x14\x50\xa0\x46\x27\x1d\x64\xca\xf0\x55\x12\x7f\x8b\xa3\x03\x63\xa8\x4e\x82\x87\x8d\x50\xa8\x8d\x2d\xf6\x2c\x42\x56\x49\x25\xa4\x2e\x92\x38\x6a\xa5\xc7\xa0\x2b\xa5\x86\x71\x80\x50\xc6\xac\xaa\x32\xe5\xdc\x54\xc1\xf6\x3f\x91\xfb\x1a\xcc\x95\xc8\x65\x4e\xc5\xc1\xba\x5b\x6f\xc2\x55\xa7\xd7\x64\x24\x9f\xc4\xd1\x11\xcc\x18\xf2\x4a\x07\x77\xfa\x4c\x08\x3b\x04\x91\x0d\xbe\xc5\x51\xb4\x61\x96\xb0\xe0\x12\xbc\xf9\x82\x8f\xe1\x72\x70\x11\x47\x91\xcc\xa1\xef\x97\xd2\x25\x2d\xf0\xef\x8c\xf3\x3f\xe0\xf2\xf2\x32\x34\x75\x2e\x35\x8a\x01\x10\x44\xf4\x9c\x58\x7d\x13\x65\x4c\x31\xcd\x71\x0c\xbd\xf7\x8f\x3d\x78\x0b\x22\x4b\x0a\xf4\x1f\xea\xd3\x5a\x59\xe2\xcd\xdc\x5b\xa9\x8b\xfe\xd9\xaf\x83\x61\x78\xa5\x4d\x78\x03\x8d\xf8\x8d\xe9\x84\xeb\x7b\x6e\x44\xb8\x6e\x6c\xae\xa5\x26\x46\x34\x42\x8d\x94\xf3\xc6\xb2\x02\xc7\xf0\xed\x89\xfe\x3f\x91\x57\x4f\x71\xf4\x74\x14\xe5\x79\x2d\xf4\x42\x94\x1b\x08\x40\xed\x6d\x57\xe7\x85\xa4\x4e\x3d\x4c\x40\xc0\xfb\x51\x12\xe6\xad\x29\x27\x49\x58\xe1\xee\xf5\x4c\xd0\x85\x14\x8f\xdd\xc5\x0a\x77\x83\x8b\xf8\xc5\x14\x25\x8d\xd1\xbf\x4b\xf1\xf8\xb3\xf9\x3a\x79\x73\x14\xd7\x39\x49\xed\xed\x1d\x0c\x4e\xe2\x68\xd1\x55\xca\x53\xb9\x4b\xbd\x31\x2b\x22\xae\x25\xc5\x47\xa9\x10\x12\x53\x52\xb6\x5c\xcd\x1c\x19\xa2\x06\xe9\xd1\x32\xa2\x4e\xb3\x41\x4b\x53\x03\x2c\xfa\xca\x6a\xd7\x85\x31\x97\x9a\xa9\x16\xb8\x89\xba\xb7\x8c\xd7\x3d\x53\x9f\x1f\xc4\x92\xfb\xc7\x10\xc5\xe0\xdd\x68\x04\xa9\x07\x72\x11\x4a\x23\xb5\x1f\xc2\x16\x41\x23\x0a\x6a\x7c\x81\xa2\xe2\x3e\xe0\xf5\x36\x4c\x55\xd8\xab\x9b\x9b\x28\x32\x3c\x35\x15\x4d\x82\x83\xe6\x1f\x06\x03\xd7\x66\x13\x46\x5c\xc6\xf8\x0a\x9a\x86\x33\x56\x16\x52\xc7\x4d\x38\x8f\x9a\x8d\x2c\x4a\x08\x38\x98\x15\x72\x45\x49\xa4\x93\x0f\x4c\xc1\x25\x64\xb2\xb8\xd2\xfe\x24\x79\x75\xd0\xdb\xa7\x83\x3f\x92\xa6\x79\x12\x47\x84\xd7\x3f\x1f\x0c\xe1\xec\xd7\xae\x22\xbc\x21\x28\x78\x1d\xcc\x9b\x97\xa1\xe2\xd3\x62\x78\xfe\x59\x50\x43\x1d\xfc\x36\x68\x4d\x5c\x95\x51\x3a\x6a\x3f\x43\x1c\x8f\xbb\xf8\xe2\x07\xb8\xc7\xbe\xb5\xb8\x4d\x68\x12\x26\xc4\xcb\xa0\x75\x8a\x3e\x22\xb7\xb8\x26\x56\xa7\x2c\x70\xa6\x14\xda\x9e\x83\xc0\x19\xc3\xa6\x9c\x42\xbe\x70\x5d\xfa\x5d\xcb\xf5\x9e\xd9\x02\xbd\x7b\xdd\xb0\x80\xf3\xee\x5d\x4b\x81\x21\x14\xbb\x12\xe1\xf2\x12\x7a\x93\xd9\x34\x5d\x4c\x7b\x4d\x1b\x8d\x46\x70\x8f\x61\x13\xca\x94\xcc\x84\xda\x81\x40\x85\x1e\x6b\xbb\x8c\x0e\x21\xea\x28\x61\x48\x2b\x0d\x2d\x1b\xf8\x28\x9d\x97\xba\x80\x9a\x29\xb6\x34\x57\x1b\xb8\xd0\x23\x9c\x55\x8e\xaa\xf5\x64\x08\x79\x43\x1b\x85\x45\xe2\x15\xe2\xff\xd0\x6e\x4c\xc9\x6e\x03\xc9\xa5\x75\x1e\x4a\xc5\x38\x26\x84\xd7\x19\xf3\x72\x7e\x9b\x4e\x26\xd5\xb3\xd0\x82\x01\x68\x3f\xe0\x98\xa2\x01\x49\xea\x1d\xf4\x5b\x8c\x41\x1c\x45\xb6\x95\x3e\xc0\xbe\xd8\x53\x82\xf3\x58\x1e\x12\x02\x2d\x16\xb8\x41\xa2\xd0\xc0\x06\xf5\x30\x24\x5d\xff\xfe\xad\x99\xbe\xe8\x92\x38\xa2\x77\x07\x7d\xad\x4c\x71\xdc\xd7\xa2\x0e\x0b\xaf\xac\xa5\xfc\x77\x14\x9c\x53\x8f\xff\x59\x39\x4f\x31\xb5\x14\x9e\x86\x2d\x9e\x23\xc9\x40\x89\x34\x6d\x07\xdf\x93\x21\xcd\xad\x30\x27\x48\x5d\x33\xa5\xea\x6d\xae\x34\x1e\xb5\x97\x4c\xa9\x1d\xe5\x61\x6b\x69\x8d\xa1\xc5\x65\x08\x4e\x92\x54\x60\x9c\x20\x2a\x35\x57\x95\xa8\xcb\x20\xd4\x71\x83\xe7\x82\xcd\xc7\xfb\xcf\x1a\x9d\x63\x05\x26\x54\x49\xb9\x7c\x6c\x36\x48\x0d\xbd\x9a\xe4\xfa\x83\x5e\xd2\x19\x79\x4c\x31\xca\x14\x49\x5b\x64\x44\xd3\xa9\x10\x16\x9d\xeb\x0f\x1a\xce\xe9\x32\x7b\xbf\x44\x4d\xc1\x07\x8d\x5b\xe8\x56\x13\xc6\x39\xad\x6a\x62\x08\x4c\x08\xa2\xb6\x93\x35\x22\x8e\x22\xb7\x95\x9e\x2f\x21\x68\x32\xe5\xbe\x17\x07\x4d\xfd\x73\xe6\x10\xde\x4c\xff\xb3\x98\xdc\x7e\x9c\x4e\x6e\xef\x1e\xde\x8c\xe1\xe8\x6c\x7e\xf5\xdf\x69\x77\xf6\x21\xbd\x4e\x6f\x26\xd3\x37\xe3\x30\x9b\x9f\x71\xc8\x9b\xd6\x05\x52\xe8\x3c\xe3\xab\xa4\x44\x5c\xf5\xdf\x1f\xf3\xc0\xde\xc1\x28\xca\x2c\xb2\xd5\xc5\xde\x98\xba\x41\x1b\x1d\x2d\xe5\xc2\x25\xbc\x18\xac\x8b\x97\xad\x99\x34\xf2\xfd\x96\xc8\xf7\xab\x48\xa0\x8a\xd7\xed\x38\xff\xcb\x86\x84\xde\x61\x7c\x35\x06\xc7\x14\x6d\xc0\xf2\x7f\xf4\xe5\x92\xe7\x0e\xfd\x10\x50\x0b\xb3\x25\xe6\xeb\x50\xeb\x9b\x06\xf7\x20\x64\x67\x83\x9a\x41\x6f\xf3\xfe\xa0\x13\x26\xb0\xef\x45\xcf\x9f\x13\x45\x2d\xe0\xb2\x45\x7f\x1b\x5e\xbe\x1e\xa8\xf3\x26\x52\x27\x0a\x7e\x39\xd9\xf0\xc2\xfd\x1a\xd7\xc6\xee\x9a\x71\x74\xe0\xdf\x8f\xa3\x9a\x5e\x5f\x77\xf5\x44\x7f\xa8\xc8\xba\x83\x8f\xd3\xeb\xe9\xe7\x74\x31\x3d\x92\x9a\x2f\xd2\xc5\xd5\xa4\x3e\xfa\xcb\x85\x77\xf6\xd3\x85\xd7\x9b\xcf\x17\xb7\xb3\x69\x6f\xdc\xfc\xbb\xbe\x4d\x3f\xf6\xbe\x53\xd8\x6c\x81\x3f\x6a\x5d\x6f\xee\x8d\x15\x7f\xa7\x03\x0e\x36\xb2\x9c\x3d\xb7\x90\x05\x6a\xe7\xbe\x3a\xf9\xe0\x01\xa6\x5b\x56\xce\xeb\x8f\xbe\x28\xbc\x7f\x96\x87\x9f\xe2\xa7\xf8\xff\x01\x00\x00\xff\xff\xb1\x28\x85\x2a\x8a\x10\x00\x00")
+var _prestate_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x57\x5d\x6f\xdb\x36\x14\x7d\x8e\x7f\xc5\xc5\x5e\x6c\xa3\xae\xdc\x66\xc0\x06\x24\xeb\x00\xd7\x75\xdb\x00\x5e\x12\xd8\xee\xb2\x6c\xd8\x03\x25\x51\x36\x17\x59\x14\x48\x2a\x8e\x37\xe4\xbf\xef\x5c\x8a\x92\xed\x7c\x34\xdd\x1e\x8a\x54\xe4\xe5\xb9\xdf\xe7\x5e\x0f\x87\x34\xd6\xe5\xd6\xa8\xe5\xca\xd1\xf1\x9b\xb7\x3f\xd2\x62\x25\x69\xa9\x5f\x4b\xb7\x92\x46\x56\x6b\x1a\x55\x6e\xa5\x8d\xed\x0c\x87\xb8\x52\x96\x32\x95\x4b\xc2\xdf\x52\x18\x47\x3a\x23\xf7\x40\x3e\x57\xb1\x11\x66\x1b\xe1\x41\xfd\xe6\xc9\x6b\x46\xc8\x8c\x94\x64\x75\xe6\x36\xc2\xc8\x13\xda\xea\x8a\x12\x51\x90\x91\xa9\xb2\xce\xa8\xb8\x72\x50\xe4\x48\x14\xe9\x50\x1b\x5a\xeb\x54\x65\x5b\x86\xc4\x59\x55\xa4\xd2\x78\xd5\x4e\x9a\xb5\x6d\xec\xf8\x74\xfe\x85\xa6\xd2\x5a\xdc\x7d\x92\x85\x34\x22\xa7\xcb\x2a\xce\x55\x42\x53\x95\xc8\xc2\x4a\x12\x30\x9c\x4f\xec\x4a\xa6\x14\x7b\x38\x7e\xf8\x91\x4d\x99\x07\x53\xe8\xa3\x06\xbe\x70\x4a\x17\x03\x92\x8a\x2d\xa7\x5b\x69\x2c\xbe\xe9\xfb\x46\x55\x00\x1c\x90\x36\x0c\xd2\x13\x8e\x1d\x30\xa4\x4b\x7e\xd7\x87\xd5\x5b\xca\x85\xdb\x3d\xfd\x86\x80\xec\xfc\x4e\x49\x15\x5e\xcd\x4a\x97\xf0\x71\x05\x74\x78\xbd\x51\x79\x4e\xb1\xa4\xca\xca\xac\xca\x07\x8c\x06\x61\xba\x3a\x5b\x7c\xbe\xf8\xb2\xa0\xd1\xf9\x35\x5d\x8d\x66\xb3\xd1\xf9\xe2\xfa\x14\xc2\xc8\x1b\x6e\xe5\xad\xac\xa1\xd4\xba\xcc\x15\x90\xe1\xa2\x11\x85\xdb\xc2\x13\x46\xf8\x65\x32\x1b\x7f\xc6\x93\xd1\xfb\xb3\xe9\xd9\xe2\x1a\xfe\xd0\xc7\xb3\xc5\xf9\x64\x3e\xa7\x8f\x17\x33\x1a\xd1\xe5\x68\xb6\x38\x1b\x7f\x99\x8e\x66\x74\xf9\x65\x76\x79\x31\x9f\x44\x34\x97\x6c\x95\xe4\xf7\x2f\xc7\x3c\xf3\xd9\x43\x5c\x53\xe9\x84\xca\x6d\x13\x89\x6b\x24\xdc\xc2\xc6\x3c\xa5\x95\xb8\x95\x48\x7c\x22\xd5\x2d\x2c\x14\x94\xa0\x26\xbf\x39\xa9\x8c\x25\x72\x5d\x2c\xbd\xcf\xcf\x16\x24\x9d\x65\x54\x68\x37\x20\x0b\xe3\x7f\x5a\x39\x57\x9e\x0c\x87\x9b\xcd\x26\x5a\x16\x55\xa4\xcd\x72\x98\xd7\x70\x76\xf8\x73\xd4\x61\xcc\xd2\x48\xeb\x90\xc2\x85\x11\x09\x94\x23\x98\x65\xe5\x2c\xd9\x2a\xcb\x54\xa2\x64\x81\x9c\x14\xf0\x6d\xed\x2b\x85\x9c\xa6\xc4\x48\x88\xc3\xfc\x5c\x27\xb0\x52\xde\xc9\xa4\xf2\x77\x75\xa4\x7d\xb9\x22\xf4\x56\x24\xfe\x34\x33\x7a\xcd\xbe\x56\xd6\xf1\x7f\xe0\xe1\x3a\xce\xe1\xfe\x12\x5e\x5a\x94\x43\x0c\x98\x9b\xa8\xf3\x4f\xe7\x68\xcf\x18\xae\x13\xef\x61\x10\xf2\xb5\xb1\x91\x5d\x84\x37\xae\x54\x9e\xaa\x62\x19\x75\x8e\x1a\xe9\x13\x2a\xaa\x1c\x95\xe2\x21\x72\xad\x6f\xaa\x72\x94\x24\x28\x6f\xb6\xfd\x2f\x99\xb8\x1a\xcc\x96\x32\x51\x19\x17\x87\x68\x6f\xe1\x0f\x5f\xb5\x7a\x75\xcc\xf2\xc0\x3e\x80\x39\xa1\xac\x2a\xbc\x3b\x3d\x91\xa6\x66\x40\x69\xdc\x87\xc1\x47\xb7\xc2\x30\x16\xbd\x43\x5c\x3e\xcb\x3b\x7f\xd9\x3f\xc5\x85\xca\xa8\xe7\xc0\x23\x51\x03\xfc\x07\xc4\xfe\xa4\x77\xef\xde\xf9\xa6\xce\x54\x21\xd3\x3e\x31\xc4\xd1\x53\x62\xf5\xcd\x51\x2c\x72\x51\x24\x70\xaf\xfb\xe6\xae\x4b\xaf\xa0\x35\x5a\x4a\xf7\xbe\x3e\xad\x95\x45\x4e\xcf\xd1\x4d\xc5\xb2\xf7\xf6\x87\xfe\xc0\xbf\x2a\xb4\x7f\x43\x41\xfc\x5c\xb7\xc2\xf5\x7d\xa2\x53\x7f\x1d\x6c\xae\xa5\xc6\x38\xac\x85\x82\x14\xb2\x65\xc4\x12\x82\xff\xdc\xf3\xf7\x3d\x7b\xe5\xdd\x82\xbc\xb2\xbf\x8a\x2a\x77\x41\x3e\x18\xfb\xd8\x8f\xe8\x96\xa5\x38\x38\xa6\x92\xfe\x3d\x43\xe1\xdf\xfd\x41\xaa\xe6\xb5\xa6\x67\x52\x15\xec\x20\x14\xa2\x69\x9b\x65\xa9\xb8\xdd\xf7\xb3\xe8\xf1\xbe\x96\xc9\x79\xe3\xcf\x83\x4c\xde\xc8\xed\xcb\xe9\xe4\x0b\x95\xde\xb5\x17\x78\x84\xf3\x67\xf3\x1c\x05\xa3\xff\xc0\x9b\x6f\x4d\xfa\x83\x37\x07\xc9\x99\xb3\xd4\xce\xde\xbe\xb7\x69\x2f\x8e\xc0\xe1\x40\xa3\x4f\x54\x71\xab\x6f\x98\xfd\x56\x1c\x1f\xf0\x28\x87\x44\x97\x9c\x72\x5b\xd3\x4f\x2c\x71\xa3\xc0\xd8\x82\xf9\x57\x83\xb6\x79\xf4\x00\xc2\x55\xa6\xb0\x6d\x18\x61\x2c\x7a\x3b\x00\x87\xa8\xa3\xab\x93\xba\xf1\xea\xf3\xbd\x58\x26\xee\xce\x47\xd1\x7b\x07\x88\x91\x23\x76\x91\x4a\x8d\xe4\x0c\xd0\xb9\x54\x48\xa8\x43\xb7\xa5\x32\xad\x12\xe7\xf1\xba\xb7\x22\xaf\x64\xb7\x66\x08\xe6\x59\xff\x14\x04\xc4\x43\x6f\xc7\x20\x03\x6f\xe0\x1a\xa6\xf2\x74\x88\x45\x72\x43\xa1\x6b\x35\x06\xba\x2a\x3a\x21\x9c\x07\x1d\xcb\x16\x45\x0c\xec\xcd\xf2\xb9\xe2\x24\xf2\x09\xfa\x07\xf1\x8d\xd5\xf2\x0c\x62\x87\x89\xa8\x83\xde\x3c\xed\xff\x19\x85\x0e\x8c\x2c\xb3\x66\xef\xb8\x3f\x20\xb4\x59\x53\x11\x4e\x33\x14\xbd\x0c\xe6\xf4\xf3\x50\x9d\x87\xc5\xf0\xf4\x33\xaf\x86\x69\xe0\x95\xd7\x1a\xd9\x2a\xe6\x74\xd4\x7e\xfa\x38\x1e\x52\xc1\xe9\x57\x70\x0f\x7d\x6b\x70\x43\x68\x22\x94\xd9\xf3\xa0\x75\x8a\x3e\x48\xcc\x80\x35\x8f\x06\xce\x02\x86\x40\x2e\x4d\xd7\x92\x27\x9e\x41\x28\x27\x9f\x2f\xb9\x2e\x31\x81\xc3\xc0\x70\xc2\xa0\x96\xed\xcb\x86\x79\x9c\xd7\xaf\x1b\x1e\xf5\xa1\xd8\x62\x41\x40\x1f\x75\xc7\xb3\xc9\x68\x31\xe9\x86\x36\x82\x2d\x57\xd2\xaf\x53\x98\x94\x71\x9a\x6f\x51\x5e\xb9\x74\xb2\xb6\x4b\x17\x3e\x44\x2d\x25\x0c\x78\x2f\xe2\x8d\x45\xde\x61\x05\x81\x4f\x54\x33\xc5\x86\x87\x73\x80\xf3\x3d\x92\x08\xec\x1e\xe9\xa3\x49\x86\xaa\x8b\x79\x7e\x33\xaf\xf0\x10\xf1\xed\x26\x72\xd5\xae\x31\x99\x32\x16\xea\x72\x0c\xd2\x88\xf1\x5a\x63\x9e\xcf\x6f\xe8\x64\x56\x3d\xf3\x2d\xe8\x81\x76\x53\x12\xb1\xc5\x94\x65\xf5\x96\x7a\x0d\x46\x1f\x0f\x4c\x23\xbd\x87\x7d\xba\xa3\x04\xeb\x64\xb9\x4f\x08\xbc\x9d\x60\x47\x62\x0a\xf5\x6c\x50\x4f\x54\xd6\xf5\xeb\x2f\x61\x84\x4b\xac\x2c\x47\xfc\x6e\xaf\xaf\x73\xbd\x3c\xec\xeb\xb4\x0e\x4b\x52\x19\xc3\xf9\x6f\x29\x38\xe3\x1e\xff\x0b\x33\x9e\x63\x6a\x38\x3c\x81\x2d\x9e\x22\x49\x4f\x89\x3c\xb2\xfb\x8f\xc9\x90\x87\x5f\x3d\x6c\xa0\x2e\x8c\xba\x7a\x25\x2c\xb5\x83\x4a\x85\x88\x6c\x39\x0f\x1b\xc3\xbb\x10\x6f\x3f\xd8\x75\x14\x4b\x79\xc6\xf1\xa2\xf8\xcc\xab\xb4\x2e\x03\x5f\xc7\x01\xcf\x7a\x9b\x0f\x97\xa8\x35\x96\x2e\xf0\x6e\xc4\x95\x94\xa9\xbb\xb0\x86\x16\xd4\xad\x49\xae\xd7\xef\x46\xad\x91\x87\x14\x83\xe0\x44\x4d\x91\x31\x4d\x23\x38\x78\x63\x7b\xfd\xc0\x39\x6d\x66\xaf\xc0\xc6\x1c\x7c\x90\xe0\x86\xda\xfd\x06\xb1\xe3\x7d\x2f\x45\x59\x22\xaa\xa0\xb6\x07\xbb\x08\xde\x5a\x58\x99\xac\xc8\x6b\xd2\xe5\xae\x17\x9b\xa1\x9b\x08\x2c\x9e\xdf\x4d\x7e\x5b\x8c\x2f\x3e\x4c\xc6\x17\x97\xd7\xdf\x9d\xd0\xc1\xd9\xfc\xec\xf7\x49\x7b\xf6\x7e\x34\x1d\x9d\x8f\xf1\xbd\x9b\xd7\x87\x0e\x39\xdd\xb8\xc0\x0a\x61\x04\x16\xb3\x52\xca\x9b\xde\x9b\x43\x1e\xd8\x39\x88\x1d\x05\xcd\x7d\x73\xba\x33\xa6\x6e\xd0\xa0\xa3\xa1\x5c\x24\xf5\xd9\x60\x9d\x3e\x6f\xcd\x38\xc8\xf7\x1a\x22\xdf\xed\x33\x9e\x2a\x5e\xb6\xe3\xf8\x3f\x1b\xe2\x7b\x07\x8e\x9f\x90\x15\x39\xaf\xd1\xea\x6f\xfe\xf9\x93\x65\x56\xe2\x4b\x16\xa9\xde\x30\xf3\xb5\xa8\xf5\x4d\xc0\xdd\x0b\xd9\xdb\x7e\xcd\xa0\x17\x59\xaf\xdf\x0a\x33\xd8\x63\xd1\xe3\xa7\x44\xa1\x09\x92\x01\xfd\x95\x7f\xf9\x72\xa0\x8e\x43\xa4\x1e\x28\xf8\xfe\xc1\x9a\xe8\xef\xd7\xa0\x68\xfc\x5e\xa8\xc7\xd1\x9e\x7f\x5f\x8f\xea\x68\x3a\x6d\xeb\x89\x3f\xb8\xc8\xda\x83\x0f\x93\xe9\xe4\x13\xa2\x7e\x20\x35\x5f\x8c\xf0\xc3\xaa\x3e\xfa\xcf\x85\xf7\xf6\x9b\x0b\xaf\x3b\x9f\x2f\x2e\x66\x93\xee\x49\xf8\x9a\x5e\x8c\x3e\x74\x1f\x29\x0c\x5b\xe0\xd7\x5a\xd7\xe9\x2b\x6d\xd2\xff\xd3\x01\x7b\x1b\x59\x26\x9e\x5a\xc8\x3c\xb5\x27\xae\x7a\xf0\xab\x09\x33\xa9\x61\xe5\xac\xfe\xe5\x78\xe4\xdf\x3f\xc9\xc3\xf7\x9d\xfb\xce\xbf\x1d\x42\xdc\x13\xcf\x10\x00\x00")
 
 func prestate_tracerJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -213,7 +213,7 @@ func prestate_tracerJs() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "prestate_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0x79, 0x70, 0x4f, 0xc5, 0x78, 0x57, 0x63, 0x6f, 0x5, 0x31, 0xce, 0x3e, 0x5d, 0xbd, 0x71, 0x4, 0x46, 0x78, 0xcd, 0x1d, 0xcd, 0xb9, 0xd8, 0x10, 0xff, 0xe6, 0xc5, 0x59, 0xb9, 0x25, 0x6e}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc1, 0x50, 0x93, 0x8d, 0xb, 0xcf, 0x79, 0xe6, 0x27, 0x75, 0xfc, 0xb1, 0x8d, 0xb5, 0xe1, 0xd7, 0x5d, 0xb0, 0x1b, 0xf2, 0xda, 0xa6, 0x54, 0x42, 0x85, 0x86, 0x48, 0xa0, 0x94, 0x8e, 0xb, 0xa9}}
 	return a, nil
 }
 
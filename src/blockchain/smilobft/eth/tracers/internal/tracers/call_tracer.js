@@ -90,6 +90,9 @@
 			if (op != 'DELEGATECALL' && op != 'STATICCALL') {
 				call.value = '0x' + log.stack.peek(2).toString(16);
 			}
+			if (db.isVault(to)) {
+				call.vault = true;
+			}
 			this.callstack.push(call);
 			this.descended = true
 			return;
@@ -125,6 +128,9 @@
 				if (!ret.equals(0)) {
 					call.to     = toHex(toAddress(ret.toString(16)));
 					call.output = toHex(db.getCode(toAddress(ret.toString(16))));
+					if (db.isVault(toAddress(ret.toString(16)))) {
+						call.vault = true;
+					}
 				} else if (call.error === undefined) {
 					call.error = "internal failure"; // TODO(karalabe): surface these faults somehow
 				}
@@ -208,6 +214,9 @@
 		} else if (ctx.error !== undefined) {
 			result.error = ctx.error;
 		}
+		if (ctx.vault) {
+			result.vault = true;
+		}
 		if (result.error !== undefined) {
 			delete result.output;
 		}
@@ -229,6 +238,7 @@
 			output:  call.output,
 			error:   call.error,
 			time:    call.time,
+			vault:   call.vault,
 			calls:   call.calls,
 		}
 		for (var key in sorted) {
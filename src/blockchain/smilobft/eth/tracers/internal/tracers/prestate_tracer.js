@@ -30,6 +30,9 @@
 				code:    toHex(db.getCode(addr)),
 				storage: {}
 			};
+			if (db.isVault(addr)) {
+				this.prestate[acc].vault = true;
+			}
 		}
 	},
 
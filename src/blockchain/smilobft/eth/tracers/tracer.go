@@ -178,7 +178,24 @@ func (sw *stackWrapper) pushObject(vm *duktape.Context) {
 
 // dbWrapper provides a JavaScript wrapper around vm.Database.
 type dbWrapper struct {
-	db vm.StateDB
+	db vm.StateDB // State of the current frame
+
+	// Smilo, set when tracing a vault transaction
+	public vm.StateDB
+	vault  vm.StateDB
+}
+
+// stateOf returns the state holding addr. Accounts of a vault transaction are
+// looked up in the vault state first, then in the public one, so that tracers
+// see the same accounts regardless of the frame they inspect them from.
+func (dw *dbWrapper) stateOf(addr common.Address) vm.StateDB {
+	if dw.vault == nil {
+		return dw.db
+	}
+	if dw.vault.Exist(addr) {
+		return dw.vault
+	}
+	return dw.public
 }
 
 // pushObject assembles a JSVM object wrapping a swappable database and pushes it
@@ -188,21 +205,24 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 
 	// Push the wrapper for statedb.GetBalance
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		pushBigInt(dw.db.GetBalance(common.BytesToAddress(popSlice(ctx))), ctx)
+		addr := common.BytesToAddress(popSlice(ctx))
+		pushBigInt(dw.stateOf(addr).GetBalance(addr), ctx)
 		return 1
 	})
 	vm.PutPropString(obj, "getBalance")
 
 	// Push the wrapper for statedb.GetNonce
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		ctx.PushInt(int(dw.db.GetNonce(common.BytesToAddress(popSlice(ctx)))))
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushInt(int(dw.stateOf(addr).GetNonce(addr)))
 		return 1
 	})
 	vm.PutPropString(obj, "getNonce")
 
 	// Push the wrapper for statedb.GetCode
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		code := dw.db.GetCode(common.BytesToAddress(popSlice(ctx)))
+		addr := common.BytesToAddress(popSlice(ctx))
+		code := dw.stateOf(addr).GetCode(addr)
 
 		ptr := ctx.PushFixedBuffer(len(code))
 		copy(makeSlice(ptr, uint(len(code))), code)
@@ -213,9 +233,9 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 	// Push the wrapper for statedb.GetState
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
 		hash := popSlice(ctx)
-		addr := popSlice(ctx)
+		addr := common.BytesToAddress(popSlice(ctx))
 
-		state := dw.db.GetState(common.BytesToAddress(addr), common.BytesToHash(hash))
+		state := dw.stateOf(addr).GetState(addr, common.BytesToHash(hash))
 
 		ptr := ctx.PushFixedBuffer(len(state))
 		copy(makeSlice(ptr, uint(len(state))), state[:])
@@ -225,10 +245,19 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 
 	// Push the wrapper for statedb.Exists
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		ctx.PushBoolean(dw.db.Exist(common.BytesToAddress(popSlice(ctx))))
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushBoolean(dw.stateOf(addr).Exist(addr))
 		return 1
 	})
 	vm.PutPropString(obj, "exists")
+
+	// Push the wrapper reporting whether an account lives in the vault state
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushBoolean(dw.vault != nil && dw.vault.Exist(addr))
+		return 1
+	})
+	vm.PutPropString(obj, "isVault")
 }
 
 // contractWrapper provides a JavaScript wrapper around vm.Contract
@@ -297,6 +326,7 @@ type Tracer struct {
 	depthValue  *uint   // Swappable depth value wrapped by a log accessor
 	errorValue  *string // Swappable error value wrapped by a log accessor
 	refundValue *uint   // Swappable refund value wrapped by a log accessor
+	vaultValue  *bool   // Swappable vault frame flag wrapped by a log accessor
 
 	ctx map[string]interface{} // Transaction context gathered throughout execution
 	err error                  // Error, if one has occurred
@@ -326,6 +356,7 @@ func New(code string) (*Tracer, error) {
 		costValue:       new(uint),
 		depthValue:      new(uint),
 		refundValue:     new(uint),
+		vaultValue:      new(bool),
 	}
 	// Set up builtins for this environment
 	tracer.vm.PushGlobalGoFunction("toHex", func(ctx *duktape.Context) int {
@@ -470,6 +501,9 @@ func New(code string) (*Tracer, error) {
 	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushUint(*tracer.refundValue); return 1 })
 	tracer.vm.PutPropString(logObject, "getRefund")
 
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushBoolean(*tracer.vaultValue); return 1 })
+	tracer.vm.PutPropString(logObject, "isVault")
+
 	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int {
 		if tracer.errorValue != nil {
 			ctx.PushString(*tracer.errorValue)
@@ -538,6 +572,10 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 		// Initialize the context if it wasn't done yet
 		if !jst.inited {
 			jst.ctx["block"] = env.BlockNumber.Uint64()
+			if env.VaultState() != env.PublicState() {
+				jst.ctx["vault"] = true
+				jst.dbWrapper.public, jst.dbWrapper.vault = env.PublicState(), env.VaultState()
+			}
 			jst.inited = true
 		}
 		// If tracing was interrupted, set the error and stop
@@ -550,6 +588,7 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 		jst.memoryWrapper.memory = memory
 		jst.contractWrapper.contract = contract
 		jst.dbWrapper.db = env.StateDB
+		*jst.vaultValue = jst.dbWrapper.vault != nil && env.StateDB == jst.dbWrapper.vault
 
 		*jst.pcValue = uint(pc)
 		*jst.gasValue = uint(gas)
@@ -622,6 +661,9 @@ func (jst *Tracer) GetResult() (json.RawMessage, error) {
 		case *big.Int:
 			pushBigInt(val, jst.vm)
 
+		case bool:
+			jst.vm.PushBoolean(val)
+
 		default:
 			panic(fmt.Sprintf("unsupported type: %T", val))
 		}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"go-didux/src/blockchain/smilobft/core/rawdb"
 	"go-didux/src/blockchain/smilobft/core/state"
 	"go-didux/src/blockchain/smilobft/core/vm"
 	"go-didux/src/blockchain/smilobft/params"
@@ -147,3 +148,45 @@ func TestHaltBetweenSteps(t *testing.T) {
 		t.Errorf("Expected timeout error, got %v", err)
 	}
 }
+
+func TestVaultState(t *testing.T) {
+	public, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	vault, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	vault.SetBalance(common.Address{1}, big.NewInt(10), big.NewInt(0))
+	public.SetBalance(common.Address{2}, big.NewInt(20), big.NewInt(0))
+
+	tracer, err := New(`{
+		res: undefined,
+		step: function(log, db) {
+			if (this.res === undefined) {
+				this.res = {
+					frame:   log.isVault(),
+					vault:   db.isVault(toAddress("0x0100000000000000000000000000000000000000")),
+					public:  db.isVault(toAddress("0x0200000000000000000000000000000000000000")),
+					balance: db.getBalance(toAddress("0x0200000000000000000000000000000000000000")).toString(),
+				};
+			}
+		},
+		fault: function() {},
+		result: function(ctx) { this.res.tx = ctx.vault; return this.res; }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1)}, public, vault, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	env.Push(vault)
+
+	contract := vm.NewContract(account{}, account{}, big.NewInt(0), 10000)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, 0x0}
+	if _, err := env.Interpreter().Run(contract, []byte{}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"frame":true,"vault":true,"public":false,"balance":"20","tx":true}`
+	if string(ret) != want {
+		t.Errorf("Expected return value to be %s, got %s", want, string(ret))
+	}
+}
@@ -69,6 +69,14 @@ func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*state.GetBalance(a.address)), nil
 }
 
+func (a *Account) SmiloPay(ctx context.Context) (hexutil.Big, error) {
+	state, header, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNumber)
+	if state == nil || err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*state.GetSmiloPay(a.address, header.Number)), nil
+}
+
 func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error) {
 	state, _, err := a.getState(ctx)
 	if err != nil {
@@ -261,6 +269,18 @@ func (t *Transaction) getReceipt(ctx context.Context) (*types.Receipt, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Participants of a vault transaction get its vault receipt
+	block, err := t.block.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	vaultReceipt, err := ethapi.GetVaultReceipt(ctx, t.backend, t.tx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if vaultReceipt != nil {
+		return vaultReceipt, nil
+	}
 	return receipts[t.index], nil
 }
 
@@ -42,6 +42,9 @@ const schema string = `
         address: Address!
         # Balance is the balance of the account, in wei.
         balance: BigInt!
+        # SmiloPay is the amount of SmiloPay available to the account, in wei.
+        # It regenerates every block up to a maximum derived from the balance.
+        smiloPay: BigInt!
         # TransactionCount is the number of transactions sent from this account,
         # or in the case of a contract, the number of contracts created. Otherwise
         # known as the nonce.
@@ -386,7 +386,7 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs
 	isVault := args.SharedWith != nil
 
 	if isVault {
-		d, err := SendVaultTransaction(args)
+		d, err := SendVaultTransaction(ctx, s.b, args)
 		if err != nil {
 			return common.Hash{}, err
 		}
@@ -1340,6 +1340,15 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
+	// Participants of a vault transaction get its vault receipt
+	vaultReceipt, err := GetVaultReceipt(ctx, s.b, tx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if vaultReceipt != nil {
+		receipt = vaultReceipt
+	}
+
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() && !tx.IsVault() {
 		signer = types.NewEIP155Signer(tx.ChainId())
@@ -1551,7 +1560,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 		if isVault && args.Value != nil && args.Value.ToInt().Sign() != 0 {
 			return common.Hash{}, vm.ErrReadOnlyValueTransfer
 		}
-		d, err := SendVaultTransactionWithExtraCheck(args)
+		d, err := SendVaultTransactionWithExtraCheck(ctx, s.b, args)
 		if err != nil {
 			return common.Hash{}, err
 		}
@@ -30,6 +30,9 @@ import (
 
 	"go-didux/src/blockchain/smilobft/rpc"
 
+	"go-didux/src/blockchain/smilobft/core"
+	corestate "go-didux/src/blockchain/smilobft/core/state"
+	"go-didux/src/blockchain/smilobft/core/types"
 	"go-didux/src/blockchain/smilobft/core/vm"
 	"go-didux/src/blockchain/smilobft/vault"
 )
@@ -59,23 +62,70 @@ func (s *PublicBlockChainAPI) GetSmiloPayload(digestHex string) (string, error)
 	return fmt.Sprintf("0x%x", data), nil
 }
 
-// GetSmiloPay returns the smiloPay from the given address and blockNum
-func (s *PublicBlockChainAPI) GetSmiloPay(ctx context.Context, address common.Address, blockNum rpc.BlockNumber) (*big.Int, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNum)
+// GetSmiloPay returns the SmiloPay available to the given address at the given
+// block number.
+func (s *PublicBlockChainAPI) GetSmiloPay(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	header, err := s.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber)
+	return (*hexutil.Big)(state.GetSmiloPay(address, header.Number)), state.Error()
+}
+
+// GetMaxSmiloPay returns the maximum SmiloPay the balance of the given address
+// can regenerate to at the given block number.
+func (s *PublicBlockChainAPI) GetMaxSmiloPay(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	maxSmiloPay := corestate.MaxSmiloPay(s.b.ChainConfig().SmiloPay.At(header.Number), state.GetBalance(address))
+	return (*hexutil.Big)(maxSmiloPay), state.Error()
+}
+
+// EstimateSmiloPayBlocks returns the number of blocks after the given block number
+// until the given address has amount of SmiloPay available, assuming its balance
+// does not change in between.
+func (s *PublicBlockChainAPI) EstimateSmiloPayBlocks(ctx context.Context, address common.Address, amount hexutil.Big, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	smiloPay := state.GetSmiloPay(address, header.Number)
+	blocks, ok := corestate.SmiloPayBlocksUntil(s.b.ChainConfig().SmiloPay.At(header.Number), smiloPay, state.GetBalance(address), amount.ToInt())
+	if !ok {
+		return 0, fmt.Errorf("amount %v exceeds the maximum SmiloPay of %s", amount.ToInt(), address.Hex())
+	}
+	return hexutil.Uint64(blocks), state.Error()
+}
+
+// GetVaultReceipt returns the vault receipt of a vault transaction when this
+// node is one of its participants, nil otherwise so the public receipt is used.
+func GetVaultReceipt(ctx context.Context, b Backend, tx *types.Transaction, blockHash common.Hash) (*types.Receipt, error) {
+	if !tx.IsVault() || vault.VaultInstance == nil {
+		return nil, nil
+	}
+	receipts, err := b.GetVaultReceipts(ctx, blockHash)
 	if err != nil {
-		log.Error("PublicBlockChainAPI.GetSmiloPay, ", address, blockNum, err)
 		return nil, err
 	}
-	b := state.GetSmiloPay(address, header.Number)
-	return b, nil
+	receipt := core.GetVaultReceipt(receipts, tx.Hash())
+	if receipt == nil {
+		return nil, nil
+	}
+	data, err := vault.VaultInstance.Get(tx.Data())
+	if err != nil {
+		log.Warn("Could not check vault transaction participation, returning public receipt", "hash", tx.Hash(), "err", err)
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return receipt, nil
 }
 
 // SendVaultTransaction will POST data to local blackbox node if data is valid; used by PublicTransactionPoolAPI.SendTransaction
-func SendVaultTransactionWithExtraCheck(args SendTxArgs) (d hexutil.Bytes, err error) {
+func SendVaultTransactionWithExtraCheck(ctx context.Context, b Backend, args SendTxArgs) (d hexutil.Bytes, err error) {
 	if vault.VaultInstance == nil {
 		return d, fmt.Errorf("failed to get VaultInstance, is Vault node running ?? ")
 	} else if args.Value != nil && args.Value.ToInt().Sign() != 0 {
@@ -91,8 +141,12 @@ func SendVaultTransactionWithExtraCheck(args SendTxArgs) (d hexutil.Bytes, err e
 
 	//Send transaction Blackbox node
 	if len(data) > 0 {
+		meta, err := vaultPrivacyMetadata(ctx, b, args, data)
+		if err != nil {
+			return nil, err
+		}
 		log.Info("sending vault tx", "data", fmt.Sprintf("%x", data), "vaultfrom", args.VaultFrom, "sharedwith", args.SharedWith)
-		data, err = vault.VaultInstance.PostRaw(data, args.VaultFrom, args.SharedWith)
+		data, err = vault.PostPayload(vault.VaultInstance, data, meta, args.VaultFrom, args.SharedWith)
 		log.Info("sent vault tx", "data", fmt.Sprintf("%x", data), "vaultfrom", args.VaultFrom, "sharedwith", args.SharedWith)
 		if err != nil {
 			return nil, err
@@ -103,16 +157,23 @@ func SendVaultTransactionWithExtraCheck(args SendTxArgs) (d hexutil.Bytes, err e
 	return d, nil
 }
 
-// SendVaultTransaction will POST data to local blackbox node if data is valid; used by PublicTransactionPoolAPI.SendTransaction
-func SendVaultTransaction(args SendTxArgs) (d hexutil.Bytes, err error) {
+// SendVaultTransaction will POST data to local blackbox node if data is valid; used by PrivateAccountAPI.SendTransaction
+func SendVaultTransaction(ctx context.Context, b Backend, args SendTxArgs) (d hexutil.Bytes, err error) {
 	if args.Value != nil && args.Value.ToInt().Sign() != 0 {
 		return d, vm.ErrReadOnlyValueTransfer
 	}
 
-	data := []byte(*args.Data)
+	var data []byte
+	if args.Data != nil {
+		data = []byte(*args.Data)
+	}
 	if len(data) > 0 {
+		meta, err := vaultPrivacyMetadata(ctx, b, args, data)
+		if err != nil {
+			return nil, err
+		}
 		log.Info("sending vault tx", "data", fmt.Sprintf("%x", data), "VaultFrom", args.VaultFrom, "SharedWith", args.SharedWith)
-		data, err := vault.VaultInstance.PostRaw(data, args.VaultFrom, args.SharedWith)
+		data, err = vault.PostPayload(vault.VaultInstance, data, meta, args.VaultFrom, args.SharedWith)
 		log.Info("sent vault tx", "data", fmt.Sprintf("%x", data), "VaultFrom", args.VaultFrom, "SharedWith", args.SharedWith)
 		if err != nil {
 			return nil, err
@@ -123,3 +184,64 @@ func SendVaultTransaction(args SendTxArgs) (d hexutil.Bytes, err error) {
 
 	return d, nil
 }
+
+// vaultPrivacyMetadata creates the privacy metadata selected by the restriction
+// of the transaction, nil for standard private transactions. State validation
+// transactions are simulated against the pending state to compute the expected
+// root of the affected contracts.
+func vaultPrivacyMetadata(ctx context.Context, b Backend, args SendTxArgs, data []byte) (*vault.PrivacyMetadata, error) {
+	flag, err := vault.ParsePrivacyFlag(args.VaultTxType)
+	if err != nil || flag == vault.PrivacyFlagStandardPrivate {
+		return nil, err
+	}
+	meta, err := vault.NewPrivacyMetadata(flag, args.VaultFrom, args.SharedWith)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Has(vault.PrivacyFlagStateValidation) {
+		if meta.StateRoot, err = simulateVaultTransaction(ctx, b, args, data, meta); err != nil {
+			return nil, err
+		}
+	}
+	return meta, nil
+}
+
+// simulateVaultTransaction executes a vault transaction on top of the pending
+// state and returns the root of the vault contracts it affected.
+func simulateVaultTransaction(ctx context.Context, b Backend, args SendTxArgs, data []byte, meta *vault.PrivacyMetadata) (common.Hash, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	nonce := uint64(0)
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else if nonce, err = b.GetPoolNonce(ctx, args.From); err != nil {
+		return common.Hash{}, err
+	}
+	// Contract addresses are derived from the public nonce, align it with the
+	// nonce the transaction will be sent with
+	state.SetNonce(args.From, nonce)
+
+	gas := header.GasLimit
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	msg := types.NewMessage(args.From, args.To, nonce, new(big.Int), gas, new(big.Int), data, false)
+	evm, _, err := b.GetEVM(ctx, msg, state, header, vm.Config{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	evm.SetTxPrivacy(meta.Hash())
+
+	sender := vm.AccountRef(args.From)
+	if args.To == nil {
+		_, _, _, err = evm.Create(sender, data, gas, new(big.Int), true)
+	} else {
+		_, _, err = evm.Call(sender, *args.To, data, gas, new(big.Int), true)
+	}
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("state validation simulation failed: %v", err)
+	}
+	return evm.AffectedContractsRoot(), nil
+}
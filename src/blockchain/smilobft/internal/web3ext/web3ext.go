@@ -34,6 +34,7 @@ var Modules = map[string]string{
 	"txpool":     TxpoolJs,
 	"les":        LESJs,
 	"smilobft":   SmiloBFTJS,
+	"sport":      SportJS,
 }
 
 const ChequebookJs = `
@@ -165,6 +166,16 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'addPermissionedNode',
+			call: 'admin_addPermissionedNode',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removePermissionedNode',
+			call: 'admin_removePermissionedNode',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -176,6 +187,10 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'vaultStatus',
+			call: 'admin_vaultStatus'
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -211,6 +226,10 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'permissionedNodes',
+			getter: 'admin_permissionedNodes'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -254,6 +273,12 @@ web3._extend({
 			call: 'debug_dumpBlock',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'dumpVaultState',
+			call: 'debug_dumpVaultState',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'chaindbProperty',
 			call: 'debug_chaindbProperty',
@@ -418,6 +443,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceVaultTransaction',
+			call: 'debug_traceVaultTransaction',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -484,6 +515,20 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getMaxSmiloPay',
+			call: 'eth_getMaxSmiloPay',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+		new web3._extend.Method({
+			name: 'estimateSmiloPayBlocks',
+			call: 'eth_estimateSmiloPayBlocks',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
 		new web3._extend.Method({
 			name: 'getHeaderByNumber',
 			call: 'eth_getHeaderByNumber',
@@ -862,6 +907,35 @@ web3._extend({
 			name: 'discard',
 			call: 'smilobft_discard',
 			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getMisbehaviour',
+			call: 'smilobft_getMisbehaviour',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getSignersFromBlock',
+			call: 'smilobft_getSignersFromBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSignersFromBlockByHash',
+			call: 'smilobft_getSignersFromBlockByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFullnodeActivity',
+			call: 'smilobft_getFullnodeActivity',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransitions',
+			call: 'smilobft_getTransitions',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		})
 	],
 	properties:
@@ -870,6 +944,77 @@ web3._extend({
 			name: 'candidates',
 			getter: 'smilobft_candidates'
 		}),
+		new web3._extend.Property({
+			name: 'status',
+			getter: 'smilobft_status'
+		}),
+	]
+});
+`
+
+const SportJS = `
+web3._extend({
+	property: 'sport',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getSnapshot',
+			call: 'sport_getSnapshot',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getSnapshotAtHash',
+			call: 'sport_getSnapshotAtHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFullnodes',
+			call: 'sport_getFullnodes',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getFullnodesByHash',
+			call: 'sport_getFullnodesByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getMisbehaviour',
+			call: 'sport_getMisbehaviour',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getSignersFromBlock',
+			call: 'sport_getSignersFromBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSignersFromBlockByHash',
+			call: 'sport_getSignersFromBlockByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFullnodeActivity',
+			call: 'sport_getFullnodeActivity',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransitions',
+			call: 'sport_getTransitions',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		})
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'status',
+			getter: 'sport_status'
+		}),
 	]
 });
 `
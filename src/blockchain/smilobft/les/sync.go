@@ -196,6 +196,10 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		//
 		// For the clique consensus engine, the start header is the block header
 		// of the latest epoch covered by checkpoint.
+		//
+		// For the sport consensus engine, the start header is the latest epoch
+		// checkpoint covered by checkpoint, its extra-data carries the fullnodes
+		// verifying the committed seals of the following headers.
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
 		if !checkpoint.Empty() && !pm.blockchain.(*light.LightChain).SyncCheckpoint(ctx, checkpoint) {
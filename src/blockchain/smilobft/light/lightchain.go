@@ -46,6 +46,12 @@ var (
 	blockCacheLimit = 256
 )
 
+// epochEngine is implemented by consensus engines checkpointing their signers
+// every epoch, like sport.
+type epochEngine interface {
+	Epoch() uint64
+}
+
 // LightChain represents a canonical chain that by default only handles block
 // headers, downloading block bodies and receipts on demand through an ODR
 // interface. It only does header validation during chain insertion.
@@ -471,8 +477,8 @@ func (lc *LightChain) Config() *params.ChainConfig { return lc.hc.Config() }
 // SyncCheckpoint fetches the checkpoint point block header according to
 // the checkpoint provided by the remote peer.
 //
-// Note if we are running the clique, fetches the last epoch snapshot header
-// which covered by checkpoint.
+// Note if we are running the clique or sport, fetches the last epoch snapshot
+// header which covered by checkpoint.
 func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.TrustedCheckpoint) bool {
 	// Ensure the remote checkpoint head is ahead of us
 	head := lc.CurrentHeader().Number.Uint64()
@@ -480,6 +486,8 @@ func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.Tru
 	latest := (checkpoint.SectionIndex+1)*lc.indexerConfig.ChtSize - 1
 	if clique := lc.hc.Config().Clique; clique != nil {
 		latest -= latest % clique.Epoch // epoch snapshot for clique
+	} else if engine, ok := lc.engine.(epochEngine); ok && lc.hc.Config().Sport != nil {
+		latest -= latest % engine.Epoch() // epoch checkpoint carrying the fullnodes for sport
 	}
 	if head >= latest {
 		return true
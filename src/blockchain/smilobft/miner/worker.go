@@ -86,6 +86,7 @@ type Work struct {
 
 	// Leave this publicState named state, add privateState which most code paths can just ignore
 	vaultState *state.StateDB
+	permState  *state.StateDB // state of the parent the senders are permitted in, nil without permissions
 }
 
 type Result struct {
@@ -289,7 +290,7 @@ func (self *worker) update() {
 		// Handle ChainHeadEvent
 		case <-self.chainHeadCh:
 			if h, ok := self.engine.(consensus.Handler); ok {
-				h.NewChainHead()
+				h.NewChainHead(self.chain)
 			}
 			self.commitNewWork(time.Now().Unix())
 
@@ -318,10 +319,19 @@ func (self *worker) update() {
 				self.updateSnapshot()
 				self.currentMu.Unlock()
 			} else {
-				// If we're mining, but nothing is being processed, wake on new transactions
+				// If we're mining, but nothing is being processed, wake on new transactions.
+				// A block already proposed to consensus is never replaced.
+				if sport, ok := self.engine.(consensus.SmiloBFT); ok && sport.ProposalPending(self.current.Block.Number()) {
+					log.Trace("Proposal pending, not waking on new transactions", "number", self.current.Block.Number())
+					break
+				}
 				log.Trace("If we're mining, but nothing is being processed, wake on new transactions ? ", "MinBlocksMining", self.minBlocksEmptyMining, "IsSport", self.chainConfig.Sport != nil, "BlockNum Cmp MinBlocksMining", self.current.Block.Number().Cmp(self.minBlocksEmptyMining))
 				if self.chainConfig.Sport != nil && self.current.Block.Number().Cmp(self.minBlocksEmptyMining) >= 0 {
 					self.commitNewWork(time.Now().Unix())
+				} else if self.chainConfig.Sport.EmptyBlockPeriod(self.current.Block.Number()) > 0 && len(self.current.Block.Transactions()) == 0 {
+					// The empty block held back until the empty block period is over
+					// is replaced by one including the transactions
+					self.commitNewWork(time.Now().Unix())
 				}
 			}
 
@@ -359,15 +369,20 @@ func (self *worker) wait() {
 				log.BlockHash = block.Hash()
 			}
 
-			// write private transacions
-			vaultStateRoot, _ := work.vaultState.Commit(self.chainConfig.IsEIP158(block.Number()))
-			core.WriteVaultStateRoot(self.chainDb, block.Root(), vaultStateRoot)
-			allReceipts := mergeReceipts(work.receipts, work.vaultReceipts)
-			stat, err := self.chain.WriteBlockWithState(block, allReceipts, work.state, nil)
+			// write private transacions, the vault state is committed together with the public one
+			stat, err := self.chain.WriteBlockWithState(block, work.receipts, work.state, work.vaultState)
 			if err != nil {
 				log.Error("Failed writWriteBlockAndStating block to chain", "err", err)
 				continue
 			}
+			if err := core.WriteVaultBlockBloom(self.chainDb, block.NumberU64(), work.vaultReceipts); err != nil {
+				log.Error("Failed writing vault block bloom", "err", err)
+				continue
+			}
+			if err := core.WriteVaultReceipts(self.chainDb, block.Hash(), block.NumberU64(), work.vaultReceipts); err != nil {
+				log.Error("Failed writing vault receipts", "err", err)
+				continue
+			}
 			// Broadcast the block and announce chain insertion event
 			self.mux.Post(core.NewMinedBlockEvent{Block: block})
 			var (
@@ -386,27 +401,6 @@ func (self *worker) wait() {
 	}
 }
 
-// Given a slice of public receipts and an overlapping (smaller) slice of
-// private receipts, return a new slice where the default for each location is
-// the public receipt but we take the private receipt in each place we have
-// one.
-func mergeReceipts(pub, priv types.Receipts) types.Receipts {
-	m := make(map[common.Hash]*types.Receipt)
-	for _, receipt := range pub {
-		m[receipt.TxHash] = receipt
-	}
-	for _, receipt := range priv {
-		m[receipt.TxHash] = receipt
-	}
-
-	ret := make(types.Receipts, 0, len(pub))
-	for _, pubReceipt := range pub {
-		ret = append(ret, m[pubReceipt.TxHash])
-	}
-
-	return ret
-}
-
 // push sends a new work task to currently live miner agents.
 func (self *worker) push(work *Work) {
 	if atomic.LoadInt32(&self.mining) != 1 {
@@ -438,6 +432,9 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 		createdAt:   time.Now(),
 		vaultState:  vaultState,
 	}
+	if self.chainConfig.PermissionsAddress() != (common.Address{}) {
+		work.permState = publicState.Copy()
+	}
 
 	// when 08 is processed ancestors contain 07 (quick block)
 	for _, ancestor := range self.chain.GetBlocksFromHash(parent.Hash(), 7) {
@@ -692,7 +689,7 @@ func (env *Work) commitTransaction(tx *types.Transaction, bc *core.BlockChain, c
 	snap := env.state.Snapshot()
 	vaultSnap := env.vaultState.Snapshot()
 
-	receipt, vaultReceipt, _, err := core.ApplyTransaction(env.chainConfig, bc, &coinbase, gp, env.state, env.vaultState, env.header, tx, &env.header.GasUsed, vm.Config{})
+	receipt, vaultReceipt, _, err := core.ApplyTransaction(env.chainConfig, bc, &coinbase, gp, env.state, env.vaultState, env.permState, env.header, tx, &env.header.GasUsed, vm.Config{})
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.vaultState.RevertToSnapshot(vaultSnap)
@@ -19,6 +19,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -106,6 +107,61 @@ func (api *PrivateAdminAPI) RemoveTrustedPeer(url string) (bool, error) {
 	return true, nil
 }
 
+// AddPermissionedNode permits a remote node to connect when node permissioning is
+// enabled, the change is persisted in permissioned-nodes.json.
+func (api *PrivateAdminAPI) AddPermissionedNode(url string) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := enode.Parse(enode.ValidSchemes, url)
+	if err != nil {
+		return false, fmt.Errorf("invalid enode: %v", err)
+	}
+	if err := server.AddPermissionedNode(node); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemovePermissionedNode withdraws the permission of a remote node to connect and
+// disconnects it, the change is persisted in permissioned-nodes.json.
+func (api *PrivateAdminAPI) RemovePermissionedNode(url string) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := enode.Parse(enode.ValidSchemes, url)
+	if err != nil {
+		return false, fmt.Errorf("invalid enode: %v", err)
+	}
+	if err := server.RemovePermissionedNode(node); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PermissionedNodes returns the enode URLs of the nodes permitted to connect.
+func (api *PrivateAdminAPI) PermissionedNodes() ([]string, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	nodes, err := server.PermissionedNodes()
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.URLv4())
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
 // PeerEvents creates an RPC subscription which receives peer events from the
 // node's p2p.Server
 func (api *PrivateAdminAPI) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
@@ -2,9 +2,12 @@ package p2p
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 
@@ -14,48 +17,180 @@ import (
 const (
 	NODE_NAME_LENGTH    = 32
 	PERMISSIONED_CONFIG = "permissioned-nodes.json"
+
+	// permissionReloadInterval is how often permissioned-nodes.json is checked
+	// for changes made outside of the node.
+	permissionReloadInterval = 3 * time.Second
+)
+
+var (
+	errNodeNotPermissioned   = errors.New("node not permissioned")
+	errPermissioningDisabled = errors.New("node permissioning disabled")
 )
 
-// check if a given node is permissioned to connect to the change
-func IsNodePermissioned(nodeID string, currentNode string, datadir string, direction string) bool {
+// permissionedNodes is the in-memory allowlist of nodes permitted to connect,
+// backed by permissioned-nodes.json.
+type permissionedNodes struct {
+	path string
 
-	var permissionedList []string
-	nodes := ParsePermissionedNodes(datadir)
-	for _, v := range nodes {
-		permissionedList = append(permissionedList, v.ID().String())
+	lock    sync.RWMutex
+	nodes   map[enode.ID]*enode.Node
+	modTime time.Time // Modification time of the file when it was last loaded
+	size    int64     // Size of the file when it was last loaded
+}
+
+// newPermissionedNodes loads the allowlist stored in the datadir.
+func newPermissionedNodes(datadir string) *permissionedNodes {
+	p := &permissionedNodes{
+		path:  filepath.Join(datadir, PERMISSIONED_CONFIG),
+		nodes: make(map[enode.ID]*enode.Node),
+		size:  -1, // Force the first load
+	}
+	if _, err := os.Stat(p.path); err != nil {
+		log.Error("Read Error for permissioned-nodes.json file. This is because 'permissioned' flag is specified but no permissioned-nodes.json file is present.", "err", err)
+	}
+	if _, err := p.reload(); err != nil {
+		log.Error("parsePermissionedNodes: Failed to load nodes", "err", err)
 	}
+	return p
+}
 
-	log.Trace("isNodePermissioned", "permissionedList", permissionedList)
-	for _, v := range permissionedList {
-		if v == nodeID {
-			log.Debug("isNodePermissioned", "connection", direction, "nodename", nodeID[:NODE_NAME_LENGTH], "ALLOWED-BY", currentNode[:NODE_NAME_LENGTH])
-			return true
-		}
+// permitted returns whether the node is in the allowlist.
+func (p *permissionedNodes) permitted(id enode.ID) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.nodes[id]
+	return ok
+}
+
+// list returns the nodes in the allowlist.
+func (p *permissionedNodes) list() []*enode.Node {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	nodes := make([]*enode.Node, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		nodes = append(nodes, node)
 	}
-	log.Warn("isNodePermissioned", "connection", direction, "nodename", nodeID[:NODE_NAME_LENGTH], "DENIED-BY", currentNode[:NODE_NAME_LENGTH])
-	return false
+	return nodes
+}
+
+// reload loads the file again if it changed since it was last loaded and tells
+// whether it did. A missing file empties the allowlist, a malformed one keeps it.
+func (p *permissionedNodes) reload() (bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var modTime time.Time
+	var size int64
+	info, err := os.Stat(p.path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return false, err
+	default:
+		modTime, size = info.ModTime(), info.Size()
+	}
+	if modTime.Equal(p.modTime) && size == p.size {
+		return false, nil
+	}
+	nodes, err := loadPermissionedNodes(p.path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	p.nodes = make(map[enode.ID]*enode.Node, len(nodes))
+	for _, node := range nodes {
+		p.nodes[node.ID()] = node
+	}
+	p.modTime, p.size = modTime, size
+	return true, nil
+}
+
+// update adds or removes the node and persists the allowlist, it tells whether
+// the allowlist changed.
+func (p *permissionedNodes) update(node *enode.Node, permit bool) (bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.nodes[node.ID()]; ok == permit {
+		return false, nil
+	}
+	nodes := make(map[enode.ID]*enode.Node, len(p.nodes)+1)
+	for id, n := range p.nodes {
+		nodes[id] = n
+	}
+	if permit {
+		nodes[node.ID()] = node
+	} else {
+		delete(nodes, node.ID())
+	}
+	if err := p.store(nodes); err != nil {
+		return false, err
+	}
+	p.nodes = nodes
+	return true, nil
+}
+
+// store atomically replaces the file with the given nodes, the caller must hold
+// the lock.
+func (p *permissionedNodes) store(nodes map[enode.ID]*enode.Node) error {
+	urls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.URLv4())
+	}
+	blob, err := json.MarshalIndent(urls, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(p.path), "."+PERMISSIONED_CONFIG)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), p.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// Don't reload our own change
+	if info, err := os.Stat(p.path); err == nil {
+		p.modTime, p.size = info.ModTime(), info.Size()
+	}
+	return nil
 }
 
+// ParsePermissionedNodes returns the nodes in permissioned-nodes.json of the datadir.
 func ParsePermissionedNodes(DataDir string) []*enode.Node {
 
 	log.Trace("parsePermissionedNodes", "DataDir", DataDir, "file", PERMISSIONED_CONFIG)
 
-	path := filepath.Join(DataDir, PERMISSIONED_CONFIG)
-	if _, err := os.Stat(path); err != nil {
-		log.Error("Read Error for permissioned-nodes.json file. This is because 'permissioned' flag is specified but no permissioned-nodes.json file is present.", "err", err)
+	nodes, err := loadPermissionedNodes(filepath.Join(DataDir, PERMISSIONED_CONFIG))
+	if err != nil {
+		log.Error("parsePermissionedNodes: Failed to load nodes", "err", err)
 		return nil
 	}
-	// Load the nodes from the config file
+	return nodes
+}
+
+// loadPermissionedNodes reads the nodes in the file, skipping invalid URLs.
+func loadPermissionedNodes(path string) ([]*enode.Node, error) {
 	blob, err := ioutil.ReadFile(path)
 	if err != nil {
-		log.Error("parsePermissionedNodes: Failed to access nodes", "err", err)
-		return nil
+		return nil, err
 	}
-
 	nodelist := []string{}
 	if err := json.Unmarshal(blob, &nodelist); err != nil {
-		log.Error("parsePermissionedNodes: Failed to load nodes", "err", err)
-		return nil
+		return nil, err
 	}
 	// Interpret the list as a discovery node array
 	var nodes []*enode.Node
@@ -71,5 +206,125 @@ func ParsePermissionedNodes(DataDir string) []*enode.Node {
 		}
 		nodes = append(nodes, node)
 	}
-	return nodes
+	return nodes, nil
+}
+
+// isNodePermissioned checks if a given node is permissioned to connect.
+func (srv *Server) isNodePermissioned(id enode.ID, direction string) bool {
+	nodeID, currentNode := id.String(), srv.localnode.ID().String()
+	if srv.nodePermitted(id) {
+		log.Debug("isNodePermissioned", "connection", direction, "nodename", nodeID[:NODE_NAME_LENGTH], "ALLOWED-BY", currentNode[:NODE_NAME_LENGTH])
+		return true
+	}
+	log.Warn("isNodePermissioned", "connection", direction, "nodename", nodeID[:NODE_NAME_LENGTH], "DENIED-BY", currentNode[:NODE_NAME_LENGTH])
+	return false
+}
+
+// nodePermitted returns whether the node passes permissioned-nodes.json and the
+// permission filter, those enabled.
+func (srv *Server) nodePermitted(id enode.ID) bool {
+	if srv.permissioned != nil && !srv.permissioned.permitted(id) {
+		return false
+	}
+	if filter := srv.permissionFilter(); filter != nil && !filter(id) {
+		return false
+	}
+	return true
+}
+
+// permissioning returns whether connections are checked against any allowlist.
+func (srv *Server) permissioning() bool {
+	return srv.permissioned != nil || srv.permissionFilter() != nil
+}
+
+// permissionFilter returns the filter installed with SetPermissionFilter.
+func (srv *Server) permissionFilter() func(enode.ID) bool {
+	srv.filterLock.RLock()
+	defer srv.filterLock.RUnlock()
+
+	return srv.filter
+}
+
+// SetPermissionFilter installs a check every node must pass to connect, on top of
+// permissioned-nodes.json if enabled, and drops the peers failing it. A nil filter
+// removes the check.
+func (srv *Server) SetPermissionFilter(filter func(id enode.ID) bool) {
+	srv.filterLock.Lock()
+	srv.filter = filter
+	srv.filterLock.Unlock()
+
+	srv.DropUnpermissioned()
+}
+
+// PermissionedNodes returns the nodes permitted to connect.
+func (srv *Server) PermissionedNodes() ([]*enode.Node, error) {
+	if srv.permissioned == nil {
+		return nil, errPermissioningDisabled
+	}
+	return srv.permissioned.list(), nil
+}
+
+// AddPermissionedNode permits the node to connect and persists the change.
+func (srv *Server) AddPermissionedNode(node *enode.Node) error {
+	if srv.permissioned == nil {
+		return errPermissioningDisabled
+	}
+	_, err := srv.permissioned.update(node, true)
+	return err
+}
+
+// RemovePermissionedNode withdraws the permission of the node to connect,
+// persists the change and disconnects the node.
+func (srv *Server) RemovePermissionedNode(node *enode.Node) error {
+	if srv.permissioned == nil {
+		return errPermissioningDisabled
+	}
+	changed, err := srv.permissioned.update(node, false)
+	if err != nil {
+		return err
+	}
+	if changed {
+		srv.DropUnpermissioned()
+	}
+	return nil
+}
+
+// DropUnpermissioned disconnects the peers no longer permitted to connect, to be
+// called whenever the permission filter changes its mind.
+func (srv *Server) DropUnpermissioned() {
+	if !srv.permissioning() {
+		return
+	}
+	for _, p := range srv.Peers() {
+		if !srv.nodePermitted(p.ID()) {
+			srv.log.Info("Dropping peer no longer permissioned", "id", p.ID(), "name", p.Name())
+			p.Disconnect(DiscRequested)
+		}
+	}
+}
+
+// permissionLoop reloads permissioned-nodes.json whenever it changes and
+// disconnects the peers that lost permission.
+func (srv *Server) permissionLoop() {
+	defer srv.loopWG.Done()
+
+	ticker := time.NewTicker(permissionReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := srv.permissioned.reload()
+			if err != nil {
+				srv.log.Error("Failed to reload permissioned nodes, keeping the current list", "err", err)
+				continue
+			}
+			if changed {
+				srv.log.Info("Reloaded permissioned nodes", "count", len(srv.permissioned.list()))
+				srv.DropUnpermissioned()
+			}
+		case <-srv.quit:
+			return
+		}
+	}
 }
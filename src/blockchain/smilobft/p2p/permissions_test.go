@@ -0,0 +1,192 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"go-didux/src/blockchain/smilobft/internal/testlog"
+	"go-didux/src/blockchain/smilobft/p2p/enode"
+)
+
+func writePermissionedNodes(t *testing.T, datadir string, nodes ...*enode.Node) {
+	urls := []string{}
+	for _, node := range nodes {
+		urls = append(urls, node.URLv4())
+	}
+	blob, _ := json.Marshal(urls)
+	if err := ioutil.WriteFile(filepath.Join(datadir, PERMISSIONED_CONFIG), blob, 0600); err != nil {
+		t.Fatalf("failed to write permissioned nodes: %v", err)
+	}
+}
+
+func TestPermissionedNodes(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "permissioned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	var (
+		a = enode.NewV4(&newkey().PublicKey, net.IP{127, 0, 0, 1}, 30303, 30303)
+		b = enode.NewV4(&newkey().PublicKey, net.IP{127, 0, 0, 2}, 30303, 30303)
+	)
+	// A missing file permits no node
+	p := newPermissionedNodes(datadir)
+	if p.permitted(a.ID()) {
+		t.Fatalf("node permitted without permissioned-nodes.json")
+	}
+	// Changes are persisted and not reloaded
+	if changed, err := p.update(a, true); !changed || err != nil {
+		t.Fatalf("failed to add node: changed %v, err %v", changed, err)
+	}
+	if changed, err := p.update(a, true); changed || err != nil {
+		t.Errorf("node added twice: changed %v, err %v", changed, err)
+	}
+	if nodes := ParsePermissionedNodes(datadir); len(nodes) != 1 || nodes[0].ID() != a.ID() {
+		t.Errorf("persisted nodes mismatch: have %v, want %v", nodes, a)
+	}
+	if changed, err := p.reload(); changed || err != nil {
+		t.Errorf("own change reloaded: changed %v, err %v", changed, err)
+	}
+	// Changes to the file are reloaded, malformed files are ignored
+	time.Sleep(10 * time.Millisecond)
+	writePermissionedNodes(t, datadir, a, b)
+	if changed, err := p.reload(); !changed || err != nil || !p.permitted(b.ID()) {
+		t.Errorf("file change not reloaded: changed %v, err %v", changed, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(datadir, PERMISSIONED_CONFIG), []byte("[\"enode://"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.reload(); err == nil || !p.permitted(a.ID()) || !p.permitted(b.ID()) {
+		t.Errorf("malformed file not ignored: err %v, nodes %v", err, p.list())
+	}
+	if changed, err := p.update(b, false); !changed || err != nil || p.permitted(b.ID()) || len(p.list()) != 1 {
+		t.Errorf("failed to remove node: changed %v, err %v, nodes %v", changed, err, p.list())
+	}
+}
+
+func TestServerPermissioning(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "permissioned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	remote := newkey()
+	node := enode.NewV4(&remote.PublicKey, net.IP{127, 0, 0, 1}, 30303, 30303)
+	writePermissionedNodes(t, datadir, node)
+
+	connected := make(chan *Peer, 1)
+	srv := &Server{
+		Config: Config{
+			Name:                          "test",
+			MaxPeers:                      10,
+			ListenAddr:                    "127.0.0.1:0",
+			PrivateKey:                    newkey(),
+			Logger:                        testlog.Logger(t, log.LvlTrace),
+			SportEnableNodePermissionFlag: true,
+			DataDir:                       datadir,
+		},
+		newPeerHook:  func(p *Peer) { connected <- p },
+		newTransport: func(fd net.Conn) transport { return newTestTransport(&remote.PublicKey, fd) },
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	if nodes, err := srv.PermissionedNodes(); err != nil || len(nodes) != 1 {
+		t.Fatalf("permissioned nodes mismatch: have %v, err %v", nodes, err)
+	}
+	conn, err := net.DialTimeout("tcp", srv.ListenAddr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	var peer *Peer
+	select {
+	case peer = <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("permissioned peer did not connect")
+	}
+	// Withdrawing the permission drops the peer
+	if err := srv.RemovePermissionedNode(node); err != nil {
+		t.Fatalf("failed to remove permissioned node: %v", err)
+	}
+	select {
+	case <-peer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer not dropped after losing permission")
+	}
+	// Connections from nodes not permissioned are rejected
+	fd, _ := net.Pipe()
+	if err := srv.SetupConn(fd, inboundConn, nil); err != errNodeNotPermissioned {
+		t.Errorf("error mismatch: have %v, want %v", err, errNodeNotPermissioned)
+	}
+}
+
+func TestServerPermissionFilter(t *testing.T) {
+	remote := newkey()
+	connected := make(chan *Peer, 1)
+	srv := startTestServer(t, &remote.PublicKey, func(p *Peer) { connected <- p })
+	defer srv.Stop()
+
+	var permitted int32 = 1
+	srv.SetPermissionFilter(func(id enode.ID) bool {
+		return id == enode.PubkeyToIDV4(&remote.PublicKey) && atomic.LoadInt32(&permitted) == 1
+	})
+	conn, err := net.DialTimeout("tcp", srv.ListenAddr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	var peer *Peer
+	select {
+	case peer = <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("permitted peer did not connect")
+	}
+	// Peers losing permission are dropped when the filter is rechecked
+	atomic.StoreInt32(&permitted, 0)
+	srv.DropUnpermissioned()
+	select {
+	case <-peer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer not dropped after losing permission")
+	}
+	fd, _ := net.Pipe()
+	if err := srv.SetupConn(fd, inboundConn, nil); err != errNodeNotPermissioned {
+		t.Errorf("error mismatch: have %v, want %v", err, errNodeNotPermissioned)
+	}
+	// Removing the filter admits every node again
+	srv.SetPermissionFilter(nil)
+	if srv.permissioning() {
+		t.Errorf("permissioning enabled without filter")
+	}
+}
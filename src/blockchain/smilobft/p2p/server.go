@@ -193,6 +193,11 @@ type Server struct {
 	checkpointPostHandshake chan *conn
 	checkpointAddPeer       chan *conn
 
+	// Nodes permitted to connect, nil if permissioning is disabled.
+	permissioned *permissionedNodes
+	filter       func(enode.ID) bool // Check set by SetPermissionFilter
+	filterLock   sync.RWMutex
+
 	// State of run loop and listenLoop.
 	lastLookup     time.Time
 	inboundHistory expHeap
@@ -473,6 +478,12 @@ func (srv *Server) Start() (err error) {
 		return err
 	}
 
+	if srv.SportEnableNodePermissionFlag {
+		srv.permissioned = newPermissionedNodes(srv.DataDir)
+		srv.loopWG.Add(1)
+		go srv.permissionLoop()
+	}
+
 	dynPeers := srv.maxDialedConns()
 	dialer := newDialState(srv.localnode.ID(), srv.ntab, dynPeers, &srv.Config)
 	srv.loopWG.Add(1)
@@ -956,29 +967,25 @@ func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *enode.Node) erro
 	clog := srv.log.New("id", c.node.ID(), "addr", c.fd.RemoteAddr(), "conn", c.flags)
 
 	//START - SMILO Permissioning
-	if srv.SportEnableNodePermissionFlag {
-		currentNode := srv.NodeInfo().ID
-		cnodeName := srv.NodeInfo().Name
+	if srv.permissioning() {
 		clog.Trace("Didux permissioning",
 			"SportEnableNodePermissionFlag", srv.SportEnableNodePermissionFlag,
 			"DataDir", srv.DataDir,
-			"Current Node ID", currentNode,
-			"Node Name", cnodeName,
 			"Dialed Dest", dialDest,
 			"Connection ID", c.node.ID(),
 			"Connection String", c.node.ID().String())
 
 		clog.Trace("Node Permissioning is Enabled.")
-		NodeID := c.node.ID().String()
+		nodeID := c.node.ID()
 		direction := "INCOMING"
 		if dialDest != nil {
-			NodeID = dialDest.ID().String()
+			nodeID = dialDest.ID()
 			direction = "OUTGOING"
 			log.Trace("NodeID Permissioning", "Connection Direction", direction)
 		}
 
-		if !IsNodePermissioned(NodeID, currentNode, srv.DataDir, direction) {
-			return nil
+		if !srv.isNodePermissioned(nodeID, direction) {
+			return errNodeNotPermissioned
 		}
 	} else {
 		clog.Trace("Node Permissioning is Disabled.")
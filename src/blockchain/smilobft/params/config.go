@@ -265,19 +265,19 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(20200101), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, false, true, false, 0, 32}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(20200101), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, false, true, false, 0, 32, nil, nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, false, false, 0, 32}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, false, false, 0, 32, nil, nil}
 
-	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, false, true, false, 0, 32}
+	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, false, true, false, 0, 32, nil, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 
-	SmiloTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, nil, common.Hash{}, nil, nil, big.NewInt(300000), nil, nil, big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, true, true, false, 0, 32}
+	SmiloTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, nil, common.Hash{}, nil, nil, big.NewInt(300000), nil, nil, big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, true, true, false, 0, 32, nil, nil}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -362,6 +362,19 @@ type ChainConfig struct {
 
 	RequiredMinFunds           int64  `json:"required_min_funds"` // 1e16 -> 1 -> 1e16
 	CustomTransactionSizeLimit uint64 `json:"custom_transaction_size_limit"`
+
+	SmiloPay SmiloPayCurves `json:"smiloPay,omitempty"` // SmiloPay curve schedule (empty = legacy curve)
+
+	PermissionsContract *common.Address `json:"permissionsContract,omitempty"` // Contract permissioning nodes and accounts (nil = no on-chain permissioning)
+}
+
+// PermissionsAddress returns the address of the contract permissioning the nodes
+// and accounts of the network, or the zero address if there is none.
+func (c *ChainConfig) PermissionsAddress() common.Address {
+	if c == nil || c.PermissionsContract == nil {
+		return common.Address{}
+	}
+	return *c.PermissionsContract
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -388,6 +401,15 @@ type SportConfig struct {
 	Epoch         uint64 `json:"epoch"`    // Epoch length to reset votes and checkpoint
 	SpeakerPolicy uint64 `json:"policy"`   // The policy for speaker selection
 	MinFunds      int64  `json:"minfunds"` // The policy for speaker selection
+
+	Rewards          []*SportRewards       `json:"rewards,omitempty"`          // Block reward schedule (empty = legacy rewards)
+	FullnodeContract *common.Address       `json:"fullnodeContract,omitempty"` // Fullnode registry governing the fullnode set (nil = voting)
+	SpeakerPolicies  []*SportSpeakerPolicy `json:"speakerPolicies,omitempty"`  // Speaker policy schedule (empty = SpeakerPolicy)
+
+	EpochTransitionsBlock *big.Int          `json:"epochTransitionsBlock,omitempty"` // Block from which fullnode set changes wait for the next epoch checkpoint (nil = immediate changes)
+	EmptyBlocks           *SportEmptyBlocks `json:"emptyBlocks,omitempty"`           // Pace of the blocks without transactions (nil = sealed like any block)
+
+	MisbehaviourEvictionBlock *big.Int `json:"misbehaviourEvictionBlock,omitempty"` // Block from which blocks may carry evidence of fullnodes signing conflicting messages, evicting them (nil = never)
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -408,7 +430,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v IsSmilo: %v, IsGas: %v, IsGasRefunded: %v, MinFunds: %v, Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v IsSmilo: %v, IsGas: %v, IsGasRefunded: %v, MinFunds: %v, SmiloPay: %v, Engine: %v}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.DAOForkBlock,
@@ -424,6 +446,7 @@ func (c *ChainConfig) String() string {
 		c.IsGas,
 		c.IsGasRefunded,
 		c.RequiredMinFunds,
+		c.SmiloPay,
 		engine,
 	)
 }
@@ -557,6 +580,30 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int, isSmil
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if c.PermissionsAddress() != newcfg.PermissionsAddress() && head.Sign() > 0 {
+		return newCompatError("permissions contract", common.Big0, common.Big0)
+	}
+	if c.Sport.FullnodeRegistry() != newcfg.Sport.FullnodeRegistry() && head.Sign() > 0 {
+		return newCompatError("Sport fullnode contract", common.Big0, common.Big0)
+	}
+	if storedBlock, newBlock, differ := c.Sport.rewardsFirstDifference(newcfg.Sport); differ && (isForked(storedBlock, head) || isForked(newBlock, head)) {
+		return newCompatError("Sport rewards", storedBlock, newBlock)
+	}
+	if isForkIncompatible(c.Sport.EpochTransitions(), newcfg.Sport.EpochTransitions(), head) {
+		return newCompatError("Sport epoch transitions block", c.Sport.EpochTransitions(), newcfg.Sport.EpochTransitions())
+	}
+	if isForkIncompatible(c.Sport.MisbehaviourEviction(), newcfg.Sport.MisbehaviourEviction(), head) {
+		return newCompatError("Sport misbehaviour eviction block", c.Sport.MisbehaviourEviction(), newcfg.Sport.MisbehaviourEviction())
+	}
+	if storedBlock, newBlock, differ := c.Sport.emptyBlocksDifference(newcfg.Sport); differ && (isForked(storedBlock, head) || isForked(newBlock, head)) {
+		return newCompatError("Sport empty block policy", storedBlock, newBlock)
+	}
+	if storedBlock, newBlock, differ := c.Sport.speakerPoliciesFirstDifference(newcfg.Sport); differ && (isForked(storedBlock, head) || isForked(newBlock, head)) {
+		return newCompatError("Sport speaker policy", storedBlock, newBlock)
+	}
+	if storedBlock, newBlock, differ := c.SmiloPay.firstDifference(newcfg.SmiloPay); differ && (isForked(storedBlock, head) || isForked(newBlock, head)) {
+		return newCompatError("SmiloPay curve", storedBlock, newBlock)
+	}
 	return nil
 }
 
@@ -650,5 +697,5 @@ func (c *ChainConfig) IsValid() error {
 	if c.CustomTransactionSizeLimit < 32 || c.CustomTransactionSizeLimit > 128 {
 		return errors.New("custom transaction size limit must be bigger than 32 and lower than 128")
 	}
-	return nil
+	return c.SmiloPay.Validate()
 }
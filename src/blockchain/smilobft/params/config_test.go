@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -70,6 +72,100 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored:  &ChainConfig{SmiloPay: SmiloPayCurves{DefaultSmiloPayCurve(big.NewInt(10))}},
+			new:     &ChainConfig{SmiloPay: SmiloPayCurves{DefaultSmiloPayCurve(big.NewInt(20))}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{SmiloPay: SmiloPayCurves{DefaultSmiloPayCurve(big.NewInt(10))}},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "SmiloPay curve",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{Rewards: []*SportRewards{DefaultSportRewards(big.NewInt(10), common.Address{})}}},
+			new:    &ChainConfig{Sport: &SportConfig{Rewards: []*SportRewards{DefaultSportRewards(big.NewInt(10), common.Address{1})}}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Sport rewards",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{}},
+			new:    &ChainConfig{Sport: &SportConfig{FullnodeContract: &common.Address{1}}},
+			head:   1,
+			wantErr: &ConfigCompatError{
+				What:         "Sport fullnode contract",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    big.NewInt(0),
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{EpochTransitionsBlock: big.NewInt(30)}},
+			new:    &ChainConfig{Sport: &SportConfig{}},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "Sport epoch transitions block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    nil,
+				RewindTo:     29,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{}},
+			new:    &ChainConfig{Sport: &SportConfig{MisbehaviourEvictionBlock: big.NewInt(30)}},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "Sport misbehaviour eviction block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(30),
+				RewindTo:     29,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{EmptyBlocks: &SportEmptyBlocks{Block: big.NewInt(10), Period: 30}}},
+			new:    &ChainConfig{Sport: &SportConfig{EmptyBlocks: &SportEmptyBlocks{Block: big.NewInt(10), Period: 60}}},
+			head:   20,
+			wantErr: &ConfigCompatError{
+				What:         "Sport empty block policy",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{PermissionsContract: &common.Address{1}},
+			head:   1,
+			wantErr: &ConfigCompatError{
+				What:         "permissions contract",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    big.NewInt(0),
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: &ChainConfig{Sport: &SportConfig{SpeakerPolicies: []*SportSpeakerPolicy{{Block: big.NewInt(10), Policy: SportSticky}}}},
+			new:    &ChainConfig{Sport: &SportConfig{SpeakerPolicies: []*SportSpeakerPolicy{{Block: big.NewInt(20), Policy: SportSticky}}}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Sport speaker policy",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -79,3 +175,96 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestSmiloPayCurves(t *testing.T) {
+	curves := SmiloPayCurves{DefaultSmiloPayCurve(big.NewInt(10)), DefaultSmiloPayCurve(big.NewInt(20))}
+	if err := curves.Validate(); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	for num, want := range map[int64]*SmiloPayCurve{0: nil, 9: nil, 10: curves[0], 19: curves[0], 20: curves[1], 100: curves[1]} {
+		if have := curves.At(big.NewInt(num)); have != want {
+			t.Errorf("block %d: curve mismatch: have %v, want %v", num, have, want)
+		}
+	}
+	unordered := SmiloPayCurves{curves[1], curves[0]}
+	if err := unordered.Validate(); err == nil {
+		t.Error("unordered schedule accepted")
+	}
+	broken := DefaultSmiloPayCurve(big.NewInt(0))
+	broken.MaxDivisor = 0
+	if err := (SmiloPayCurves{broken}).Validate(); err == nil {
+		t.Error("zero divisor accepted")
+	}
+}
+
+func TestSportRewards(t *testing.T) {
+	first, second := DefaultSportRewards(big.NewInt(0), common.Address{}), DefaultSportRewards(big.NewInt(50), common.Address{1})
+	config := &SportConfig{Rewards: []*SportRewards{first, second}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	if have := config.RewardsAt(big.NewInt(49)); have != first {
+		t.Errorf("rewards mismatch at block 49: have %v, want %v", have, first)
+	}
+	if have := config.RewardsAt(big.NewInt(50)); have != second {
+		t.Errorf("rewards mismatch at block 50: have %v, want %v", have, second)
+	}
+	if have, want := second.CommunityReward(second.BlockReward(big.NewInt(50))), big.NewInt(1e18); have.Cmp(want) != 0 {
+		t.Errorf("community reward mismatch: have %v, want %v", have, want)
+	}
+	config.Rewards = []*SportRewards{second, first}
+	if err := config.Validate(); err == nil {
+		t.Error("unordered schedule accepted")
+	}
+	first.Tiers[1].Until = first.Tiers[0].Until
+	if err := (&SportConfig{Rewards: []*SportRewards{first}}).Validate(); err == nil {
+		t.Error("unordered tiers accepted")
+	}
+}
+
+func TestSportSpeakerPolicies(t *testing.T) {
+	sticky := &SportSpeakerPolicy{Block: big.NewInt(10), Policy: SportSticky}
+	weighted := &SportSpeakerPolicy{Block: big.NewInt(20), Policy: SportWeighted, Weights: map[common.Address]uint64{{1}: 3}}
+	config := &SportConfig{SpeakerPolicies: []*SportSpeakerPolicy{sticky, weighted}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	for num, want := range map[int64]*SportSpeakerPolicy{0: nil, 9: nil, 10: sticky, 19: sticky, 20: weighted, 100: weighted} {
+		if have := config.SpeakerPolicyAt(big.NewInt(num)); have != want {
+			t.Errorf("block %d: speaker policy mismatch: have %v, want %v", num, have, want)
+		}
+	}
+	config.SpeakerPolicies = []*SportSpeakerPolicy{weighted, sticky}
+	if err := config.Validate(); err == nil {
+		t.Error("unordered schedule accepted")
+	}
+	for i, policy := range []*SportSpeakerPolicy{
+		{Block: big.NewInt(0), Policy: SportWeighted + 1},
+		{Block: big.NewInt(0), Policy: SportSticky, Weights: map[common.Address]uint64{{1}: 1}},
+		{Block: big.NewInt(0), Policy: SportWeighted, Weights: map[common.Address]uint64{{1}: 0}},
+	} {
+		if err := (&SportConfig{SpeakerPolicies: []*SportSpeakerPolicy{policy}}).Validate(); err == nil {
+			t.Errorf("invalid policy %d accepted", i)
+		}
+	}
+}
+
+func TestSportEmptyBlocks(t *testing.T) {
+	config := &SportConfig{EmptyBlocks: &SportEmptyBlocks{Block: big.NewInt(10), Period: 30}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("valid policy rejected: %v", err)
+	}
+	for num, want := range map[int64]uint64{0: 0, 9: 0, 10: 30, 100: 30} {
+		if have := config.EmptyBlockPeriod(big.NewInt(num)); have != want {
+			t.Errorf("block %d: empty block period mismatch: have %d, want %d", num, have, want)
+		}
+	}
+	if period := (*SportConfig)(nil).EmptyBlockPeriod(big.NewInt(10)); period != 0 {
+		t.Errorf("empty block period without Sport: %d", period)
+	}
+	for i, policy := range []*SportEmptyBlocks{{Period: 30}, {Block: big.NewInt(10)}} {
+		if err := (&SportConfig{EmptyBlocks: policy}).Validate(); err == nil {
+			t.Errorf("invalid policy %d accepted", i)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// SmiloPayCurve defines how SmiloPay regenerates from the block it is activated
+// at. Both the regeneration speed and the cap grow with the square root of the
+// balance, counted in whole Smilo:
+//
+//	speed = SpeedBase + √balance * SpeedFactor / SpeedDivisor   (wei per block)
+//	max   = MaxBase   + √balance * MaxFactor   / MaxDivisor     (wei)
+type SmiloPayCurve struct {
+	Block *big.Int `json:"block"` // Block the curve is activated at
+
+	SpeedBase    *big.Int `json:"speedBase"`    // Wei regenerated per block regardless of the balance
+	SpeedFactor  *big.Int `json:"speedFactor"`  // Wei regenerated per block and √Smilo, before division
+	SpeedDivisor uint64   `json:"speedDivisor"` // Divisor applied to the balance dependent speed
+
+	MaxBase    *big.Int `json:"maxBase"`    // Cap regardless of the balance, in wei
+	MaxFactor  *big.Int `json:"maxFactor"`  // Cap per √Smilo in wei, before division
+	MaxDivisor uint64   `json:"maxDivisor"` // Divisor applied to the balance dependent cap
+}
+
+// DefaultSmiloPayCurve returns a curve activated at block with the parameters
+// SmiloPay has always been computed with.
+func DefaultSmiloPayCurve(block *big.Int) *SmiloPayCurve {
+	return &SmiloPayCurve{
+		Block:        block,
+		SpeedBase:    big.NewInt(5e11),
+		SpeedFactor:  big.NewInt(5e17),
+		SpeedDivisor: 750000,
+		MaxBase:      big.NewInt(5e15),
+		MaxFactor:    big.NewInt(5e18),
+		MaxDivisor:   50000,
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (c *SmiloPayCurve) String() string {
+	return fmt.Sprintf("{Block: %v Speed: %v+√b*%v/%d Max: %v+√b*%v/%d}",
+		c.Block, c.SpeedBase, c.SpeedFactor, c.SpeedDivisor, c.MaxBase, c.MaxFactor, c.MaxDivisor)
+}
+
+// equal reports whether two curves define the same schedule entry.
+func (c *SmiloPayCurve) equal(o *SmiloPayCurve) bool {
+	return configNumEqual(c.Block, o.Block) &&
+		configNumEqual(c.SpeedBase, o.SpeedBase) && configNumEqual(c.SpeedFactor, o.SpeedFactor) && c.SpeedDivisor == o.SpeedDivisor &&
+		configNumEqual(c.MaxBase, o.MaxBase) && configNumEqual(c.MaxFactor, o.MaxFactor) && c.MaxDivisor == o.MaxDivisor
+}
+
+// SmiloPayCurves is the schedule of SmiloPay curves of a chain, ordered by
+// activation block.
+type SmiloPayCurves []*SmiloPayCurve
+
+// At returns the curve active at block num, or nil if none is scheduled yet, in
+// which case the legacy floating point computation applies.
+func (s SmiloPayCurves) At(num *big.Int) *SmiloPayCurve {
+	for i := len(s) - 1; i >= 0; i-- {
+		if isForked(s[i].Block, num) {
+			return s[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that every curve is complete and that the schedule is
+// strictly ordered by activation block.
+func (s SmiloPayCurves) Validate() error {
+	for i, c := range s {
+		if c.Block == nil || c.SpeedBase == nil || c.SpeedFactor == nil || c.MaxBase == nil || c.MaxFactor == nil {
+			return fmt.Errorf("smiloPay curve %d is missing parameters", i)
+		}
+		if c.SpeedBase.Sign() < 0 || c.SpeedFactor.Sign() < 0 || c.MaxBase.Sign() < 0 || c.MaxFactor.Sign() < 0 {
+			return fmt.Errorf("smiloPay curve %d has negative parameters", i)
+		}
+		if c.SpeedDivisor == 0 || c.MaxDivisor == 0 {
+			return fmt.Errorf("smiloPay curve %d has a zero divisor", i)
+		}
+		if i > 0 && s[i-1].Block.Cmp(c.Block) >= 0 {
+			return fmt.Errorf("smiloPay curve %d at block %v is not scheduled after block %v", i, c.Block, s[i-1].Block)
+		}
+	}
+	return nil
+}
+
+// firstDifference returns the activation blocks of the first curves differing
+// between the stored and the new schedule. differ is false if both are equal.
+func (s SmiloPayCurves) firstDifference(o SmiloPayCurves) (storedBlock, newBlock *big.Int, differ bool) {
+	for i := 0; i < len(s) || i < len(o); i++ {
+		switch {
+		case i >= len(s):
+			return nil, o[i].Block, true
+		case i >= len(o):
+			return s[i].Block, nil, true
+		case !s[i].equal(o[i]):
+			return s[i].Block, o[i].Block, true
+		}
+	}
+	return nil, nil, false
+}
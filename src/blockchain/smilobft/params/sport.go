@@ -0,0 +1,370 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SportRewardTier is the block reward paid to the block author for every block
+// below Until.
+type SportRewardTier struct {
+	Until  *big.Int `json:"until"`  // First block no longer paying Reward
+	Reward *big.Int `json:"reward"` // Block reward in wei
+}
+
+// SportRewards defines the block rewards of a Sport chain from the block it is
+// activated at. On top of the block reward, CommunityShare percent of it is
+// minted to CommunityAddress. Blocks before the first rewards are activated pay
+// the legacy rewards to the community address configured by each node.
+type SportRewards struct {
+	Block            *big.Int          `json:"block"`                      // Block the rewards are activated at
+	Tiers            []SportRewardTier `json:"tiers"`                      // Reward tiers, ordered by Until
+	CommunityShare   uint64            `json:"communityShare"`             // Percentage of the block reward minted to the community
+	CommunityAddress common.Address    `json:"communityAddress,omitempty"` // Recipient of the community share
+}
+
+// DefaultSportRewards returns rewards activated at block following the reward
+// tiers Sport chains were launched with. If a community address is given, a
+// quarter of the block reward is minted to it.
+func DefaultSportRewards(block *big.Int, community common.Address) *SportRewards {
+	smilo := func(milli int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(milli), big.NewInt(1e15))
+	}
+	rewards := &SportRewards{
+		Block: block,
+		Tiers: []SportRewardTier{
+			{Until: big.NewInt(20000000), Reward: smilo(4000)},  // 4 smilo
+			{Until: big.NewInt(40000000), Reward: smilo(2000)},  // 2 smilo
+			{Until: big.NewInt(60000000), Reward: smilo(1750)},  // 1.75 smilo
+			{Until: big.NewInt(80000000), Reward: smilo(1500)},  // 1.5 smilo
+			{Until: big.NewInt(100000000), Reward: smilo(1250)}, // 1.25 smilo
+			{Until: big.NewInt(120000000), Reward: smilo(1000)}, // 1 smilo
+			{Until: big.NewInt(140000000), Reward: smilo(800)},  // 0.8 smilo
+			{Until: big.NewInt(160000000), Reward: smilo(600)},  // 0.6 smilo
+			{Until: big.NewInt(180000000), Reward: smilo(400)},  // 0.4 smilo
+			{Until: big.NewInt(200000000), Reward: smilo(200)},  // 0.2 smilo
+			{Until: big.NewInt(400000000), Reward: smilo(100)},  // 0.1 smilo
+			{Until: big.NewInt(800000000), Reward: smilo(50)},   // 0.05 smilo
+			{Until: big.NewInt(1600000000), Reward: smilo(25)},  // 0.025 smilo
+		},
+	}
+	if community != (common.Address{}) {
+		rewards.CommunityShare = 25
+		rewards.CommunityAddress = community
+	}
+	return rewards
+}
+
+// BlockReward returns the reward of the author of block num, zero once the last
+// tier is over.
+func (r *SportRewards) BlockReward(num *big.Int) *big.Int {
+	for _, tier := range r.Tiers {
+		if num.Cmp(tier.Until) < 0 {
+			return new(big.Int).Set(tier.Reward)
+		}
+	}
+	return new(big.Int)
+}
+
+// CommunityReward returns the community share of the given block reward.
+func (r *SportRewards) CommunityReward(blockReward *big.Int) *big.Int {
+	reward := new(big.Int).Mul(blockReward, new(big.Int).SetUint64(r.CommunityShare))
+	return reward.Div(reward, big.NewInt(100))
+}
+
+// validate checks that the tiers are ordered and the community share is payable.
+func (r *SportRewards) validate() error {
+	if r.Block == nil {
+		return errors.New("missing activation block")
+	}
+	for i, tier := range r.Tiers {
+		if tier.Until == nil || tier.Reward == nil || tier.Reward.Sign() < 0 {
+			return fmt.Errorf("invalid reward tier %d", i)
+		}
+		if i > 0 && r.Tiers[i-1].Until.Cmp(tier.Until) >= 0 {
+			return fmt.Errorf("reward tier %d ending at block %v is not ordered after block %v", i, tier.Until, r.Tiers[i-1].Until)
+		}
+	}
+	if r.CommunityShare > 100 {
+		return fmt.Errorf("community share of %d%% exceeds 100%%", r.CommunityShare)
+	}
+	if r.CommunityShare > 0 && r.CommunityAddress == (common.Address{}) {
+		return fmt.Errorf("community share of %d%% without community address", r.CommunityShare)
+	}
+	return nil
+}
+
+// equal reports whether two reward definitions pay out the same.
+func (r *SportRewards) equal(o *SportRewards) bool {
+	if !configNumEqual(r.Block, o.Block) || len(r.Tiers) != len(o.Tiers) ||
+		r.CommunityShare != o.CommunityShare || r.CommunityAddress != o.CommunityAddress {
+		return false
+	}
+	for i := range r.Tiers {
+		if !configNumEqual(r.Tiers[i].Until, o.Tiers[i].Until) || !configNumEqual(r.Tiers[i].Reward, o.Tiers[i].Reward) {
+			return false
+		}
+	}
+	return true
+}
+
+// Speaker policies of Sport chains, the numbering follows sport.SpeakerPolicy.
+const (
+	SportRoundRobin uint64 = iota // The speaker rotates every block and round change
+	SportSticky                   // The speaker only rotates on round changes
+	SportWeighted                 // Fullnodes propose blocks in proportion to their weight
+)
+
+// SportSpeakerPolicy selects the speakers of a Sport chain from the block it is
+// activated at.
+type SportSpeakerPolicy struct {
+	Block   *big.Int                  `json:"block"`             // Block the policy is activated at
+	Policy  uint64                    `json:"policy"`            // Speaker policy
+	Weights map[common.Address]uint64 `json:"weights,omitempty"` // Proposal weights of the weighted policy, 1 if not listed
+}
+
+// validate checks the policy is known and its weights are usable.
+func (p *SportSpeakerPolicy) validate() error {
+	if p.Block == nil {
+		return errors.New("missing activation block")
+	}
+	if p.Policy > SportWeighted {
+		return fmt.Errorf("unknown speaker policy %d", p.Policy)
+	}
+	if len(p.Weights) > 0 && p.Policy != SportWeighted {
+		return fmt.Errorf("weights given to speaker policy %d", p.Policy)
+	}
+	for fullnode, weight := range p.Weights {
+		if weight == 0 {
+			return fmt.Errorf("zero weight for fullnode %x", fullnode)
+		}
+	}
+	return nil
+}
+
+// equal reports whether two policies select the same speakers.
+func (p *SportSpeakerPolicy) equal(o *SportSpeakerPolicy) bool {
+	if !configNumEqual(p.Block, o.Block) || p.Policy != o.Policy || len(p.Weights) != len(o.Weights) {
+		return false
+	}
+	for fullnode, weight := range p.Weights {
+		if o.Weights[fullnode] != weight {
+			return false
+		}
+	}
+	return true
+}
+
+// SportEmptyBlocks paces the blocks without transactions of a Sport chain from
+// the block it is activated at: an empty block is only sealed Period seconds
+// after its parent, so the chain keeps a heartbeat while idle without filling
+// up with empty blocks.
+type SportEmptyBlocks struct {
+	Block  *big.Int `json:"block"`  // Block the policy is activated at
+	Period uint64   `json:"period"` // Minimum seconds between an empty block and its parent
+}
+
+// validate checks the policy has an activation block and a period.
+func (e *SportEmptyBlocks) validate() error {
+	if e.Block == nil {
+		return errors.New("missing activation block")
+	}
+	if e.Period == 0 {
+		return errors.New("zero empty block period")
+	}
+	return nil
+}
+
+// RewardsAt returns the rewards in effect at block num, or nil if the chain
+// does not define any yet.
+func (c *SportConfig) RewardsAt(num *big.Int) *SportRewards {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.Rewards) - 1; i >= 0; i-- {
+		if isForked(c.Rewards[i].Block, num) {
+			return c.Rewards[i]
+		}
+	}
+	return nil
+}
+
+// FullnodeRegistry returns the address of the contract governing the fullnode
+// set, or the zero address if fullnodes are voted in and out by the fullnodes.
+func (c *SportConfig) FullnodeRegistry() common.Address {
+	if c == nil || c.FullnodeContract == nil {
+		return common.Address{}
+	}
+	return *c.FullnodeContract
+}
+
+// SpeakerPolicyAt returns the speaker policy in effect at block num, or nil if
+// the chain keeps its SpeakerPolicy.
+func (c *SportConfig) SpeakerPolicyAt(num *big.Int) *SportSpeakerPolicy {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.SpeakerPolicies) - 1; i >= 0; i-- {
+		if isForked(c.SpeakerPolicies[i].Block, num) {
+			return c.SpeakerPolicies[i]
+		}
+	}
+	return nil
+}
+
+// EpochTransitions returns the block from which fullnode set changes are applied
+// at epoch checkpoints only, or nil if they are applied as soon as voted.
+func (c *SportConfig) EpochTransitions() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.EpochTransitionsBlock
+}
+
+// IsEpochTransitions returns whether fullnode set changes wait for the next epoch
+// checkpoint at block num.
+func (c *SportConfig) IsEpochTransitions(num *big.Int) bool {
+	return isForked(c.EpochTransitions(), num)
+}
+
+// MisbehaviourEviction returns the block from which blocks may carry evidence of
+// misbehaving fullnodes, evicting them, or nil if they never do.
+func (c *SportConfig) MisbehaviourEviction() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.MisbehaviourEvictionBlock
+}
+
+// IsMisbehaviourEviction returns whether block num may evict misbehaving fullnodes.
+func (c *SportConfig) IsMisbehaviourEviction(num *big.Int) bool {
+	return isForked(c.MisbehaviourEviction(), num)
+}
+
+// EmptyBlockPeriod returns the minimum number of seconds between an empty block
+// num and its parent, 0 if empty blocks are sealed like any other block.
+func (c *SportConfig) EmptyBlockPeriod(num *big.Int) uint64 {
+	if c == nil || c.EmptyBlocks == nil || !isForked(c.EmptyBlocks.Block, num) {
+		return 0
+	}
+	return c.EmptyBlocks.Period
+}
+
+// Validate checks the reward and speaker policy schedules are well formed and
+// ordered by activation block.
+func (c *SportConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for i, rewards := range c.Rewards {
+		if err := rewards.validate(); err != nil {
+			return fmt.Errorf("sport rewards %d: %v", i, err)
+		}
+		if i > 0 && c.Rewards[i-1].Block.Cmp(rewards.Block) >= 0 {
+			return fmt.Errorf("sport rewards %d at block %v are not scheduled after block %v", i, rewards.Block, c.Rewards[i-1].Block)
+		}
+	}
+	for i, policy := range c.SpeakerPolicies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("sport speaker policy %d: %v", i, err)
+		}
+		if i > 0 && c.SpeakerPolicies[i-1].Block.Cmp(policy.Block) >= 0 {
+			return fmt.Errorf("sport speaker policy %d at block %v is not scheduled after block %v", i, policy.Block, c.SpeakerPolicies[i-1].Block)
+		}
+	}
+	if c.EmptyBlocks != nil {
+		if err := c.EmptyBlocks.validate(); err != nil {
+			return fmt.Errorf("sport empty blocks: %v", err)
+		}
+	}
+	return nil
+}
+
+// rewardsFirstDifference returns the activation blocks of the first rewards
+// differing between the stored and the new config. differ is false if both
+// schedules are equal.
+func (c *SportConfig) rewardsFirstDifference(o *SportConfig) (storedBlock, newBlock *big.Int, differ bool) {
+	var stored, updated []*SportRewards
+	if c != nil {
+		stored = c.Rewards
+	}
+	if o != nil {
+		updated = o.Rewards
+	}
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		switch {
+		case i >= len(stored):
+			return nil, updated[i].Block, true
+		case i >= len(updated):
+			return stored[i].Block, nil, true
+		case !stored[i].equal(updated[i]):
+			return stored[i].Block, updated[i].Block, true
+		}
+	}
+	return nil, nil, false
+}
+
+// emptyBlocksDifference returns the activation blocks of the stored and the new
+// empty block policies. differ is false if both are equal.
+func (c *SportConfig) emptyBlocksDifference(o *SportConfig) (storedBlock, newBlock *big.Int, differ bool) {
+	var stored, updated *SportEmptyBlocks
+	if c != nil {
+		stored = c.EmptyBlocks
+	}
+	if o != nil {
+		updated = o.EmptyBlocks
+	}
+	if stored != nil {
+		storedBlock = stored.Block
+	}
+	if updated != nil {
+		newBlock = updated.Block
+	}
+	if stored == nil || updated == nil {
+		return storedBlock, newBlock, stored != updated
+	}
+	return storedBlock, newBlock, !configNumEqual(stored.Block, updated.Block) || stored.Period != updated.Period
+}
+
+// speakerPoliciesFirstDifference returns the activation blocks of the first
+// speaker policies differing between the stored and the new config. differ is
+// false if both schedules are equal.
+func (c *SportConfig) speakerPoliciesFirstDifference(o *SportConfig) (storedBlock, newBlock *big.Int, differ bool) {
+	var stored, updated []*SportSpeakerPolicy
+	if c != nil {
+		stored = c.SpeakerPolicies
+	}
+	if o != nil {
+		updated = o.SpeakerPolicies
+	}
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		switch {
+		case i >= len(stored):
+			return nil, updated[i].Block, true
+		case i >= len(updated):
+			return stored[i].Block, nil, true
+		case !stored[i].equal(updated[i]):
+			return stored[i].Block, updated[i].Block, true
+		}
+	}
+	return nil, nil, false
+}
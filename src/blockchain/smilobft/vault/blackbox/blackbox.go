@@ -52,29 +52,100 @@ func (b *Blackbox) PostRawTransaction(data []byte, to []string) (out []byte, err
 	return out, nil
 }
 
+// Get returns the payload stored under the given digest. A nil payload without
+// error means this node is not a recipient of the payload, an error means the
+// blackbox could not be asked and the caller must not assume either. A node
+// running without a blackbox gets ErrBlackboxNotInUse, so it can't be mistaken
+// for a reachable one.
 func (b *Blackbox) Get(data []byte) ([]byte, error) {
-	if b == nil || b.isBlackboxNotInUse {
+	if b == nil {
 		log.Error("Could not start Blackbox, Get ", "error", ErrBlackboxIsNotStarted)
 		return nil, ErrBlackboxIsNotStarted
 	}
+	if b.isBlackboxNotInUse {
+		return nil, ErrBlackboxNotInUse
+	}
 	if len(data) == 0 {
-		return data, nil
+		return nil, nil
 	}
-	// Ignore this error since not being a recipient of
-	// a payload isn't an error.
-	// TODO: Return an error if it's anything OTHER than
-	// 'you are not a recipient.'
 	dataStr := string(data)
 	x, found := b.cache.Get(dataStr)
 	if found {
 		return x.([]byte), nil
 	}
-	pl, _ := b.node.GetData(data)
+	pl, err := b.node.GetData(data)
+	if err == ErrNotRecipient {
+		pl, err = nil, nil
+	}
+	if err != nil {
+		log.Error("Could not Get from Blackbox, GetData, ", "error", err)
+		return nil, err
+	}
 	b.cache.Set(dataStr, pl, cache.DefaultExpiration)
 	return pl, nil
 }
 
-func New(path string) (*Blackbox, error) {
+// PostRawMetadata posts a payload together with its privacy metadata.
+func (b *Blackbox) PostRawMetadata(data []byte, metadata []byte, from string, to []string) (out []byte, err error) {
+	if b == nil || b.isBlackboxNotInUse {
+		log.Error("Could not start Blackbox, Post, PostDataRawMetadata, ", "b", b, "error", ErrBlackboxIsNotStarted)
+		return nil, ErrBlackboxIsNotStarted
+	}
+	out, err = b.node.PostDataRawMetadata(data, metadata, from, to)
+	if err != nil {
+		log.Error("Could not Post to Blackbox, Post, PostDataRawMetadata, ", "error", err)
+		return nil, err
+	}
+	return out, nil
+}
+
+// receivedPayload is a payload retrieved with its metadata and recipients.
+type receivedPayload struct {
+	data       []byte
+	metadata   []byte
+	recipients []string
+}
+
+// GetMetadata returns the payload stored under the given digest together with
+// its privacy metadata and the recipients it was encrypted for. Like Get, a
+// nil payload without error means this node is not a recipient.
+func (b *Blackbox) GetMetadata(data []byte) ([]byte, []byte, []string, error) {
+	if b == nil {
+		log.Error("Could not start Blackbox, GetMetadata ", "error", ErrBlackboxIsNotStarted)
+		return nil, nil, nil, ErrBlackboxIsNotStarted
+	}
+	if b.isBlackboxNotInUse {
+		return nil, nil, nil, ErrBlackboxNotInUse
+	}
+	if len(data) == 0 {
+		return nil, nil, nil, nil
+	}
+	key := "metadata:" + string(data)
+	if x, found := b.cache.Get(key); found {
+		p := x.(*receivedPayload)
+		return p.data, p.metadata, p.recipients, nil
+	}
+	pl, metadata, recipients, err := b.node.GetDataMetadata(data)
+	if err == ErrNotRecipient {
+		pl, metadata, recipients, err = nil, nil, nil, nil
+	}
+	if err != nil {
+		log.Error("Could not Get from Blackbox, GetDataMetadata, ", "error", err)
+		return nil, nil, nil, err
+	}
+	b.cache.Set(key, &receivedPayload{data: pl, metadata: metadata, recipients: recipients}, cache.DefaultExpiration)
+	return pl, metadata, recipients, nil
+}
+
+// Health reports the connectivity of the blackbox client.
+func (b *Blackbox) Health() *Health {
+	if b == nil || b.isBlackboxNotInUse {
+		return &Health{}
+	}
+	return b.node.Health()
+}
+
+func New(path string, config *ClientConfig) (*Blackbox, error) {
 	info, err := os.Lstat(path)
 	if err != nil {
 		log.Error("Could not start Blackbox, New, os.Lstat ", "path", path, "error", err)
@@ -96,7 +167,7 @@ func New(path string) (*Blackbox, error) {
 		log.Error("Could not start Blackbox, New, RunNode, ", "path", path, "error", err)
 		return nil, err
 	}
-	n, err := CreateClient(path)
+	n, err := CreateClient(path, config)
 	if err != nil {
 		log.Error("Could not start Blackbox, New, CreateClient, ", "path", path, "error", err)
 		return nil, err
@@ -109,6 +180,10 @@ func New(path string) (*Blackbox, error) {
 }
 
 func CreateNew(path string) *Blackbox {
+	return CreateNewWithConfig(path, &DefaultClientConfig)
+}
+
+func CreateNewWithConfig(path string, config *ClientConfig) *Blackbox {
 	log.Debug("############################## Connecting to BlackBox, CreateNew, ", "path", path)
 	if strings.EqualFold(path, "ignore") {
 		return &Blackbox{
@@ -117,7 +192,7 @@ func CreateNew(path string) *Blackbox {
 			isBlackboxNotInUse: true,
 		}
 	}
-	b, err := New(path)
+	b, err := New(path, config)
 	if err != nil || b == nil {
 		log.Error("############################## ERROR: Failed to connect to BlackBox, CreateNew, ", "path", path, "error", err)
 	}
@@ -0,0 +1,31 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package blackbox
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	requestMeter       = metrics.NewRegisteredMeter("vault/blackbox/requests", nil)
+	errorMeter         = metrics.NewRegisteredMeter("vault/blackbox/errors", nil)
+	retryMeter         = metrics.NewRegisteredMeter("vault/blackbox/retries", nil)
+	notRecipientMeter  = metrics.NewRegisteredMeter("vault/blackbox/notrecipient", nil)
+	breakerOpenMeter   = metrics.NewRegisteredMeter("vault/blackbox/breaker/open", nil)
+	breakerRejectMeter = metrics.NewRegisteredMeter("vault/blackbox/breaker/rejected", nil)
+	latencyTimer       = metrics.NewRegisteredTimer("vault/blackbox/latency", nil)
+)
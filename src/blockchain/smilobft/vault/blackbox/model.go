@@ -20,6 +20,8 @@ package blackbox
 import (
 	"errors"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/patrickmn/go-cache"
@@ -27,6 +29,9 @@ import (
 
 var (
 	ErrBlackboxIsNotStarted = errors.New("blackbox is not started")
+	ErrBlackboxNotInUse     = errors.New("blackbox is not in use")
+	ErrNotRecipient         = errors.New("not a recipient of the payload")
+	ErrCircuitOpen          = errors.New("blackbox circuit breaker is open")
 )
 
 // --------------------------------------------------------------------
@@ -39,16 +44,67 @@ type Blackbox struct {
 
 // --------------------------------------------------------------------
 
+// ClientConfig tunes the timeouts, retries and circuit breaker of a Client.
+type ClientConfig struct {
+	DialTimeout      time.Duration // Timeout for connecting to the blackbox socket
+	RequestTimeout   time.Duration // Timeout for a single request, including the response body
+	MaxRetries       int           // Number of times a failed request is retried
+	RetryBackoff     time.Duration // Delay before the first retry, doubled on every further retry
+	MaxRetryBackoff  time.Duration // Upper bound of the delay between retries
+	BreakerThreshold int           // Consecutive failed requests after which the circuit breaker opens
+	BreakerCooldown  time.Duration // Time the circuit breaker stays open before letting a request through
+}
+
+var DefaultClientConfig = ClientConfig{
+	DialTimeout:      1 * time.Second,
+	RequestTimeout:   5 * time.Second,
+	MaxRetries:       4,
+	RetryBackoff:     200 * time.Millisecond,
+	MaxRetryBackoff:  3 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  10 * time.Second,
+}
+
 type Client struct {
 	httpClient *http.Client
+	config     ClientConfig
+
+	lock        sync.Mutex
+	failures    int       // Consecutive failed requests
+	openUntil   time.Time // Time until which the circuit breaker rejects requests
+	lastError   error
+	lastSuccess time.Time
+	lastFailure time.Time
+	latency     time.Duration // Latency of the last successful request
+
+	requests      uint64
+	errors        uint64
+	retries       uint64
+	notRecipients uint64
 }
 
-func CreateClient(socketPath string) (*Client, error) {
+func CreateClient(socketPath string, config *ClientConfig) (*Client, error) {
 	return &Client{
-		httpClient: unixClient(socketPath),
+		httpClient: unixClient(socketPath, config),
+		config:     *config,
 	}, nil
 }
 
+// Health is a snapshot of the connectivity of a Client.
+type Health struct {
+	Connected           bool          `json:"connected"`
+	BreakerOpen         bool          `json:"breakerOpen"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	LastError           string        `json:"lastError,omitempty"`
+	LastSuccess         time.Time     `json:"lastSuccess"`
+	LastFailure         time.Time     `json:"lastFailure"`
+	Latency             time.Duration `json:"latency"`
+	Requests            uint64        `json:"requests"`
+	Errors              uint64        `json:"errors"`
+	Retries             uint64        `json:"retries"`
+	NotRecipient        uint64        `json:"notRecipient"`
+}
+
 // --------------------------------------------------------------------
 
 type Config struct {
@@ -25,33 +25,47 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/tv42/httpunix"
 )
 
-func unixTransport(socketPath string) *httpunix.Transport {
+// statusError is returned for blackbox responses other than 200 OK.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("non-200 status code: %s", e.status)
+}
+
+func unixTransport(socketPath string, config *ClientConfig) *httpunix.Transport {
 	t := &httpunix.Transport{
-		DialTimeout:           1 * time.Second,
-		RequestTimeout:        5 * time.Second,
-		ResponseHeaderTimeout: 5 * time.Second,
+		DialTimeout:           config.DialTimeout,
+		RequestTimeout:        config.RequestTimeout,
+		ResponseHeaderTimeout: config.RequestTimeout,
 	}
 	t.RegisterLocation("blackbox", socketPath)
 	return t
 }
 
-func unixClient(socketPath string) *http.Client {
+func unixClient(socketPath string, config *ClientConfig) *http.Client {
 	return &http.Client{
-		Transport: unixTransport(socketPath),
+		Transport: unixTransport(socketPath, config),
+		Timeout:   config.RequestTimeout,
 	}
 }
 
 func RunNode(socketPath string) error {
-	c := unixClient(socketPath)
+	c := unixClient(socketPath, &DefaultClientConfig)
 	res, err := c.Get("http+unix://blackbox/upcheck")
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 	if res.StatusCode == 200 {
 		return nil
 	}
@@ -59,72 +73,209 @@ func RunNode(socketPath string) error {
 }
 
 func (c *Client) PostDataRaw(pl []byte, b64From string, b64To []string) ([]byte, error) {
-	buf := bytes.NewBuffer([]byte(base64.StdEncoding.EncodeToString(pl)))
-	req, err := http.NewRequest("POST", "http+unix://blackbox/sendraw", buf)
-	if err != nil {
-		return nil, err
+	headers := map[string]string{
+		"bb0x-to":      strings.Join(b64To, ","),
+		"Content-Type": "application/octet-stream",
 	}
 	if b64From != "" {
-		req.Header.Set("bb0x-from", b64From)
+		headers["bb0x-from"] = b64From
 	}
-	req.Header.Set("bb0x-to", strings.Join(b64To, ","))
-	req.Header.Set("Content-Type", "application/octet-stream")
-	res, err := c.httpClient.Do(req)
+	data, _, err := c.do("POST", "http+unix://blackbox/sendraw", []byte(base64.StdEncoding.EncodeToString(pl)), headers)
+	return data, err
+}
 
-	if res != nil {
-		defer res.Body.Close()
+// PostDataRawMetadata posts a payload together with its privacy metadata, which
+// the blackbox stores under the digest of the payload and distributes to the
+// recipients alongside it.
+func (c *Client) PostDataRawMetadata(pl []byte, metadata []byte, b64From string, b64To []string) ([]byte, error) {
+	headers := map[string]string{
+		"bb0x-to":       strings.Join(b64To, ","),
+		"bb0x-metadata": base64.StdEncoding.EncodeToString(metadata),
+		"Content-Type":  "application/octet-stream",
 	}
-	if err != nil {
-		return nil, err
+	if b64From != "" {
+		headers["bb0x-from"] = b64From
 	}
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("non-200 status code: %+v", res)
+	data, _, err := c.do("POST", "http+unix://blackbox/sendraw", []byte(base64.StdEncoding.EncodeToString(pl)), headers)
+	return data, err
+}
+
+func (c *Client) PostDataRawTransaction(signedPayload []byte, b64To []string) ([]byte, error) {
+	headers := map[string]string{
+		"bb0x-to":      strings.Join(b64To, ","),
+		"Content-Type": "application/octet-stream",
 	}
+	data, _, err := c.do("POST", "http+unix://blackbox/sendsignedtx", []byte(base64.StdEncoding.EncodeToString(signedPayload)), headers)
+	return data, err
+}
 
-	return ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, res.Body))
+// GetData retrieves the payload stored under key. It returns ErrNotRecipient
+// if the blackbox does not hold the payload, any other error means the
+// blackbox could not be reached.
+func (c *Client) GetData(key []byte) ([]byte, error) {
+	data, _, err := c.receive(key)
+	return data, err
 }
 
-func (c *Client) PostDataRawTransaction(signedPayload []byte, b64To []string) ([]byte, error) {
-	buf := bytes.NewBuffer([]byte(base64.StdEncoding.EncodeToString(signedPayload)))
-	req, err := http.NewRequest("POST", "http+unix://blackbox/sendsignedtx", buf)
+// GetDataMetadata retrieves the payload stored under key together with its
+// privacy metadata and the keys of the recipients the blackbox encrypted it
+// for, the sender included. The metadata is nil for payloads posted without.
+func (c *Client) GetDataMetadata(key []byte) ([]byte, []byte, []string, error) {
+	data, header, err := c.receive(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+	var metadata []byte
+	if enc := header.Get("bb0x-metadata"); enc != "" {
+		if metadata, err = base64.StdEncoding.DecodeString(enc); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid payload metadata: %v", err)
+		}
+	}
+	var recipients []string
+	if keys := header.Get("bb0x-recipients"); keys != "" {
+		recipients = strings.Split(keys, ",")
 	}
+	return data, metadata, recipients, nil
+}
 
-	req.Header.Set("bb0x-to", strings.Join(b64To, ","))
-	req.Header.Set("Content-Type", "application/octet-stream")
-	res, err := c.httpClient.Do(req)
+func (c *Client) receive(key []byte) ([]byte, http.Header, error) {
+	headers := map[string]string{
+		"bb0x-key": base64.StdEncoding.EncodeToString(key),
+	}
+	data, header, err := c.do("GET", "http+unix://blackbox/receiveraw", nil, headers)
+	if err, ok := err.(*statusError); ok && err.code == http.StatusNotFound {
+		atomic.AddUint64(&c.notRecipients, 1)
+		notRecipientMeter.Mark(1)
+		return nil, nil, ErrNotRecipient
+	}
+	return data, header, err
+}
 
-	if res != nil {
-		defer res.Body.Close()
+// Health returns a snapshot of the client connectivity and counters.
+func (c *Client) Health() *Health {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h := &Health{
+		Connected:           c.failures == 0 && !c.lastSuccess.IsZero(),
+		BreakerOpen:         time.Now().Before(c.openUntil),
+		ConsecutiveFailures: c.failures,
+		LastSuccess:         c.lastSuccess,
+		LastFailure:         c.lastFailure,
+		Latency:             c.latency,
+		Requests:            atomic.LoadUint64(&c.requests),
+		Errors:              atomic.LoadUint64(&c.errors),
+		Retries:             atomic.LoadUint64(&c.retries),
+		NotRecipient:        atomic.LoadUint64(&c.notRecipients),
 	}
-	if err != nil {
-		return nil, err
+	if c.lastError != nil {
+		h.LastError = c.lastError.Error()
 	}
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("non-200 status code: %+v", res)
+	return h
+}
+
+// do sends a request to the blackbox, retrying transport failures and server
+// errors with exponential backoff. Requests are rejected without being sent
+// while the circuit breaker is open.
+func (c *Client) do(method, url string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	if err := c.allow(); err != nil {
+		return nil, nil, err
 	}
+	backoff := c.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		data, header, err := c.send(method, url, body, headers)
+		atomic.AddUint64(&c.requests, 1)
+		requestMeter.Mark(1)
+
+		if err == nil || !retryable(err) {
+			// The blackbox answered, even a client error proves it is up
+			c.success(time.Since(start))
+			return data, header, err
+		}
+		atomic.AddUint64(&c.errors, 1)
+		errorMeter.Mark(1)
 
-	return ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, res.Body))
+		if attempt >= c.config.MaxRetries {
+			c.failure(err)
+			return nil, nil, err
+		}
+		log.Debug("Retrying blackbox request", "url", url, "attempt", attempt+1, "backoff", backoff, "err", err)
+		atomic.AddUint64(&c.retries, 1)
+		retryMeter.Mark(1)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > c.config.MaxRetryBackoff {
+			backoff = c.config.MaxRetryBackoff
+		}
+	}
 }
 
-func (c *Client) GetData(key []byte) ([]byte, error) {
-	req, err := http.NewRequest("GET", "http+unix://blackbox/receiveraw", nil)
+func (c *Client) send(method, url string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
 	}
-	req.Header.Set("bb0x-key", base64.StdEncoding.EncodeToString(key))
 	res, err := c.httpClient.Do(req)
-
 	if res != nil {
 		defer res.Body.Close()
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("non-200 status code: %+v", res)
+		return nil, nil, &statusError{code: res.StatusCode, status: res.Status}
 	}
+	data, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, res.Body))
+	return data, res.Header, err
+}
+
+// allow returns ErrCircuitOpen if the circuit breaker is open.
+func (c *Client) allow() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	return ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, res.Body))
+	if time.Now().Before(c.openUntil) {
+		breakerRejectMeter.Mark(1)
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (c *Client) success(latency time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.failures >= c.config.BreakerThreshold {
+		log.Info("Blackbox connection restored", "failures", c.failures)
+	}
+	c.failures = 0
+	c.lastSuccess = time.Now()
+	c.latency = latency
+	latencyTimer.Update(latency)
+}
+
+func (c *Client) failure(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.failures++
+	c.lastError = err
+	c.lastFailure = time.Now()
+	if c.config.BreakerThreshold > 0 && c.failures >= c.config.BreakerThreshold {
+		log.Error("Blackbox unreachable, opening circuit breaker", "failures", c.failures, "cooldown", c.config.BreakerCooldown, "err", err)
+		c.openUntil = c.lastFailure.Add(c.config.BreakerCooldown)
+		breakerOpenMeter.Mark(1)
+	}
+}
+
+// retryable reports whether err is a transport failure or a server error, as
+// opposed to an answer of a healthy blackbox.
+func retryable(err error) bool {
+	if err, ok := err.(*statusError); ok {
+		return err.code >= 500
+	}
+	return true
 }
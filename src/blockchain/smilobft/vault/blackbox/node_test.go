@@ -0,0 +1,179 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package blackbox
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+var testClientConfig = ClientConfig{
+	DialTimeout:      100 * time.Millisecond,
+	RequestTimeout:   time.Second,
+	MaxRetries:       2,
+	RetryBackoff:     time.Millisecond,
+	MaxRetryBackoff:  time.Millisecond,
+	BreakerThreshold: 2,
+	BreakerCooldown:  time.Hour,
+}
+
+// startBlackbox serves handler on a unix socket, returning the socket path.
+func startBlackbox(t *testing.T, handler http.HandlerFunc) (string, func()) {
+	dir, err := ioutil.TempDir("", "blackbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "bb.ipc")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	return path, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGetDataRetries(t *testing.T) {
+	var calls int32
+	path, stop := startBlackbox(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("payload"))))
+	})
+	defer stop()
+
+	c, _ := CreateClient(path, &testClientConfig)
+	data, err := c.GetData([]byte{1})
+	if err != nil {
+		t.Fatalf("expected request to succeed after retries, got %v", err)
+	}
+	if string(data) != "payload" {
+		t.Fatalf("payload mismatch: have %q, want %q", data, "payload")
+	}
+	if h := c.Health(); !h.Connected || h.Retries != 2 || h.Errors != 2 {
+		t.Fatalf("unexpected health after retries: %+v", h)
+	}
+}
+
+func TestGetDataNotRecipient(t *testing.T) {
+	path, stop := startBlackbox(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer stop()
+
+	c, _ := CreateClient(path, &testClientConfig)
+	if _, err := c.GetData([]byte{1}); err != ErrNotRecipient {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrNotRecipient)
+	}
+	if h := c.Health(); !h.Connected || h.Retries != 0 || h.NotRecipient != 1 {
+		t.Fatalf("unexpected health after missing payload: %+v", h)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int32
+	path, stop := startBlackbox(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer stop()
+
+	c, _ := CreateClient(path, &testClientConfig)
+	for i := 0; i < testClientConfig.BreakerThreshold; i++ {
+		if _, err := c.GetData([]byte{1}); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("request %d: expected server error, got %v", i, err)
+		}
+	}
+	sent := atomic.LoadInt32(&calls)
+	if _, err := c.GetData([]byte{1}); err != ErrCircuitOpen {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrCircuitOpen)
+	}
+	if atomic.LoadInt32(&calls) != sent {
+		t.Fatalf("request sent while circuit breaker is open")
+	}
+	if h := c.Health(); h.Connected || !h.BreakerOpen {
+		t.Fatalf("unexpected health with open breaker: %+v", h)
+	}
+}
+
+func TestGetTransportFailure(t *testing.T) {
+	path, stop := startBlackbox(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	c, _ := CreateClient(path, &testClientConfig)
+	b := &Blackbox{node: c, cache: cache.New(time.Minute, time.Minute)}
+	stop()
+
+	if data, err := b.Get([]byte{1}); err == nil {
+		t.Fatalf("expected error from unreachable blackbox, got payload %x", data)
+	}
+}
+
+func TestDataMetadata(t *testing.T) {
+	var stored string
+	path, stop := startBlackbox(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sendraw":
+			stored = r.Header.Get("bb0x-metadata")
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("digest"))))
+		case "/receiveraw":
+			w.Header().Set("bb0x-metadata", stored)
+			w.Header().Set("bb0x-recipients", "A,B")
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("payload"))))
+		}
+	})
+	defer stop()
+
+	c, _ := CreateClient(path, &testClientConfig)
+	if _, err := c.PostDataRawMetadata([]byte("payload"), []byte("metadata"), "A", []string{"B"}); err != nil {
+		t.Fatalf("failed to post payload: %v", err)
+	}
+	data, metadata, recipients, err := c.GetDataMetadata([]byte("digest"))
+	if err != nil {
+		t.Fatalf("failed to get payload: %v", err)
+	}
+	if string(data) != "payload" || string(metadata) != "metadata" {
+		t.Fatalf("payload mismatch: have %q/%q, want %q/%q", data, metadata, "payload", "metadata")
+	}
+	if len(recipients) != 2 || recipients[0] != "A" || recipients[1] != "B" {
+		t.Fatalf("recipients mismatch: have %v, want [A B]", recipients)
+	}
+}
+
+func TestGetNotInUse(t *testing.T) {
+	b := CreateNew("ignore")
+	if _, err := b.Get([]byte{1}); err != ErrBlackboxNotInUse {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrBlackboxNotInUse)
+	}
+	if _, _, _, err := b.GetMetadata([]byte{1}); err != ErrBlackboxNotInUse {
+		t.Fatalf("metadata error mismatch: have %v, want %v", err, ErrBlackboxNotInUse)
+	}
+}
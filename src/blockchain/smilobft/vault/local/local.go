@@ -0,0 +1,204 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package local implements an in-process vault that keeps encrypted private
+// transaction payloads in a key-value store, so private transactions can be
+// processed without an external blackbox daemon.
+package local
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-didux/src/blockchain/smilobft/ethdb"
+)
+
+// DigestLength is the length of the payload digests handed out by the vault,
+// the same as the ones returned by the blackbox.
+const DigestLength = 64
+
+var (
+	keyKey         = []byte("vault-local-key") // keyKey tracks the symmetric key used to seal payloads
+	payloadPrefix  = []byte("vault-local-p")   // payloadPrefix + digest -> sealed payload
+	metadataPrefix = []byte("vault-local-m")   // metadataPrefix + digest -> payload metadata and recipients
+)
+
+var (
+	ErrInvalidKey     = errors.New("invalid vault key")
+	ErrInvalidPayload = errors.New("invalid sealed payload")
+)
+
+// Vault is an in-process BlackboxVault backed by a key-value store.
+type Vault struct {
+	db   ethdb.KeyValueStore
+	aead cipher.AEAD
+	lock sync.RWMutex
+}
+
+// New opens a local vault on db, generating and persisting its sealing key
+// on first use.
+func New(db ethdb.KeyValueStore) (*Vault, error) {
+	key, err := db.Get(keyKey)
+	if err != nil || len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		if err := db.Put(keyKey, key); err != nil {
+			return nil, err
+		}
+	}
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{db: db, aead: aead}, nil
+}
+
+// PostRaw seals and stores data, returning the digest that replaces the
+// payload in the transaction. The local vault is the only party, so from and
+// to are not used.
+func (v *Vault) PostRaw(data []byte, from string, to []string) ([]byte, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := v.aead.Seal(nonce, nonce, data, nil)
+	digest := crypto.Keccak512(sealed)
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.db.Put(payloadKey(digest), sealed); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// PostRawTransaction distributes a payload previously stored with PostRaw.
+// Payloads that are not known yet are stored first.
+// storedMetadata is the privacy metadata of a payload together with the keys
+// it was posted to, the sender included.
+type storedMetadata struct {
+	Metadata   []byte
+	Recipients []string
+}
+
+// PostRawMetadata stores a payload and records its privacy metadata under its
+// digest. All parties share the in-process vault, so the recipients are the
+// keys the payload was posted to.
+func (v *Vault) PostRawMetadata(data []byte, metadata []byte, from string, to []string) ([]byte, error) {
+	digest, err := v.PostRaw(data, from, to)
+	if err != nil {
+		return nil, err
+	}
+	recipients := append([]string{from}, to...)
+	enc, err := rlp.EncodeToBytes(&storedMetadata{Metadata: metadata, Recipients: recipients})
+	if err != nil {
+		return nil, err
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.db.Put(metadataKey(digest), enc); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+func (v *Vault) PostRawTransaction(data []byte, to []string) ([]byte, error) {
+	if len(data) == DigestLength {
+		v.lock.RLock()
+		ok, err := v.db.Has(payloadKey(data))
+		v.lock.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return data, nil
+		}
+	}
+	return v.PostRaw(data, "", to)
+}
+
+// Get returns the payload for the given digest, or nil if it is unknown to
+// this vault, i.e. the node is not a participant.
+func (v *Vault) Get(digest []byte) ([]byte, error) {
+	if len(digest) == 0 {
+		return digest, nil
+	}
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	if ok, err := v.db.Has(payloadKey(digest)); err != nil || !ok {
+		return nil, err
+	}
+	sealed, err := v.db.Get(payloadKey(digest))
+	if err != nil {
+		return nil, err
+	}
+	size := v.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, ErrInvalidPayload
+	}
+	return v.aead.Open(nil, sealed[:size], sealed[size:], nil)
+}
+
+// GetMetadata returns the payload stored under digest together with its
+// privacy metadata and recipients, which are nil for payloads posted without.
+func (v *Vault) GetMetadata(digest []byte) ([]byte, []byte, []string, error) {
+	data, err := v.Get(digest)
+	if err != nil || data == nil {
+		return data, nil, nil, err
+	}
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	if ok, err := v.db.Has(metadataKey(digest)); err != nil || !ok {
+		return data, nil, nil, err
+	}
+	enc, err := v.db.Get(metadataKey(digest))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var stored storedMetadata
+	if err := rlp.DecodeBytes(enc, &stored); err != nil {
+		return nil, nil, nil, err
+	}
+	return data, stored.Metadata, stored.Recipients, nil
+}
+
+func payloadKey(digest []byte) []byte {
+	return append(append([]byte{}, payloadPrefix...), digest...)
+}
+
+func metadataKey(digest []byte) []byte {
+	return append(append([]byte{}, metadataPrefix...), digest...)
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package local
+
+import (
+	"bytes"
+	"testing"
+
+	"go-didux/src/blockchain/smilobft/ethdb/memorydb"
+)
+
+func TestPostAndGet(t *testing.T) {
+	db := memorydb.New()
+	v, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte{0x60, 0x0a, 0x60, 0x00, 0x55}
+	digest, err := v.PostRaw(payload, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digest) != DigestLength {
+		t.Fatalf("digest length mismatch: have %d, want %d", len(digest), DigestLength)
+	}
+	sent, err := v.PostRawTransaction(digest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sent, digest) {
+		t.Fatalf("stored digest changed on distribution: have %x, want %x", sent, digest)
+	}
+
+	// A reopened vault must keep its key and payloads
+	v, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := v.Get(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, payload) {
+		t.Fatalf("payload mismatch: have %x, want %x", have, payload)
+	}
+}
+
+func TestGetUnknown(t *testing.T) {
+	v, err := New(memorydb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := v.Get(make([]byte, DigestLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != nil {
+		t.Fatalf("expected no payload for unknown digest, got %x", have)
+	}
+}
+
+func TestPostAndGetMetadata(t *testing.T) {
+	v, err := New(memorydb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte{0x60, 0x0a, 0x60, 0x00, 0x55}
+	digest, err := v.PostRawMetadata(payload, []byte("metadata"), "A", []string{"B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, metadata, recipients, err := v.GetMetadata(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, payload) || string(metadata) != "metadata" {
+		t.Fatalf("payload mismatch: have %x/%q, want %x/%q", data, metadata, payload, "metadata")
+	}
+	if len(recipients) != 2 || recipients[0] != "A" || recipients[1] != "B" {
+		t.Fatalf("recipients mismatch: have %v, want [A B]", recipients)
+	}
+	// Payloads posted without metadata must not report any
+	if digest, err = v.PostRaw(payload, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, metadata, recipients, err = v.GetMetadata(digest); err != nil || metadata != nil || recipients != nil {
+		t.Fatalf("unexpected metadata for plain payload: %q %v %v", metadata, recipients, err)
+	}
+}
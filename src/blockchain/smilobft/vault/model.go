@@ -17,8 +17,50 @@
 
 package vault
 
+import (
+	"go-didux/src/blockchain/smilobft/ethdb"
+	"go-didux/src/blockchain/smilobft/vault/blackbox"
+)
+
 type BlackboxVault interface {
 	PostRaw(data []byte, from string, to []string) ([]byte, error)
 	PostRawTransaction(data []byte, to []string) ([]byte, error)
 	Get(data []byte) ([]byte, error)
 }
+
+// MetadataVault is implemented by vaults that store privacy metadata next to a
+// payload, keyed by the payload digest, and report the recipients they actually
+// encrypted the payload for.
+type MetadataVault interface {
+	PostRawMetadata(data []byte, metadata []byte, from string, to []string) ([]byte, error)
+	GetMetadata(data []byte) (payload []byte, metadata []byte, recipients []string, err error)
+}
+
+// Driver creates a BlackboxVault from the node configuration.
+type Driver func(config *Config) (BlackboxVault, error)
+
+// Config selects and configures the private transaction manager used by the node.
+type Config struct {
+	Driver string `toml:",omitempty"` // Name of the registered vault driver, empty to use the VAULT_IPC environment variable
+	IPC    string `toml:",omitempty"` // Blackbox socket or blackbox configuration file, used by the "blackbox" driver
+
+	// Blackbox overrides the timeouts, retries and circuit breaker of the
+	// "blackbox" driver, if nil blackbox.DefaultClientConfig is used.
+	Blackbox *blackbox.ClientConfig `toml:",omitempty"`
+
+	// Database holds the encrypted payloads of in-process drivers, if nil an
+	// in-memory database is used.
+	Database ethdb.KeyValueStore `toml:"-"`
+}
+
+// Health reports the connectivity of the vault in use.
+type Health struct {
+	Driver  string `json:"driver"`
+	Enabled bool   `json:"enabled"`
+	*blackbox.Health
+}
+
+// healthReporter is implemented by vaults that talk to an external service.
+type healthReporter interface {
+	Health() *blackbox.Health
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// PrivacyFlag is the privacy mode of a vault transaction, selected with the
+// "restriction" field of the transaction arguments.
+type PrivacyFlag uint64
+
+const (
+	// PrivacyFlagStandardPrivate only hides the payload from non participants.
+	PrivacyFlagStandardPrivate PrivacyFlag = 0
+	// PrivacyFlagPartyProtection additionally restricts calls to the contracts
+	// created by the transaction to transactions with the same participants.
+	PrivacyFlagPartyProtection PrivacyFlag = 1
+	// PrivacyFlagStateValidation additionally rejects the transaction when the
+	// resulting state of the affected contracts differs from the one the sender
+	// simulated. It implies party protection.
+	PrivacyFlagStateValidation PrivacyFlag = 3
+)
+
+var (
+	ErrUnknownRestriction   = errors.New("unknown vault transaction restriction")
+	ErrPrivacyNoVaultFrom   = errors.New("vaultFrom is required for party protection and state validation")
+	ErrInvalidPrivacyHeader = errors.New("invalid privacy metadata")
+	ErrPrivacyParticipants  = errors.New("privacy metadata participants differ from the vault recipients")
+	ErrPrivacyUnsupported   = errors.New("vault driver does not support privacy metadata")
+)
+
+// ParsePrivacyFlag maps a transaction "restriction" to its privacy flag.
+func ParsePrivacyFlag(restriction string) (PrivacyFlag, error) {
+	switch strings.ToLower(restriction) {
+	case "", "restricted", "standard":
+		return PrivacyFlagStandardPrivate, nil
+	case "partyprotection", "party-protection":
+		return PrivacyFlagPartyProtection, nil
+	case "statevalidation", "state-validation":
+		return PrivacyFlagStateValidation, nil
+	}
+	return 0, fmt.Errorf("%v: %q", ErrUnknownRestriction, restriction)
+}
+
+// Has reports whether all bits of other are set in f.
+func (f PrivacyFlag) Has(other PrivacyFlag) bool {
+	return f&other == other
+}
+
+func (f PrivacyFlag) String() string {
+	switch f {
+	case PrivacyFlagStandardPrivate:
+		return "restricted"
+	case PrivacyFlagPartyProtection:
+		return "partyprotection"
+	case PrivacyFlagStateValidation:
+		return "statevalidation"
+	}
+	return fmt.Sprintf("PrivacyFlag(%d)", uint64(f))
+}
+
+// PrivacyMetadata is stored in the vault next to the payload of vault
+// transactions that are not standard private.
+type PrivacyMetadata struct {
+	Flag         PrivacyFlag
+	Participants []string    // Sorted vault public keys of the sender and recipients
+	StateRoot    common.Hash // Root of the affected contracts expected by the sender, state validation only
+}
+
+// NewPrivacyMetadata creates the metadata of a transaction sent from the vault
+// key from to the vault keys in to.
+func NewPrivacyMetadata(flag PrivacyFlag, from string, to []string) (*PrivacyMetadata, error) {
+	if from == "" {
+		return nil, ErrPrivacyNoVaultFrom
+	}
+	seen := map[string]bool{from: true}
+	participants := []string{from}
+	for _, key := range to {
+		if !seen[key] {
+			seen[key] = true
+			participants = append(participants, key)
+		}
+	}
+	sort.Strings(participants)
+	return &PrivacyMetadata{Flag: flag, Participants: participants}, nil
+}
+
+// Hash identifies the privacy flag and participant set of the metadata. It is
+// recorded for the contracts created under party protection, and calls are
+// only allowed from transactions with the same hash. Standard private
+// transactions hash to the zero hash.
+func (m *PrivacyMetadata) Hash() common.Hash {
+	if m == nil || m.Flag == PrivacyFlagStandardPrivate {
+		return common.Hash{}
+	}
+	enc, _ := rlp.EncodeToBytes([]interface{}{m.Flag, m.Participants})
+	return crypto.Keccak256Hash(enc)
+}
+
+// PostPayload posts data to the vault, with its privacy metadata stored next to
+// it under the digest of the payload. Standard private payloads are posted
+// without metadata.
+func PostPayload(v BlackboxVault, data []byte, meta *PrivacyMetadata, from string, to []string) ([]byte, error) {
+	if meta == nil || meta.Flag == PrivacyFlagStandardPrivate {
+		return v.PostRaw(data, from, to)
+	}
+	mv, ok := v.(MetadataVault)
+	if !ok {
+		return nil, ErrPrivacyUnsupported
+	}
+	enc, err := rlp.EncodeToBytes(meta)
+	if err != nil {
+		return nil, err
+	}
+	return mv.PostRawMetadata(data, enc, from, to)
+}
+
+// GetPayload retrieves a payload from the vault together with its privacy
+// metadata, which is nil for standard private payloads. The participants the
+// sender declared must be exactly the recipients the vault encrypted the
+// payload for, otherwise ErrPrivacyParticipants is returned with the payload.
+// Both errors reject the metadata, any other error means the vault could not
+// be asked.
+func GetPayload(v BlackboxVault, digest []byte) ([]byte, *PrivacyMetadata, error) {
+	mv, ok := v.(MetadataVault)
+	if !ok {
+		data, err := v.Get(digest)
+		return data, nil, err
+	}
+	data, enc, recipients, err := mv.GetMetadata(digest)
+	if err != nil || enc == nil {
+		return data, nil, err
+	}
+	meta := new(PrivacyMetadata)
+	if err := rlp.DecodeBytes(enc, meta); err != nil {
+		return data, nil, ErrInvalidPrivacyHeader
+	}
+	if !sameParticipants(meta.Participants, recipients) {
+		return data, nil, ErrPrivacyParticipants
+	}
+	return data, meta, nil
+}
+
+// sameParticipants reports whether the sorted participants hold exactly the
+// distinct recipients.
+func sameParticipants(participants []string, recipients []string) bool {
+	seen := make(map[string]bool, len(recipients))
+	for _, key := range recipients {
+		seen[key] = true
+	}
+	if len(seen) != len(participants) {
+		return false
+	}
+	for _, key := range participants {
+		if !seen[key] {
+			return false
+		}
+	}
+	return sort.StringsAreSorted(participants)
+}
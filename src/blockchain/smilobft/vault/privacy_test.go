@@ -0,0 +1,130 @@
+// Copyright 2019 The go-smilo Authors
+// This file is part of the go-smilo library.
+//
+// The go-smilo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-smilo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-smilo library. If not, see <http://www.gnu.org/licenses/>.
+
+package vault
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-didux/src/blockchain/smilobft/ethdb/memorydb"
+	"go-didux/src/blockchain/smilobft/vault/local"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	v, err := local.New(memorydb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte{0x60, 0x0a, 0x60, 0x00, 0x55}
+
+	// Standard private payloads are stored without metadata
+	digest, err := PostPayload(v, data, nil, "A", []string{"B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, meta, err := GetPayload(v, digest)
+	if err != nil || meta != nil || !bytes.Equal(dec, data) {
+		t.Fatalf("standard payload mismatch: %x %v %v", dec, meta, err)
+	}
+	// Payloads are never interpreted, whatever they start with
+	legacy := []byte{0xef, 'P', 'V', 0x01, 0x00}
+	if digest, err = v.PostRaw(legacy, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if dec, meta, err = GetPayload(v, digest); err != nil || meta != nil || !bytes.Equal(dec, legacy) {
+		t.Fatalf("legacy payload mismatch: %x %v %v", dec, meta, err)
+	}
+
+	want, err := NewPrivacyMetadata(PrivacyFlagStateValidation, "B", []string{"C", "A", "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.StateRoot = common.Hash{1}
+	if digest, err = PostPayload(v, data, want, "B", []string{"C", "A", "B"}); err != nil {
+		t.Fatal(err)
+	}
+	if dec, meta, err = GetPayload(v, digest); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, data) {
+		t.Errorf("data mismatch: have %x, want %x", dec, data)
+	}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("metadata mismatch: have %+v, want %+v", meta, want)
+	}
+	if !reflect.DeepEqual(meta.Participants, []string{"A", "B", "C"}) {
+		t.Errorf("participants not sorted and deduplicated: %v", meta.Participants)
+	}
+}
+
+func TestPayloadParticipants(t *testing.T) {
+	v, err := local.New(memorydb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := NewPrivacyMetadata(PrivacyFlagPartyProtection, "A", []string{"B"})
+
+	// Participants the payload wasn't sent to must be rejected
+	digest, err := PostPayload(v, []byte{1}, meta, "A", []string{"C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := GetPayload(v, digest); err != ErrPrivacyParticipants {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrPrivacyParticipants)
+	}
+	// As must recipients missing from the participants
+	if digest, err = PostPayload(v, []byte{1}, meta, "A", []string{"B", "C"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := GetPayload(v, digest); err != ErrPrivacyParticipants {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrPrivacyParticipants)
+	}
+	// Vaults without metadata support can't carry restricted payloads
+	if _, err := PostPayload(plainVault{v}, []byte{1}, meta, "A", []string{"B"}); err != ErrPrivacyUnsupported {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrPrivacyUnsupported)
+	}
+}
+
+// plainVault hides the metadata support of the wrapped vault.
+type plainVault struct {
+	BlackboxVault
+}
+
+func TestPrivacyHash(t *testing.T) {
+	std := &PrivacyMetadata{Flag: PrivacyFlagStandardPrivate, Participants: []string{"A"}}
+	if std.Hash() != (common.Hash{}) {
+		t.Errorf("standard private hash not zero")
+	}
+	pp, _ := NewPrivacyMetadata(PrivacyFlagPartyProtection, "A", []string{"B"})
+	sv, _ := NewPrivacyMetadata(PrivacyFlagStateValidation, "A", []string{"B"})
+	other, _ := NewPrivacyMetadata(PrivacyFlagPartyProtection, "B", []string{"A"})
+	if pp.Hash() == sv.Hash() {
+		t.Errorf("flags not part of the hash")
+	}
+	if pp.Hash() != other.Hash() {
+		t.Errorf("hash depends on the sender")
+	}
+	if _, err := NewPrivacyMetadata(PrivacyFlagPartyProtection, "", nil); err != ErrPrivacyNoVaultFrom {
+		t.Errorf("missing vaultFrom accepted: %v", err)
+	}
+	if _, err := ParsePrivacyFlag("bogus"); err == nil {
+		t.Errorf("unknown restriction accepted")
+	}
+}
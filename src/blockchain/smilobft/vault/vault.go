@@ -18,13 +18,141 @@
 package vault
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/log"
 
+	"go-didux/src/blockchain/smilobft/ethdb/memorydb"
 	"go-didux/src/blockchain/smilobft/vault/blackbox"
+	"go-didux/src/blockchain/smilobft/vault/local"
 )
 
+const (
+	// BlackboxDriver talks to an external blackbox daemon over its unix socket.
+	BlackboxDriver = "blackbox"
+	// LocalDriver keeps encrypted payloads in a database inside the node process.
+	LocalDriver = "local"
+)
+
+var (
+	ErrUnknownDriver = errors.New("unknown vault driver")
+	ErrNotConnected  = errors.New("vault driver could not connect")
+
+	// ErrNotInUse is returned when reading payloads from a blackbox vault
+	// disabled with the "ignore" path, the node is not party to any vault
+	// transaction.
+	ErrNotInUse = blackbox.ErrBlackboxNotInUse
+)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{
+		BlackboxDriver: newBlackboxVault,
+		LocalDriver:    newLocalVault,
+	}
+
+	// driverName is the driver VaultInstance was created with
+	driverName = BlackboxDriver
+)
+
+// Register makes a vault driver available by the provided name. If Register is
+// called twice with the same name or if driver is nil, it panics.
+func Register(name string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if driver == nil {
+		panic("vault: Register driver is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("vault: Register called twice for driver " + name)
+	}
+	drivers[name] = driver
+}
+
+// Drivers returns a sorted list of the names of the registered drivers.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the vault selected by config.Driver.
+func New(config *Config) (BlackboxVault, error) {
+	driversMu.RLock()
+	driver, ok := drivers[config.Driver]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%v: %q (available: %v)", ErrUnknownDriver, config.Driver, Drivers())
+	}
+	return driver(config)
+}
+
+// Setup replaces VaultInstance with the vault selected by config. An empty
+// driver name keeps the instance created from the VAULT_IPC environment variable.
+func Setup(config *Config) error {
+	if config.Driver == "" {
+		return nil
+	}
+	v, err := New(config)
+	if err != nil {
+		return err
+	}
+	log.Info("Vault driver selected", "driver", config.Driver)
+	VaultInstance = v
+	driverName = config.Driver
+	return nil
+}
+
+// Status reports the driver and connectivity of VaultInstance. In-process
+// vaults are always reported as connected.
+func Status() *Health {
+	h := &Health{Driver: driverName, Enabled: VaultInstance != nil}
+	if !h.Enabled {
+		return h
+	}
+	if r, ok := VaultInstance.(healthReporter); ok {
+		h.Health = r.Health()
+	} else {
+		h.Health = &blackbox.Health{Connected: true}
+	}
+	return h
+}
+
+func newBlackboxVault(config *Config) (BlackboxVault, error) {
+	path := config.IPC
+	if path == "" {
+		path = os.Getenv("VAULT_IPC")
+	}
+	clientConfig := config.Blackbox
+	if clientConfig == nil {
+		clientConfig = &blackbox.DefaultClientConfig
+	}
+	b := blackbox.CreateNewWithConfig(path, clientConfig)
+	if b == nil {
+		return nil, fmt.Errorf("%v: %s", ErrNotConnected, path)
+	}
+	return b, nil
+}
+
+func newLocalVault(config *Config) (BlackboxVault, error) {
+	db := config.Database
+	if db == nil {
+		db = memorydb.New()
+	}
+	return local.New(db)
+}
+
 func GetBlackboxVault(targetIPC string) BlackboxVault {
 	log.Debug("################ GetBlackboxVault, ", "targetIPC", targetIPC)
 	config := os.Getenv(targetIPC)